@@ -0,0 +1,336 @@
+// Package faultclient decorates NetSvc clients with configurable, seeded
+// fault injection for chaos testing retry and failover paths.
+package faultclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// Injector owns the fault rules and random source shared by the clients it
+// wraps. Rules can be toggled, added and removed while requests are running.
+type Injector struct {
+	mu       sync.Mutex
+	rng      *rand.Rand
+	rules    []FaultRule
+	injected map[string]int
+	enabled  bool
+}
+
+func NewInjector(cfg *FaultClientConfig) (*Injector, error) {
+	inj := &Injector{
+		rng:      rand.New(rand.NewPCG(cfg.Seed, cfg.Seed)),
+		injected: map[string]int{},
+		enabled:  true,
+	}
+	for _, rule := range cfg.Rules {
+		if err := inj.AddRule(rule); err != nil {
+			return nil, err
+		}
+	}
+	return inj, nil
+}
+
+// Wrap returns a client injecting this Injector's faults into inner.
+func (inj *Injector) Wrap(inner dto.NetClientInterface) *FaultClient {
+	return &FaultClient{inner: inner, inj: inj}
+}
+
+// AddRule appends a rule, replacing any rule with the same name.
+func (inj *Injector) AddRule(rule FaultRule) error {
+	if err := rule.validate(); err != nil {
+		return err
+	}
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+	for i := range inj.rules {
+		if inj.rules[i].Name == rule.Name {
+			inj.rules[i] = rule
+			return nil
+		}
+	}
+	inj.rules = append(inj.rules, rule)
+	return nil
+}
+
+// RemoveRule deletes a rule by name.
+func (inj *Injector) RemoveRule(name string) {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+	for i := range inj.rules {
+		if inj.rules[i].Name == name {
+			inj.rules = append(inj.rules[:i], inj.rules[i+1:]...)
+			return
+		}
+	}
+}
+
+// Enable turns on the named rule. With no names it turns injection back on
+// globally after Pause.
+func (inj *Injector) Enable(names ...string) {
+	inj.setDisabled(false, names)
+}
+
+// Disable turns off the named rules.
+func (inj *Injector) Disable(names ...string) {
+	inj.setDisabled(true, names)
+}
+
+// Pause stops all injection until Enable is called without names.
+func (inj *Injector) Pause() {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+	inj.enabled = false
+}
+
+func (inj *Injector) setDisabled(disabled bool, names []string) {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+	if len(names) == 0 {
+		inj.enabled = !disabled
+		return
+	}
+	for i := range inj.rules {
+		for _, name := range names {
+			if inj.rules[i].Name == name {
+				inj.rules[i].Disabled = disabled
+			}
+		}
+	}
+}
+
+// Stats returns how many times each rule has fired.
+func (inj *Injector) Stats() map[string]int {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+	out := make(map[string]int, len(inj.injected))
+	for k, v := range inj.injected {
+		out[k] = v
+	}
+	return out
+}
+
+// plannedFault is a rule selected for one request with its latency sampled.
+type plannedFault struct {
+	rule  FaultRule
+	delay time.Duration
+}
+
+// plan rolls every matching rule once, in rule order, so a given seed and
+// request sequence always yields the same faults.
+func (inj *Injector) plan(clientRef string, target *url.URL) []plannedFault {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+	if !inj.enabled {
+		return nil
+	}
+	var out []plannedFault
+	for i := range inj.rules {
+		rule := &inj.rules[i]
+		if rule.Disabled || !rule.matches(clientRef, target) {
+			continue
+		}
+		if rule.Limit > 0 && inj.injected[rule.Name] >= rule.Limit {
+			continue
+		}
+		if rule.Rate > 0 && rule.Rate < 1 && inj.rng.Float64() >= rule.Rate {
+			continue
+		}
+		inj.injected[rule.Name]++
+		out = append(out, plannedFault{rule: *rule, delay: rule.Latency.sample(inj.rng)})
+	}
+	return out
+}
+
+// FaultClient is a dto.NetClientInterface decorator created by Injector.Wrap.
+// Register it under the wrapped client's ref.
+type FaultClient struct {
+	inner dto.NetClientInterface
+	inj   *Injector
+}
+
+func (c *FaultClient) Ref() string {
+	return c.inner.Ref()
+}
+
+func (c *FaultClient) Type() dto.NetClientType {
+	return c.inner.Type()
+}
+
+// Descriptor describes the wrapped client.
+func (c *FaultClient) Descriptor() dto.NetClient {
+	desc := dto.NetClient{Ref: c.inner.Ref(), ClientType: c.inner.Type()}
+	if descriptor, ok := c.inner.(dto.NetClientDescriptor); ok {
+		desc = descriptor.Descriptor()
+	}
+	desc.Name = "Fault injected: " + desc.Name
+	return desc
+}
+
+// SetTransferPublisher forwards the publisher to the wrapped client.
+func (c *FaultClient) SetTransferPublisher(publish func(dto.TransferNotification)) {
+	if publisher, ok := c.inner.(dto.TransferPublisher); ok {
+		publisher.SetTransferPublisher(publish)
+	}
+}
+
+// DownloadFile forwards to the wrapped client without injecting faults.
+func (c *FaultClient) DownloadFile(ctx context.Context, cfg *dto.DownloadFileConfig, destination string) error {
+	downloader, ok := c.inner.(dto.FileDownloader)
+	if !ok {
+		return fmt.Errorf("client %s does not support file downloads", c.inner.Ref())
+	}
+	return downloader.DownloadFile(ctx, cfg, destination)
+}
+
+func (c *FaultClient) ProcessRequest(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+	faults := c.inj.plan(c.inner.Ref(), requestTarget(cfg))
+
+	var after []FaultRule
+	for _, f := range faults {
+		switch f.rule.Kind {
+		case FaultLatency:
+			if err := sleep(ctx, f.delay); err != nil {
+				return dto.Response{}, err
+			}
+		case FaultError:
+			return dto.Response{}, f.rule.err()
+		case FaultTimeout:
+			timeout := f.rule.Timeout
+			if timeout == 0 {
+				timeout = DefaultFaultTimeout
+			}
+			if err := sleep(ctx, timeout); err != nil {
+				return dto.Response{}, err
+			}
+			return dto.Response{}, f.rule.err()
+		case FaultStatus:
+			return dto.Response{
+				StatusCode: f.rule.StatusCode,
+				Headers:    http.Header{},
+				Body:       append([]byte(nil), f.rule.Body...),
+			}, nil
+		case FaultTruncate, FaultSlowDrip:
+			after = append(after, f.rule)
+		}
+	}
+
+	resp, err := c.inner.ProcessRequest(ctx, cfg)
+	if err != nil {
+		return resp, err
+	}
+	for _, rule := range after {
+		if resp, err = applyBodyFault(ctx, rule, resp); err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// requestTarget returns the request URL when the ReqConfig can describe it.
+func requestTarget(cfg *dto.RequestConfig) *url.URL {
+	if cfg == nil {
+		return nil
+	}
+	describer, ok := cfg.ReqConfig.(dto.RequestDescriber)
+	if !ok {
+		return nil
+	}
+	desc, err := describer.Describe()
+	if err != nil {
+		return nil
+	}
+	target, err := url.Parse(desc.URL)
+	if err != nil {
+		return nil
+	}
+	return target
+}
+
+func applyBodyFault(ctx context.Context, rule FaultRule, resp dto.Response) (dto.Response, error) {
+	switch rule.Kind {
+	case FaultTruncate:
+		if resp.Stream != nil {
+			resp.Stream = &truncatedReader{ReadCloser: resp.Stream, remaining: rule.TruncateAt}
+		} else if len(resp.Body) > rule.TruncateAt {
+			resp.Body = resp.Body[:rule.TruncateAt]
+		}
+	case FaultSlowDrip:
+		chunk := max(rule.DripChunk, 1)
+		if resp.Stream != nil {
+			resp.Stream = &dripReader{ctx: ctx, ReadCloser: resp.Stream, chunk: chunk, interval: rule.DripInterval}
+			return resp, nil
+		}
+		// A buffered body is dripped through the same reader before returning.
+		body, err := io.ReadAll(&dripReader{
+			ctx:        ctx,
+			ReadCloser: io.NopCloser(bytes.NewReader(resp.Body)),
+			chunk:      chunk,
+			interval:   rule.DripInterval,
+		})
+		if err != nil {
+			return dto.Response{}, err
+		}
+		resp.Body = body
+	}
+	return resp, nil
+}
+
+// truncatedReader ends a stream early with io.ErrUnexpectedEOF.
+type truncatedReader struct {
+	io.ReadCloser
+	remaining int
+}
+
+func (r *truncatedReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if len(p) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.remaining -= n
+	return n, err
+}
+
+// dripReader returns at most chunk bytes per interval.
+type dripReader struct {
+	io.ReadCloser
+	ctx      context.Context
+	chunk    int
+	interval time.Duration
+}
+
+func (r *dripReader) Read(p []byte) (int, error) {
+	if err := sleep(r.ctx, r.interval); err != nil {
+		return 0, err
+	}
+	if len(p) > r.chunk {
+		p = p[:r.chunk]
+	}
+	return r.ReadCloser.Read(p)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,17 @@
+package faultclient
+
+// FaultClientConfig defines the rules shared by every client wrapped by an
+// Injector. The same Seed and request sequence always inject the same faults.
+type FaultClientConfig struct {
+	Seed  uint64
+	Rules []FaultRule
+}
+
+func DefaultFaultClientConfig(seed uint64) FaultClientConfig {
+	return FaultClientConfig{Seed: seed, Rules: []FaultRule{}}
+}
+
+func (c *FaultClientConfig) WithRule(rules ...FaultRule) *FaultClientConfig {
+	c.Rules = append(c.Rules, rules...)
+	return c
+}
@@ -0,0 +1,293 @@
+package faultclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joy-dx/gonetic/client/httpclient"
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/gonetictest"
+)
+
+type fakeClient struct {
+	ref   string
+	calls atomic.Int32
+}
+
+func (f *fakeClient) Ref() string             { return f.ref }
+func (f *fakeClient) Type() dto.NetClientType { return httpclient.NetClientHTTPRef }
+
+func (f *fakeClient) ProcessRequest(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+	f.calls.Add(1)
+	body := []byte("hello world")
+	if req, ok := cfg.ReqConfig.(*httpclient.HTTPRequestConfig); ok && req.Headers["X-Stream"] != "" {
+		return dto.Response{StatusCode: 200, Stream: io.NopCloser(strings.NewReader(string(body)))}, nil
+	}
+	return dto.Response{StatusCode: 200, Body: body}, nil
+}
+
+func request(url string, stream bool) *dto.RequestConfig {
+	httpCfg := httpclient.DefaultHTTPRequestConfig()
+	httpCfg.WithURL(url)
+	if stream {
+		httpCfg.WithHeaders(map[string]string{"X-Stream": "1"})
+	}
+	cfg := dto.DefaultRequestConfig()
+	cfg.WithReqConfig(&httpCfg)
+	return &cfg
+}
+
+func newInjector(t *testing.T, seed uint64, rules ...FaultRule) *Injector {
+	t.Helper()
+	cfg := DefaultFaultClientConfig(seed)
+	cfg.WithRule(rules...)
+	inj, err := NewInjector(&cfg)
+	if err != nil {
+		t.Fatalf("NewInjector: %v", err)
+	}
+	return inj
+}
+
+func failurePattern(t *testing.T, seed uint64) string {
+	t.Helper()
+	c := newInjector(t, seed, FaultRule{Name: "flaky", Kind: FaultError, Rate: 0.3}).Wrap(&fakeClient{ref: "api"})
+	var b strings.Builder
+	for i := 0; i < 64; i++ {
+		if _, err := c.ProcessRequest(context.Background(), request("https://api.test/x", false)); err != nil {
+			b.WriteByte('x')
+		} else {
+			b.WriteByte('.')
+		}
+	}
+	return b.String()
+}
+
+func TestInjector_Deterministic_Golden(t *testing.T) {
+	first, again, other := failurePattern(t, 42), failurePattern(t, 42), failurePattern(t, 7)
+	if first != again {
+		t.Fatalf("same seed diverged:\n%s\n%s", first, again)
+	}
+	if first == other {
+		t.Fatalf("different seeds produced the same pattern %s", first)
+	}
+	if n := strings.Count(first, "x"); n == 0 || n == len(first) {
+		t.Fatalf("rate not applied: %s", first)
+	}
+}
+
+func TestFaultClient_Faults_Golden(t *testing.T) {
+	cases := []struct {
+		name      string
+		rule      FaultRule
+		url       string
+		stream    bool
+		ctxTTL    time.Duration
+		wantErr   func(error) bool
+		wantBody  string
+		wantCode  int
+		wantCalls int32
+		minTime   time.Duration
+	}{
+		{
+			name:      "error",
+			rule:      FaultRule{Name: "e", Kind: FaultError},
+			wantErr:   func(err error) bool { var inj *InjectedError; return errors.As(err, &inj) && inj.Temporary() },
+			wantCalls: 0,
+		},
+		{
+			name:      "custom permanent error",
+			rule:      FaultRule{Name: "e", Kind: FaultError, Err: io.ErrClosedPipe},
+			wantErr:   func(err error) bool { return errors.Is(err, io.ErrClosedPipe) },
+			wantCalls: 0,
+		},
+		{
+			name:      "status",
+			rule:      FaultRule{Name: "s", Kind: FaultStatus, StatusCode: 503, Body: []byte("busy")},
+			wantCode:  503,
+			wantBody:  "busy",
+			wantCalls: 0,
+		},
+		{
+			name:      "latency",
+			rule:      FaultRule{Name: "l", Kind: FaultLatency, Latency: Latency{Distribution: LatencyUniform, Base: 30 * time.Millisecond, Spread: 10 * time.Millisecond}},
+			wantCode:  200,
+			wantBody:  "hello world",
+			wantCalls: 1,
+			minTime:   30 * time.Millisecond,
+		},
+		{
+			name:      "truncate body",
+			rule:      FaultRule{Name: "t", Kind: FaultTruncate, TruncateAt: 5},
+			wantCode:  200,
+			wantBody:  "hello",
+			wantCalls: 1,
+		},
+		{
+			name:      "truncate stream",
+			rule:      FaultRule{Name: "t", Kind: FaultTruncate, TruncateAt: 5},
+			stream:    true,
+			wantCode:  200,
+			wantBody:  "hello",
+			wantCalls: 1,
+		},
+		{
+			name:      "slow drip stream",
+			rule:      FaultRule{Name: "d", Kind: FaultSlowDrip, DripChunk: 4, DripInterval: 5 * time.Millisecond},
+			stream:    true,
+			wantCode:  200,
+			wantBody:  "hello world",
+			wantCalls: 1,
+			minTime:   15 * time.Millisecond,
+		},
+		{
+			name:    "timeout",
+			rule:    FaultRule{Name: "to", Kind: FaultTimeout},
+			ctxTTL:  20 * time.Millisecond,
+			wantErr: func(err error) bool { return errors.Is(err, context.DeadlineExceeded) },
+			minTime: 20 * time.Millisecond,
+		},
+		{
+			name: "timeout without deadline",
+			rule: FaultRule{Name: "to", Kind: FaultTimeout, Timeout: 20 * time.Millisecond},
+			wantErr: func(err error) bool {
+				var netErr net.Error
+				return errors.As(err, &netErr) && netErr.Timeout()
+			},
+			minTime: 20 * time.Millisecond,
+		},
+		{
+			name:      "host scope miss",
+			rule:      FaultRule{Name: "e", Kind: FaultError, Host: "other.test"},
+			wantCode:  200,
+			wantBody:  "hello world",
+			wantCalls: 1,
+		},
+		{
+			name:    "path scope hit",
+			rule:    FaultRule{Name: "e", Kind: FaultError, Host: "api.test", PathPattern: "/v1/*"},
+			url:     "https://api.test/v1/items",
+			wantErr: func(err error) bool { return err != nil },
+		},
+		{
+			name:      "client ref scope miss",
+			rule:      FaultRule{Name: "e", Kind: FaultError, ClientRef: "s3"},
+			wantCode:  200,
+			wantBody:  "hello world",
+			wantCalls: 1,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inner := &fakeClient{ref: "api"}
+			c := newInjector(t, 1, tc.rule).Wrap(inner)
+
+			ctx := context.Background()
+			if tc.ctxTTL > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.ctxTTL)
+				defer cancel()
+			}
+			url := tc.url
+			if url == "" {
+				url = "https://api.test/items"
+			}
+
+			start := time.Now()
+			resp, err := c.ProcessRequest(ctx, request(url, tc.stream))
+			if tc.wantErr != nil {
+				if !tc.wantErr(err) {
+					t.Fatalf("unexpected err=%v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("ProcessRequest: %v", err)
+			}
+			body := resp.Body
+			if resp.Stream != nil {
+				body, _ = io.ReadAll(resp.Stream)
+			}
+			if elapsed := time.Since(start); elapsed < tc.minTime {
+				t.Fatalf("elapsed=%v want >= %v", elapsed, tc.minTime)
+			}
+			if resp.StatusCode != tc.wantCode || string(body) != tc.wantBody {
+				t.Fatalf("status=%d body=%q", resp.StatusCode, body)
+			}
+			if inner.calls.Load() != tc.wantCalls {
+				t.Fatalf("inner calls=%d want %d", inner.calls.Load(), tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestInjector_RuntimeToggles_Golden(t *testing.T) {
+	inj := newInjector(t, 1, FaultRule{Name: "down", Kind: FaultStatus, StatusCode: 500})
+	c := inj.Wrap(&fakeClient{ref: "api"})
+	status := func() int {
+		resp, _ := c.ProcessRequest(context.Background(), request("https://api.test/", false))
+		return resp.StatusCode
+	}
+
+	steps := []struct {
+		name   string
+		toggle func()
+		want   int
+	}{
+		{name: "enabled", toggle: func() {}, want: 500},
+		{name: "rule disabled", toggle: func() { inj.Disable("down") }, want: 200},
+		{name: "rule enabled", toggle: func() { inj.Enable("down") }, want: 500},
+		{name: "paused", toggle: inj.Pause, want: 200},
+		{name: "resumed", toggle: func() { inj.Enable() }, want: 500},
+		{name: "removed", toggle: func() { inj.RemoveRule("down") }, want: 200},
+	}
+	for _, step := range steps {
+		step.toggle()
+		if got := status(); got != step.want {
+			t.Fatalf("%s: status=%d want %d", step.name, got, step.want)
+		}
+	}
+	if got := inj.Stats()["down"]; got != 3 {
+		t.Fatalf("stats=%d want 3", got)
+	}
+}
+
+func TestFaultClient_RetryThroughNetSvc_Golden(t *testing.T) {
+	f := gonetictest.NewFixture(t)
+	f.Server.On(gonetictest.Path("/items")).Respond(gonetictest.Text(http.StatusOK, "ok"))
+
+	inj := newInjector(t, 1, FaultRule{Name: "blip", Kind: FaultError, Limit: 2})
+	f.Svc.RegisterClient(f.ClientRef, inj.Wrap(f.Client))
+
+	resp, err := f.Svc.RequestWithRetry(context.Background(), f.Get("/items"))
+	if err != nil || string(resp.Body) != "ok" {
+		t.Fatalf("resp=%q err=%v", resp.Body, err)
+	}
+	if got := inj.Stats()["blip"]; got != 2 {
+		t.Fatalf("injected=%d want 2", got)
+	}
+	f.Server.AssertRequests(t, 1, gonetictest.Path("/items"))
+}
+
+func TestNewInjector_InvalidRules_Golden(t *testing.T) {
+	cases := []FaultRule{
+		{Kind: FaultError},
+		{Name: "x", Kind: "meteor"},
+		{Name: "x", Kind: FaultStatus},
+		{Name: "x", Kind: FaultSlowDrip},
+		{Name: "x", Kind: FaultError, Rate: 1.5},
+		{Name: "x", Kind: FaultError, PathPattern: "["},
+		{Name: "x", Kind: FaultTimeout, Timeout: -time.Second},
+	}
+	for _, rule := range cases {
+		cfg := DefaultFaultClientConfig(1)
+		cfg.WithRule(rule)
+		if _, err := NewInjector(&cfg); err == nil {
+			t.Fatalf("rule %+v: expected error", rule)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package faultclient
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// FaultKind selects what a rule does to a request.
+type FaultKind string
+
+const (
+	// FaultLatency delays the request before it is sent.
+	FaultLatency FaultKind = "latency"
+	// FaultError fails the request without sending it.
+	FaultError FaultKind = "error"
+	// FaultStatus answers with StatusCode without sending the request.
+	FaultStatus FaultKind = "status"
+	// FaultTruncate cuts the response body after TruncateAt bytes.
+	FaultTruncate FaultKind = "truncate"
+	// FaultSlowDrip delivers the response body DripChunk bytes per DripInterval.
+	FaultSlowDrip FaultKind = "slow_drip"
+	// FaultTimeout hangs until Timeout elapses and fails with a timeout error.
+	// If the request context ends first, its error is returned instead.
+	FaultTimeout FaultKind = "timeout"
+)
+
+// DefaultFaultTimeout bounds FaultTimeout rules without a Timeout, so a
+// request without a deadline cannot hang forever.
+const DefaultFaultTimeout = 30 * time.Second
+
+// LatencyDistribution shapes sampled latency.
+type LatencyDistribution string
+
+const (
+	// LatencyFixed always waits Base.
+	LatencyFixed LatencyDistribution = "fixed"
+	// LatencyUniform waits Base plus a uniform sample in [0, Spread).
+	LatencyUniform LatencyDistribution = "uniform"
+	// LatencyNormal waits Base plus a normal sample with standard deviation
+	// Spread, never less than zero.
+	LatencyNormal LatencyDistribution = "normal"
+	// LatencyExponential waits Base plus an exponential sample with mean Spread.
+	LatencyExponential LatencyDistribution = "exponential"
+)
+
+// Latency describes a latency distribution.
+type Latency struct {
+	Distribution LatencyDistribution
+	Base         time.Duration
+	Spread       time.Duration
+}
+
+func (l Latency) sample(rng *rand.Rand) time.Duration {
+	var extra float64
+	switch l.Distribution {
+	case LatencyUniform:
+		extra = rng.Float64() * float64(l.Spread)
+	case LatencyNormal:
+		extra = rng.NormFloat64() * float64(l.Spread)
+	case LatencyExponential:
+		extra = rng.ExpFloat64() * float64(l.Spread)
+	}
+	return time.Duration(math.Max(0, float64(l.Base)+extra))
+}
+
+// FaultRule injects one kind of fault into matching requests.
+type FaultRule struct {
+	// Name identifies the rule for Enable, Disable and Stats. Required and unique.
+	Name string
+	Kind FaultKind
+	// Disabled rules are skipped until enabled at runtime.
+	Disabled bool
+
+	// Scope. Empty fields match everything. PathPattern uses path.Match syntax.
+	// Host and path scopes need a ReqConfig implementing dto.RequestDescriber.
+	ClientRef   string
+	Host        string
+	PathPattern string
+
+	// Rate is the probability in (0, 1] that a matching request is affected,
+	// 1 when zero. Limit caps the number of injections, unlimited when zero.
+	Rate  float64
+	Limit int
+
+	Latency Latency
+	// Err is returned by FaultError, an *InjectedError when nil.
+	Err error
+	// Permanent marks injected errors as non-temporary so they are not retried.
+	Permanent  bool
+	StatusCode int
+	Body       []byte
+	TruncateAt int
+	// DripChunk defaults to 1 byte.
+	DripChunk    int
+	DripInterval time.Duration
+	// Timeout defaults to DefaultFaultTimeout.
+	Timeout time.Duration
+}
+
+func (r *FaultRule) validate() error {
+	if r.Name == "" {
+		return errors.New("fault rule name required")
+	}
+	switch r.Kind {
+	case FaultLatency, FaultError, FaultTruncate:
+	case FaultTimeout:
+		if r.Timeout < 0 {
+			return fmt.Errorf("fault rule %s: negative timeout %v", r.Name, r.Timeout)
+		}
+	case FaultStatus:
+		if r.StatusCode < 100 || r.StatusCode > 999 {
+			return fmt.Errorf("fault rule %s: invalid status code %d", r.Name, r.StatusCode)
+		}
+	case FaultSlowDrip:
+		if r.DripInterval <= 0 {
+			return fmt.Errorf("fault rule %s: drip interval required", r.Name)
+		}
+	default:
+		return fmt.Errorf("fault rule %s: unknown kind %q", r.Name, r.Kind)
+	}
+	if r.Rate < 0 || r.Rate > 1 {
+		return fmt.Errorf("fault rule %s: rate %v outside [0, 1]", r.Name, r.Rate)
+	}
+	if r.PathPattern != "" {
+		if _, err := path.Match(r.PathPattern, "/"); err != nil {
+			return fmt.Errorf("fault rule %s: path pattern: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *FaultRule) matches(clientRef string, target *url.URL) bool {
+	if r.ClientRef != "" && r.ClientRef != clientRef {
+		return false
+	}
+	if r.Host == "" && r.PathPattern == "" {
+		return true
+	}
+	if target == nil {
+		return false
+	}
+	if r.Host != "" && !strings.EqualFold(r.Host, target.Hostname()) && !strings.EqualFold(r.Host, target.Host) {
+		return false
+	}
+	if r.PathPattern != "" {
+		ok, _ := path.Match(r.PathPattern, target.Path)
+		return ok
+	}
+	return true
+}
+
+// InjectedError is returned for FaultError rules without Err and for
+// FaultTimeout rules. It implements net.Error.
+type InjectedError struct {
+	Rule      string
+	Kind      FaultKind
+	Permanent bool
+}
+
+func (e *InjectedError) Error() string {
+	return fmt.Sprintf("injected %s fault (%s)", e.Kind, e.Rule)
+}
+
+func (e *InjectedError) Timeout() bool { return e.Kind == FaultTimeout }
+
+func (e *InjectedError) Temporary() bool { return !e.Permanent }
+
+func (r *FaultRule) err() error {
+	if r.Err != nil {
+		return r.Err
+	}
+	return &InjectedError{Rule: r.Name, Kind: r.Kind, Permanent: r.Permanent}
+}
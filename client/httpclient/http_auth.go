@@ -1,8 +1,12 @@
 package httpclient
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
 	"strings"
+
+	"github.com/joy-dx/gonetic/dto"
 )
 
 // normalizeAuthType ensures proper "Bearer", "Basic", or custom capitalization.
@@ -44,3 +48,26 @@ func (c *HTTPClient) attachAuth(cfg *HTTPRequest) {
 		cfg.Headers["Cookie"] = merged
 	}
 }
+
+// StaticTokenProvider is an AuthProvider for fixed credentials such as an API
+// token or a basic auth pair. The token never expires.
+type StaticTokenProvider struct {
+	Token dto.TokenInfo
+}
+
+func NewBearerTokenProvider(token string) *StaticTokenProvider {
+	return &StaticTokenProvider{Token: dto.TokenInfo{AccessToken: token, TokenType: "Bearer"}}
+}
+
+func NewBasicAuthProvider(username string, password string) *StaticTokenProvider {
+	encoded := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return &StaticTokenProvider{Token: dto.TokenInfo{AccessToken: encoded, TokenType: "Basic"}}
+}
+
+func (p *StaticTokenProvider) Authenticate(ctx context.Context) (dto.TokenInfo, error) {
+	return p.Token, nil
+}
+
+func (p *StaticTokenProvider) Refresh(ctx context.Context, old dto.TokenInfo) (dto.TokenInfo, error) {
+	return p.Token, nil
+}
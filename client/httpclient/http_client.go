@@ -6,12 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/joy-dx/gonetic/config"
 	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/telemetry"
 )
 
 // -----------------------------------------------------------------------------
@@ -38,10 +41,17 @@ type HTTPClient struct {
 	client    *http.Client
 	token     dto.TokenInfo
 	tokenMu   sync.RWMutex
+	har       atomic.Pointer[HARRecorder]
+	tls       *tlsSource
+	unix      *unixTransport
+	// pool is nil when HTTPClientConfig.RoundTripper replaces the transport
+	pool *poolTracker
+	// initErr is a configuration error reported by every request
+	initErr error
 }
 
 func NewHTTPClient(ref string, netCfg *config.NetSvcConfig, cfg *HTTPClientConfig) *HTTPClient {
-	return &HTTPClient{
+	c := &HTTPClient{
 		cfg:    cfg,
 		netCfg: netCfg,
 		NetClient: dto.NetClient{
@@ -50,26 +60,147 @@ func NewHTTPClient(ref string, netCfg *config.NetSvcConfig, cfg *HTTPClientConfi
 			ClientType:  NetClientHTTPRef,
 			Description: "Perform HTTP requests to given URLs including auth support",
 		},
-		client: &http.Client{
-			Timeout: netCfg.RequestTimeout,
-			Transport: &http.Transport{
-				MaxIdleConns:        50,
-				IdleConnTimeout:     90 * time.Second,
-				TLSHandshakeTimeout: 10 * time.Second,
-				DisableKeepAlives:   false,
-				Proxy:               http.ProxyFromEnvironment,
-			},
-		},
 	}
+	if cfg.RoundTripper != nil {
+		// A supplied round tripper, e.g. HTTP/3, owns dialing and TLS
+		if !cfg.TLS.IsZero() || !cfg.Resolver.IsZero() || cfg.SocketPath != "" {
+			c.initErr = errors.New("tls, resolver and socket path cannot be combined with a custom round tripper")
+		}
+		c.client = &http.Client{
+			Timeout:   netCfg.RequestTimeout,
+			Transport: cfg.RoundTripper,
+		}
+		return c
+	}
+
+	c.pool = newPoolTracker()
+	transport := newTransport(cfg.Transport)
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	dial := dialer.DialContext
+	if !cfg.Resolver.IsZero() {
+		dial = newResolverDialer(cfg.Resolver, dialer).DialContext
+	}
+	c.unix = newUnixTransport(cfg.Transport, c.pool.dial(dialer.DialContext))
+	transport.RegisterProtocol(UnixScheme, c.unix)
+	errs := []error{cfg.Resolver.Validate(), cfg.Transport.Validate()}
+	var err error
+	if cfg.SocketPath != "" {
+		// Every request goes to the socket, so neither DNS nor proxies apply
+		dial = dialSocket(dialer, cfg.SocketPath)
+	} else if transport.Proxy, err = c.proxyConfig().ProxyFunc(); err != nil {
+		errs = append(errs, err)
+	}
+	transport.DialContext = c.pool.dial(dial)
+	if !cfg.TLS.IsZero() {
+		c.tls = newTLSSource(cfg.TLS)
+		if transport.TLSClientConfig, err = c.tls.tlsConfig(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.initErr = errors.Join(errs...)
+	c.client = &http.Client{
+		Timeout:   netCfg.RequestTimeout,
+		Transport: &trackingTransport{base: transport, pool: c.pool},
+	}
+	return c
 }
 
 func (c *HTTPClient) Ref() string {
 	return c.NetClient.Ref
 }
+
+// Descriptor implements dto.NetClientDescriptor.
+func (c *HTTPClient) Descriptor() dto.NetClient {
+	return c.NetClient
+}
+
+// CloseIdleConnections closes pooled connections not currently in use.
+// NetSvc calls it on Shutdown.
+func (c *HTTPClient) CloseIdleConnections() {
+	c.client.CloseIdleConnections()
+	if c.unix != nil {
+		c.unix.CloseIdleConnections()
+	}
+}
+
+// PoolStats reports open connections per dialed host. It is nil when a
+// custom RoundTripper is configured.
+func (c *HTTPClient) PoolStats() []dto.HostPoolStats {
+	if c.pool == nil {
+		return nil
+	}
+	return c.pool.stats()
+}
+
 func (c *HTTPClient) Type() dto.NetClientType {
 	return NetClientHTTPRef
 }
 
+// Transport returns the round tripper requests are sent through. Downloads
+// use it so they share the client's TLS settings.
+func (c *HTTPClient) Transport() http.RoundTripper {
+	return c.client.Transport
+}
+
+// ReloadTLS rereads the configured CA, certificate and key files. On error
+// the previous material stays in use.
+func (c *HTTPClient) ReloadTLS() error {
+	if c.initErr != nil {
+		return c.initErr
+	}
+	if c.tls == nil {
+		return nil
+	}
+	return c.tls.Reload()
+}
+
+// CurlArgs returns curl flags equivalent to the client's transport settings,
+// or false when curl cannot reproduce them. A non-empty stdin is a curl
+// config holding credentials, to be written to curl's standard input.
+func (c *HTTPClient) CurlArgs() (args []string, stdin string, ok bool) {
+	if c.cfg.RoundTripper != nil {
+		return nil, "", false
+	}
+	args, ok = c.cfg.TLS.CurlArgs()
+	if !ok {
+		return nil, "", false
+	}
+	resolverArgs, ok := c.cfg.Resolver.CurlArgs()
+	if !ok {
+		return nil, "", false
+	}
+	args = append(args, c.cfg.Transport.CurlArgs()...)
+	if c.cfg.SocketPath != "" {
+		return append(args, "--unix-socket", c.cfg.SocketPath), "", true
+	}
+	proxyArgs, stdin, err := c.proxyConfig().CurlArgs()
+	if err != nil {
+		return nil, "", false
+	}
+	args = append(args, resolverArgs...)
+	return append(args, proxyArgs...), stdin, true
+}
+
+// proxyConfig is the client's proxy, falling back to the service's.
+func (c *HTTPClient) proxyConfig() *config.ProxyConfig {
+	if c.cfg.Proxy != nil {
+		return c.cfg.Proxy
+	}
+	return &c.netCfg.Proxy
+}
+
+// StartHAR records every following ProcessRequest exchange into recorder until
+// StopHAR. File downloads, which use Transport directly or curl, are not
+// recorded.
+func (c *HTTPClient) StartHAR(recorder *HARRecorder) {
+	c.har.Store(recorder)
+}
+
+// StopHAR stops recording and returns the recorder that was attached, if any.
+func (c *HTTPClient) StopHAR() *HARRecorder {
+	return c.har.Swap(nil)
+}
+
 // -----------------------------------------------------------------------------
 // REQUEST EXECUTION
 // -----------------------------------------------------------------------------
@@ -79,6 +210,9 @@ func (c *HTTPClient) Type() dto.NetClientType {
 // If multiple authentication mechanisms are configured, OAuth2 takes precedence.
 // AuthProvider is used as a fallback.
 func (c *HTTPClient) ProcessRequest(ctx context.Context, inCfg *dto.RequestConfig) (dto.Response, error) {
+	if c.initErr != nil {
+		return dto.Response{}, fmt.Errorf("http client %s: %w", c.Ref(), c.initErr)
+	}
 	cfg, castOk := inCfg.ReqConfig.(*HTTPRequestConfig)
 	if !castOk {
 		return dto.Response{}, errors.New("problem casting to httprequestconfig")
@@ -92,6 +226,9 @@ func (c *HTTPClient) ProcessRequest(ctx context.Context, inCfg *dto.RequestConfi
 	if !ok {
 		return dto.Response{}, errors.New("problem casting built request to httprequest")
 	}
+	if err := c.applyClientDefaults(reqCfg); err != nil {
+		return dto.Response{}, err
+	}
 
 	for _, mw := range c.cfg.Middlewares {
 		if err := mw(ctx, reqCfg); err != nil {
@@ -112,6 +249,12 @@ func (c *HTTPClient) ProcessRequest(ctx context.Context, inCfg *dto.RequestConfi
 		return dto.Response{}, err
 	}
 
+	har := c.har.Load()
+	var trace *connTrace
+	if har != nil || c.cfg.Timing {
+		ctx, trace = newConnTrace(ctx)
+	}
+
 	httpReq, err := http.NewRequestWithContext(
 		ctx,
 		reqCfg.Method,
@@ -133,6 +276,10 @@ func (c *HTTPClient) ProcessRequest(ctx context.Context, inCfg *dto.RequestConfi
 		httpReq.Header.Set("Content-Type", reqCfg.ContentType)
 	}
 
+	if sc := telemetry.SpanContextFromContext(ctx); sc.IsValid() && httpReq.Header.Get(telemetry.TraceParentHeader) == "" {
+		httpReq.Header.Set(telemetry.TraceParentHeader, sc.TraceParent())
+	}
+
 	// Defensive client.Do handling — httpResp may be non-nil with error
 	httpResp, reqErr := c.client.Do(httpReq)
 	if httpResp != nil {
@@ -142,10 +289,17 @@ func (c *HTTPClient) ProcessRequest(ctx context.Context, inCfg *dto.RequestConfi
 		}()
 	}
 	if reqErr != nil {
+		if har != nil {
+			har.record(c.Ref(), httpReq, reqCfg.BodyBytes, httpResp, nil, trace, time.Now(), reqErr)
+		}
 		return dto.Response{}, fmt.Errorf("perform request: %w", reqErr)
 	}
 
 	bodyBytes, err := io.ReadAll(httpResp.Body)
+	readDone := time.Now()
+	if har != nil {
+		har.record(c.Ref(), httpReq, reqCfg.BodyBytes, httpResp, bodyBytes, trace, readDone, err)
+	}
 	if err != nil {
 		return dto.Response{}, fmt.Errorf("read body: %w", err)
 	}
@@ -155,6 +309,9 @@ func (c *HTTPClient) ProcessRequest(ctx context.Context, inCfg *dto.RequestConfi
 		Headers:    httpResp.Header.Clone(),
 		Body:       bodyBytes,
 	}
+	if c.cfg.Timing {
+		response.Timing = trace.snapshot().responseTiming(httpResp, readDone)
+	}
 
 	// Capture cookies, prunes if expired
 	if setCookies := response.Headers["Set-Cookie"]; len(setCookies) > 0 {
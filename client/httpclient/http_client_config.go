@@ -2,8 +2,10 @@ package httpclient
 
 import (
 	"context"
+	"net/http"
 	"time"
 
+	"github.com/joy-dx/gonetic/config"
 	"github.com/joy-dx/gonetic/dto"
 	"golang.org/x/oauth2"
 )
@@ -11,10 +13,31 @@ import (
 type Middleware func(ctx context.Context, req *HTTPRequest) error
 
 type HTTPClientConfig struct {
+	// BaseURL is resolved against request URLs that are relative
+	BaseURL string
+	// Headers are sent with every request unless the request sets them
+	Headers       map[string]string
 	AuthProvider  dto.AuthProvider
 	OAuthSource   oauth2.TokenSource
 	RefreshBuffer time.Duration
 	Middlewares   []Middleware
+	// Timing fills dto.Response.Timing using net/http/httptrace
+	Timing bool
+	// TLS also applies to downloads made through this client
+	TLS config.TLSConfig
+	// Proxy overrides NetSvcConfig.Proxy when set
+	Proxy *config.ProxyConfig
+	// Resolver also applies to downloads made through this client
+	Resolver config.ResolverConfig
+	// SocketPath sends every request over this Unix domain socket whatever
+	// the URL host. Use http+unix URLs to pick a socket per request instead.
+	SocketPath string
+	// Transport tunes the connection pool and HTTP/2 use
+	Transport config.TransportConfig
+	// RoundTripper replaces the built transport, e.g. with an HTTP/3
+	// implementation. TLS, Resolver, SocketPath, Transport and proxy settings
+	// then do not apply and PoolStats is nil.
+	RoundTripper http.RoundTripper
 }
 
 func DefaultHTTPClientConfig() HTTPClientConfig {
@@ -24,6 +47,18 @@ func DefaultHTTPClientConfig() HTTPClientConfig {
 	}
 }
 
+func (c *HTTPClientConfig) WithBaseURL(baseURL string) *HTTPClientConfig {
+	c.BaseURL = baseURL
+	return c
+}
+func (c *HTTPClientConfig) WithHeader(key string, value string) *HTTPClientConfig {
+	if c.Headers == nil {
+		c.Headers = make(map[string]string)
+	}
+	c.Headers[key] = value
+	return c
+}
+
 // WithRefreshBuffer sets the early-refresh buffer.
 func (c *HTTPClientConfig) WithAuthProvider(provider dto.AuthProvider) *HTTPClientConfig {
 	c.AuthProvider = provider
@@ -37,6 +72,34 @@ func (c *HTTPClientConfig) WithRefreshBuffer(d time.Duration) *HTTPClientConfig
 	c.RefreshBuffer = d
 	return c
 }
+func (c *HTTPClientConfig) WithTiming(enabled bool) *HTTPClientConfig {
+	c.Timing = enabled
+	return c
+}
+func (c *HTTPClientConfig) WithTLS(tls config.TLSConfig) *HTTPClientConfig {
+	c.TLS = tls
+	return c
+}
+func (c *HTTPClientConfig) WithProxy(proxy config.ProxyConfig) *HTTPClientConfig {
+	c.Proxy = &proxy
+	return c
+}
+func (c *HTTPClientConfig) WithResolver(resolver config.ResolverConfig) *HTTPClientConfig {
+	c.Resolver = resolver
+	return c
+}
+func (c *HTTPClientConfig) WithSocketPath(path string) *HTTPClientConfig {
+	c.SocketPath = path
+	return c
+}
+func (c *HTTPClientConfig) WithTransport(transport config.TransportConfig) *HTTPClientConfig {
+	c.Transport = transport
+	return c
+}
+func (c *HTTPClientConfig) WithRoundTripper(rt http.RoundTripper) *HTTPClientConfig {
+	c.RoundTripper = rt
+	return c
+}
 func (c *HTTPClientConfig) WithMiddleware(m ...Middleware) *HTTPClientConfig {
 	c.Middlewares = append(c.Middlewares, m...)
 	return c
@@ -0,0 +1,83 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+func init() {
+	config.RegisterClientType(config.ClientFactory{
+		Type: NetClientHTTPRef,
+		Name: config.ClientTypeHTTP,
+		New:  newFromConfig,
+		Validate: func(client *config.ClientConfig) error {
+			errs := []error{client.Auth.Validate(), client.TLS.Validate(), client.Resolver.Validate(), client.Transport.Validate()}
+			if client.Proxy != nil {
+				errs = append(errs, client.Proxy.Validate())
+			}
+			if client.SocketPath != "" && (client.Proxy != nil || !client.Resolver.IsZero()) {
+				errs = append(errs, errors.New("socket_path cannot be combined with proxy or resolver"))
+			}
+			return errors.Join(errs...)
+		},
+		NewReqConfig: func() dto.ReqConfigInterface {
+			reqCfg := DefaultHTTPRequestConfig()
+			return &reqCfg
+		},
+	})
+}
+
+// newFromConfig builds an HTTPClient from a declarative client config.
+func newFromConfig(netCfg *config.NetSvcConfig, declared *config.ClientConfig) (dto.NetClientInterface, error) {
+	httpCfg := DefaultHTTPClientConfig()
+	httpCfg.WithBaseURL(declared.BaseURL).
+		WithTiming(declared.Timing).
+		WithTLS(declared.TLS).
+		WithResolver(declared.Resolver).
+		WithSocketPath(declared.SocketPath).
+		WithTransport(declared.Transport)
+	if declared.Proxy != nil {
+		httpCfg.WithProxy(*declared.Proxy)
+	}
+	for k, v := range declared.Headers {
+		httpCfg.WithHeader(k, v)
+	}
+
+	auth := declared.Auth
+	switch auth.Mode {
+	case config.AuthModeBearer:
+		token, err := auth.Token.Resolve()
+		if err != nil {
+			return nil, err
+		}
+		httpCfg.WithAuthProvider(NewBearerTokenProvider(token))
+	case config.AuthModeBasic:
+		password, err := auth.Password.Resolve()
+		if err != nil {
+			return nil, err
+		}
+		httpCfg.WithAuthProvider(NewBasicAuthProvider(auth.Username, password))
+	case config.AuthModeClientCredentials:
+		secret, err := auth.ClientSecret.Resolve()
+		if err != nil {
+			return nil, err
+		}
+		cc := clientcredentials.Config{
+			ClientID:     auth.ClientID,
+			ClientSecret: secret,
+			TokenURL:     auth.TokenURL,
+			Scopes:       auth.Scopes,
+		}
+		httpCfg.WithOAuthSource(cc.TokenSource(context.Background()))
+	}
+
+	client := NewHTTPClient(declared.Ref, netCfg, &httpCfg)
+	if err := client.ReloadTLS(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
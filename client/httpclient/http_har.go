@@ -0,0 +1,397 @@
+package httpclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/joy-dx/gonetic/utils"
+)
+
+// HAR is an HTTP Archive 1.2 document.
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type HAREntry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	// Time is the total elapsed time in milliseconds
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	Connection      string      `json:"connection,omitempty"`
+	Comment         string      `json:"comment,omitempty"`
+	// ClientRef is the gonetic client that sent the request
+	ClientRef string `json:"_clientRef,omitempty"`
+	// Error is set when no response was received
+	Error string `json:"_error,omitempty"`
+}
+
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HARCookie struct {
+	Name     string     `json:"name"`
+	Value    string     `json:"value"`
+	Path     string     `json:"path,omitempty"`
+	Domain   string     `json:"domain,omitempty"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	HTTPOnly bool       `json:"httpOnly,omitempty"`
+	Secure   bool       `json:"secure,omitempty"`
+}
+
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// HARTimings are in milliseconds; -1 means the phase did not happen, e.g.
+// dns and connect on a reused connection. Connect includes ssl.
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type HARConfig struct {
+	// MaxBodySize caps the request and response body text kept per entry
+	MaxBodySize int
+	// MaxEntries keeps only the most recent entries; 0 keeps everything
+	MaxEntries int
+	// Redactor masks secrets in URLs, headers, cookies and bodies; a zero
+	// Redactor means utils.DefaultRedactor
+	Redactor utils.Redactor
+}
+
+func DefaultHARConfig() HARConfig {
+	return HARConfig{
+		MaxBodySize: 64 << 10,
+		MaxEntries:  1000,
+		Redactor:    utils.DefaultRedactor(),
+	}
+}
+
+func (c *HARConfig) WithMaxBodySize(size int) *HARConfig {
+	c.MaxBodySize = size
+	return c
+}
+
+func (c *HARConfig) WithMaxEntries(entries int) *HARConfig {
+	c.MaxEntries = entries
+	return c
+}
+
+func (c *HARConfig) WithRedactor(redactor utils.Redactor) *HARConfig {
+	c.Redactor = redactor
+	return c
+}
+
+// HARRecorder collects HTTPClient traffic in memory. Attach it with
+// HTTPClient.StartHAR and write it out with Save or WriteTo. It is safe to
+// share between clients.
+type HARRecorder struct {
+	cfg     HARConfig
+	mu      sync.Mutex
+	entries []HAREntry
+}
+
+func NewHARRecorder(cfg HARConfig) *HARRecorder {
+	if cfg.Redactor.IsZero() {
+		cfg.Redactor = utils.DefaultRedactor()
+	}
+	return &HARRecorder{cfg: cfg}
+}
+
+// Entries returns the recorded entries, oldest first.
+func (r *HARRecorder) Entries() []HAREntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]HAREntry(nil), r.entries...)
+}
+
+// HAR returns the recorded entries as a HAR document.
+func (r *HARRecorder) HAR() HAR {
+	entries := r.Entries()
+	if entries == nil {
+		entries = []HAREntry{}
+	}
+	return HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: HARCreator{Name: "gonetic", Version: "1"},
+		Entries: entries,
+	}}
+}
+
+// WriteTo writes the HAR document as indented JSON.
+func (r *HARRecorder) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(r.HAR(), "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("marshal har: %w", err)
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// Save writes the HAR document to path, replacing it atomically.
+func (r *HARRecorder) Save(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("save har: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := r.WriteTo(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save har: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save har: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("save har: %w", err)
+	}
+	return nil
+}
+
+// Reset drops every recorded entry.
+func (r *HARRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+func (r *HARRecorder) add(entry HAREntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	if r.cfg.MaxEntries > 0 && len(r.entries) > r.cfg.MaxEntries {
+		r.entries = append([]HAREntry(nil), r.entries[len(r.entries)-r.cfg.MaxEntries:]...)
+	}
+}
+
+// record builds an entry from one exchange. resp is nil when the request
+// failed before a response arrived.
+func (r *HARRecorder) record(clientRef string, req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, trace *connTrace, end time.Time, reqErr error) {
+	redactor := r.cfg.Redactor
+	t := trace.snapshot()
+
+	entry := HAREntry{
+		StartedDateTime: t.start,
+		Time:            millis(end.Sub(t.start)),
+		ClientRef:       clientRef,
+		Request: HARRequest{
+			Method:      req.Method,
+			URL:         redactor.URL(req.URL.String()),
+			HTTPVersion: req.Proto,
+			Cookies:     r.cookies(req.Cookies()),
+			Headers:     nameValues(redactor.Header(req.Header)),
+			QueryString: []HARNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: HARResponse{
+			Cookies:     []HARCookie{},
+			Headers:     []HARNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: harTimings(t, end),
+	}
+	if u, err := url.Parse(entry.Request.URL); err == nil {
+		for name, values := range u.Query() {
+			for _, v := range values {
+				entry.Request.QueryString = append(entry.Request.QueryString, HARNameValue{Name: name, Value: v})
+			}
+		}
+	}
+	if len(reqBody) > 0 {
+		text, truncated := r.bodyText(redactor.Body(reqBody))
+		entry.Request.PostData = &HARPostData{MimeType: req.Header.Get("Content-Type"), Text: text}
+		if truncated {
+			entry.Request.PostData.Comment = "truncated"
+		}
+	}
+	if t.remoteAddr != "" {
+		host, port, err := net.SplitHostPort(t.remoteAddr)
+		if err == nil {
+			entry.ServerIPAddress = host
+			entry.Connection = port
+		}
+	}
+	if reqErr != nil {
+		entry.Error = redactor.Error(reqErr)
+	}
+
+	if resp != nil {
+		entry.Response = HARResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: resp.Proto,
+			Cookies:     r.cookies(resp.Cookies()),
+			Headers:     nameValues(redactor.Header(resp.Header)),
+			RedirectURL: redactor.URL(resp.Header.Get("Location")),
+			HeadersSize: -1,
+			BodySize:    len(respBody),
+			Content: HARContent{
+				Size:     len(respBody),
+				MimeType: resp.Header.Get("Content-Type"),
+			},
+		}
+		body, truncated := redactor.Body(respBody), false
+		if utf8.Valid(body) {
+			entry.Response.Content.Text, truncated = r.bodyText(body)
+		} else {
+			if r.cfg.MaxBodySize > 0 && len(body) > r.cfg.MaxBodySize {
+				body, truncated = body[:r.cfg.MaxBodySize], true
+			}
+			entry.Response.Content.Text = base64.StdEncoding.EncodeToString(body)
+			entry.Response.Content.Encoding = "base64"
+		}
+		if truncated {
+			entry.Response.Content.Comment = "truncated"
+		}
+	}
+	r.add(entry)
+}
+
+func (r *HARRecorder) bodyText(body []byte) (string, bool) {
+	if r.cfg.MaxBodySize > 0 && len(body) > r.cfg.MaxBodySize {
+		return string(body[:r.cfg.MaxBodySize]), true
+	}
+	return string(body), false
+}
+
+func (r *HARRecorder) cookies(cookies []*http.Cookie) []HARCookie {
+	out := make([]HARCookie, 0, len(cookies))
+	redact := false
+	for _, name := range r.cfg.Redactor.Headers {
+		if http.CanonicalHeaderKey(name) == "Cookie" || http.CanonicalHeaderKey(name) == "Set-Cookie" {
+			redact = true
+		}
+	}
+	for _, c := range cookies {
+		cookie := HARCookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Path:     c.Path,
+			Domain:   c.Domain,
+			HTTPOnly: c.HttpOnly,
+			Secure:   c.Secure,
+		}
+		if redact {
+			cookie.Value = utils.RedactedValue
+		}
+		if !c.Expires.IsZero() {
+			expires := c.Expires
+			cookie.Expires = &expires
+		}
+		out = append(out, cookie)
+	}
+	return out
+}
+
+func nameValues(h http.Header) []HARNameValue {
+	out := make([]HARNameValue, 0, len(h))
+	for name, values := range h {
+		for _, v := range values {
+			out = append(out, HARNameValue{Name: name, Value: v})
+		}
+	}
+	return out
+}
+
+func harTimings(t traceTimes, end time.Time) HARTimings {
+	timings := HARTimings{
+		Blocked: -1,
+		DNS:     millis(between(t.dnsStart, t.dnsDone)),
+		Connect: millis(between(t.connectStart, t.connectDone)),
+		SSL:     millis(between(t.tlsStart, t.tlsDone)),
+		Send:    millis(between(t.gotConn, t.wroteRequest)),
+		Wait:    millis(between(t.wroteRequest, t.firstByte)),
+		Receive: millis(between(t.firstByte, end)),
+	}
+	if timings.SSL >= 0 && timings.Connect >= 0 {
+		timings.Connect = millis(between(t.connectStart, t.tlsDone))
+	}
+	// Time spent before DNS or dialing started, or queueing for a reused
+	// connection.
+	for _, first := range []time.Time{t.dnsStart, t.connectStart, t.gotConn} {
+		if !first.IsZero() {
+			timings.Blocked = millis(between(t.start, first))
+			break
+		}
+	}
+	// HAR requires send, wait and receive to be non-negative.
+	timings.Send = max(timings.Send, 0)
+	timings.Wait = max(timings.Wait, 0)
+	timings.Receive = max(timings.Receive, 0)
+	return timings
+}
+
+func millis(d time.Duration) float64 {
+	if d < 0 {
+		return -1
+	}
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,139 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
+)
+
+func Test_HARRecorder_golden(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cr3t"})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"refresh_token":"rt-1","data":"` + strings.Repeat("x", 64) + `"}`))
+	}))
+	defer srv.Close()
+
+	clientCfg := DefaultHTTPClientConfig()
+	clientCfg.WithHeader("Authorization", "Bearer xyz")
+	c := newTestClient(t, &clientCfg)
+
+	harCfg := DefaultHARConfig()
+	harCfg.WithMaxBodySize(40).WithMaxEntries(2)
+	recorder := NewHARRecorder(harCfg)
+
+	send := func(path string) {
+		reqCfg := DefaultHTTPRequestConfig()
+		reqCfg.WithMethod(http.MethodPost).
+			WithURL(srv.URL + path).
+			WithBody(map[string]any{"password": "hunter2", "user": "bob"})
+		if _, err := c.ProcessRequest(context.Background(), &dto.RequestConfig{ReqConfig: &reqCfg}); err != nil {
+			t.Fatalf("ProcessRequest: %v", err)
+		}
+	}
+
+	send("/before")
+	c.StartHAR(recorder)
+	send("/one?token=abc&page=1")
+	send("/two")
+	send("/three")
+	if got := c.StopHAR(); got != recorder {
+		t.Fatalf("StopHAR returned %p want %p", got, recorder)
+	}
+	send("/after")
+
+	entries := recorder.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("entries=%d want 2 (MaxEntries)", len(entries))
+	}
+	if !strings.HasSuffix(entries[0].Request.URL, "/two") || !strings.HasSuffix(entries[1].Request.URL, "/three") {
+		t.Fatalf("urls=%s,%s", entries[0].Request.URL, entries[1].Request.URL)
+	}
+
+	e := entries[1]
+	if e.Response.Status != http.StatusOK || e.Response.StatusText != "OK" || e.Request.HTTPVersion != "HTTP/1.1" {
+		t.Fatalf("entry=%+v", e)
+	}
+	if e.Time <= 0 || e.Timings.Wait < 0 || e.Timings.Send < 0 || e.Timings.Receive < 0 || e.ServerIPAddress != "127.0.0.1" {
+		t.Fatalf("timings=%+v time=%v ip=%q", e.Timings, e.Time, e.ServerIPAddress)
+	}
+	for _, h := range e.Request.Headers {
+		if h.Name == "Authorization" && h.Value != utils.RedactedValue {
+			t.Fatalf("authorization not redacted: %s", h.Value)
+		}
+	}
+	if len(e.Response.Cookies) != 1 || e.Response.Cookies[0].Value != utils.RedactedValue {
+		t.Fatalf("cookies=%+v", e.Response.Cookies)
+	}
+	if e.Request.PostData == nil || strings.Contains(e.Request.PostData.Text, "hunter2") {
+		t.Fatalf("postData=%+v", e.Request.PostData)
+	}
+	if content := e.Response.Content; len(content.Text) != 40 || content.Comment != "truncated" || strings.Contains(content.Text, "rt-1") || content.Size <= 40 {
+		t.Fatalf("content=%+v", content)
+	}
+
+	recorder.Reset()
+	c.StartHAR(recorder)
+	send("/one?token=abc&page=1")
+	entry := recorder.Entries()[0]
+	if strings.Contains(entry.Request.URL, "abc") {
+		t.Fatalf("url not redacted: %s", entry.Request.URL)
+	}
+	query := map[string]string{}
+	for _, q := range entry.Request.QueryString {
+		query[q.Name] = q.Value
+	}
+	if query["page"] != "1" || query["token"] != utils.RedactedValue {
+		t.Fatalf("queryString=%v", query)
+	}
+
+	path := filepath.Join(t.TempDir(), "traffic.har")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc HAR
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if doc.Log.Version != "1.2" || len(doc.Log.Entries) != 1 || doc.Log.Entries[0].Request.Method != http.MethodPost {
+		t.Fatalf("doc=%+v", doc.Log)
+	}
+}
+
+func Test_HARRecorder_transportError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	clientCfg := DefaultHTTPClientConfig()
+	c := newTestClient(t, &clientCfg)
+	// A zero config still redacts, with the default redactor.
+	recorder := NewHARRecorder(HARConfig{})
+	c.StartHAR(recorder)
+
+	reqCfg := DefaultHTTPRequestConfig()
+	reqCfg.WithURL(url + "/x?token=abc")
+	if _, err := c.ProcessRequest(context.Background(), &dto.RequestConfig{ReqConfig: &reqCfg}); err == nil {
+		t.Fatalf("expected error")
+	}
+	entries := recorder.Entries()
+	if len(entries) != 1 || entries[0].Error == "" || entries[0].Response.Status != 0 {
+		t.Fatalf("entries=%+v", entries)
+	}
+	if got := entries[0].Error; strings.Contains(got, "abc") || !strings.Contains(got, "token="+utils.RedactedValue) {
+		t.Fatalf("error not redacted: %s", got)
+	}
+}
@@ -0,0 +1,208 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// newTransport applies cfg to a transport with gonetic's pool defaults.
+func newTransport(cfg config.TransportConfig) *http.Transport {
+	t := &http.Transport{
+		MaxIdleConns:          50,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		ExpectContinueTimeout: cfg.ExpectContinueTimeout,
+		ForceAttemptHTTP2:     cfg.HTTP2(),
+	}
+	if cfg.MaxIdleConns > 0 {
+		t.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+	if cfg.TLSHandshakeTimeout > 0 {
+		t.TLSHandshakeTimeout = cfg.TLSHandshakeTimeout
+	}
+	if cfg.H2C {
+		t.Protocols = new(http.Protocols)
+		t.Protocols.SetHTTP2(true)
+		t.Protocols.SetUnencryptedHTTP2(true)
+	}
+	return t
+}
+
+// poolTracker follows the connections a client dials and whether they carry
+// a request, since net/http does not expose its pool.
+type poolTracker struct {
+	mu    sync.Mutex
+	conns map[*trackedConn]int // active requests per connection
+}
+
+func newPoolTracker() *poolTracker {
+	return &poolTracker{conns: make(map[*trackedConn]int)}
+}
+
+type trackedConn struct {
+	net.Conn
+	pool  *poolTracker
+	host  string
+	close sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.close.Do(func() {
+		c.pool.mu.Lock()
+		delete(c.pool.conns, c)
+		c.pool.mu.Unlock()
+	})
+	return c.Conn.Close()
+}
+
+// dial wraps base so its connections are counted under the dialed address.
+func (p *poolTracker) dial(base dialFunc) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := base(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		tracked := &trackedConn{Conn: conn, pool: p, host: addr}
+		p.mu.Lock()
+		p.conns[tracked] = 0
+		p.mu.Unlock()
+		return tracked, nil
+	}
+}
+
+func (p *poolTracker) acquire(c *trackedConn) {
+	p.mu.Lock()
+	if n, ok := p.conns[c]; ok {
+		p.conns[c] = n + 1
+	}
+	p.mu.Unlock()
+}
+
+func (p *poolTracker) release(c *trackedConn) {
+	p.mu.Lock()
+	if n, ok := p.conns[c]; ok && n > 0 {
+		p.conns[c] = n - 1
+	}
+	p.mu.Unlock()
+}
+
+// stats groups open connections by host, sorted by host.
+func (p *poolTracker) stats() []dto.HostPoolStats {
+	p.mu.Lock()
+	byHost := make(map[string]*dto.HostPoolStats)
+	for c, active := range p.conns {
+		s, ok := byHost[c.host]
+		if !ok {
+			s = &dto.HostPoolStats{Host: c.host}
+			byHost[c.host] = s
+		}
+		if active > 0 {
+			s.InUse++
+		} else {
+			s.Idle++
+		}
+	}
+	p.mu.Unlock()
+	out := make([]dto.HostPoolStats, 0, len(byHost))
+	for _, s := range byHost {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Host < out[j].Host })
+	return out
+}
+
+// trackedConnOf unwraps the connection net/http reports to GotConn.
+func trackedConnOf(conn net.Conn) *trackedConn {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	tracked, _ := conn.(*trackedConn)
+	return tracked
+}
+
+// trackingTransport marks the connection a request got as in use until its
+// response body is closed or read to the end.
+type trackingTransport struct {
+	base http.RoundTripper
+	pool *poolTracker
+}
+
+func (t *trackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var (
+		mu  sync.Mutex
+		got *trackedConn
+	)
+	ctx := httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			tracked := trackedConnOf(info.Conn)
+			if tracked == nil {
+				return
+			}
+			t.pool.acquire(tracked)
+			mu.Lock()
+			// net/http retries some requests on a new connection
+			previous := got
+			got = tracked
+			mu.Unlock()
+			if previous != nil {
+				t.pool.release(previous)
+			}
+		},
+	})
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	mu.Lock()
+	tracked := got
+	mu.Unlock()
+	if tracked == nil {
+		return resp, err
+	}
+	if err != nil || resp.StatusCode == http.StatusSwitchingProtocols {
+		t.pool.release(tracked)
+		return resp, err
+	}
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: func() { t.pool.release(tracked) }}
+	return resp, nil
+}
+
+func (t *trackingTransport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.once.Do(b.release)
+	}
+	return n, err
+}
+
+func (b *releaseBody) Close() error {
+	b.once.Do(b.release)
+	return b.ReadCloser.Close()
+}
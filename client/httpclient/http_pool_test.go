@@ -0,0 +1,258 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+func getBody(t *testing.T, c *HTTPClient, url string) (string, error) {
+	t.Helper()
+	reqCfg := DefaultHTTPRequestConfig()
+	reqCfg.WithURL(url)
+	resp, err := c.ProcessRequest(context.Background(), &dto.RequestConfig{ReqConfig: &reqCfg})
+	return string(resp.Body), err
+}
+
+func Test_HTTPClient_Protocols_golden(t *testing.T) {
+	echoProto := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	})
+
+	h2c := httptest.NewUnstartedServer(echoProto)
+	h2c.Config.Protocols = new(http.Protocols)
+	h2c.Config.Protocols.SetHTTP1(true)
+	h2c.Config.Protocols.SetUnencryptedHTTP2(true)
+	h2c.Start()
+	t.Cleanup(h2c.Close)
+
+	h2 := httptest.NewUnstartedServer(echoProto)
+	h2.EnableHTTP2 = true
+	h2.StartTLS()
+	t.Cleanup(h2.Close)
+	caFile := writePEM(t, filepath.Join(t.TempDir(), "ca.pem"), "CERTIFICATE", h2.Certificate().Raw)
+	trusted := config.TLSConfig{CAFiles: []string{caFile}}
+
+	noHTTP2, yesHTTP2 := false, true
+	tests := []struct {
+		name      string
+		url       string
+		tls       config.TLSConfig
+		transport config.TransportConfig
+		want      string
+	}{
+		{name: "cleartext defaults to http/1.1", url: h2c.URL, want: "HTTP/1.1"},
+		{name: "h2c", url: h2c.URL, transport: config.TransportConfig{H2C: true}, want: "HTTP/2.0"},
+		{name: "tls defaults to http/1.1", url: h2.URL, tls: trusted, want: "HTTP/1.1"},
+		{name: "http/2 over tls", url: h2.URL, tls: trusted, transport: config.TransportConfig{ForceAttemptHTTP2: &yesHTTP2}, want: "HTTP/2.0"},
+		{name: "http/2 disabled", url: h2.URL, tls: trusted, transport: config.TransportConfig{ForceAttemptHTTP2: &noHTTP2}, want: "HTTP/1.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultHTTPClientConfig()
+			cfg.WithTLS(tt.tls).WithTransport(tt.transport)
+			c := newTestClient(t, &cfg)
+			t.Cleanup(c.CloseIdleConnections)
+
+			got, err := getBody(t, c, tt.url)
+			if err != nil || got != tt.want {
+				t.Fatalf("proto=%q err=%v want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_HTTPClient_TransportOptions_golden(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte("late"))
+	}))
+	t.Cleanup(slow.Close)
+
+	tests := []struct {
+		name      string
+		transport config.TransportConfig
+		wantErr   string
+	}{
+		{name: "no header timeout"},
+		{
+			name:      "response header timeout",
+			transport: config.TransportConfig{ResponseHeaderTimeout: 50 * time.Millisecond},
+			wantErr:   "timeout awaiting response headers",
+		},
+		{
+			name:      "invalid transport",
+			transport: config.TransportConfig{MaxIdleConns: -1},
+			wantErr:   "http client test: max_idle_conns must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultHTTPClientConfig()
+			cfg.WithTransport(tt.transport)
+			c := newTestClient(t, &cfg)
+
+			got, err := getBody(t, c, slow.URL)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err=%v want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != "late" {
+				t.Fatalf("body=%q err=%v", got, err)
+			}
+		})
+	}
+}
+
+func Test_newTransport_golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  config.TransportConfig
+		want *http.Transport
+	}{
+		{
+			name: "defaults",
+			want: &http.Transport{MaxIdleConns: 50, IdleConnTimeout: 90 * time.Second, TLSHandshakeTimeout: 10 * time.Second},
+		},
+		{
+			name: "tuned",
+			cfg: config.TransportConfig{
+				MaxIdleConns: 100, MaxIdleConnsPerHost: 10, MaxConnsPerHost: 20,
+				IdleConnTimeout: time.Minute, TLSHandshakeTimeout: 5 * time.Second,
+				ResponseHeaderTimeout: 3 * time.Second, ExpectContinueTimeout: time.Second,
+			},
+			want: &http.Transport{
+				MaxIdleConns: 100, MaxIdleConnsPerHost: 10, MaxConnsPerHost: 20,
+				IdleConnTimeout: time.Minute, TLSHandshakeTimeout: 5 * time.Second,
+				ResponseHeaderTimeout: 3 * time.Second, ExpectContinueTimeout: time.Second,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newTransport(tt.cfg)
+			fields := func(tr *http.Transport) []any {
+				return []any{tr.MaxIdleConns, tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost, tr.IdleConnTimeout,
+					tr.TLSHandshakeTimeout, tr.ResponseHeaderTimeout, tr.ExpectContinueTimeout, tr.ForceAttemptHTTP2}
+			}
+			if !reflect.DeepEqual(fields(got), fields(tt.want)) {
+				t.Fatalf("transport=%v want %v", fields(got), fields(tt.want))
+			}
+		})
+	}
+}
+
+func Test_HTTPClient_PoolStats_golden(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/block" {
+			entered <- struct{}{}
+			<-release
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+	host := srv.Listener.Addr().String()
+
+	c := newTestClient(t, nil)
+	if got := c.PoolStats(); len(got) != 0 {
+		t.Fatalf("stats before any request=%v", got)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := getBody(t, c, srv.URL+"/block")
+		done <- err
+	}()
+	<-entered
+	if got, want := c.PoolStats(), []dto.HostPoolStats{{Host: host, InUse: 1}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("in flight stats=%v want %v", got, want)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if got, want := c.PoolStats(), []dto.HostPoolStats{{Host: host, Idle: 1}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after response stats=%v want %v", got, want)
+	}
+	if _, err := getBody(t, c, srv.URL); err != nil {
+		t.Fatalf("reuse request: %v", err)
+	}
+	if got, want := c.PoolStats(), []dto.HostPoolStats{{Host: host, Idle: 1}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after reuse stats=%v want %v", got, want)
+	}
+	c.CloseIdleConnections()
+	if got := c.PoolStats(); len(got) != 0 {
+		t.Fatalf("after close stats=%v", got)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func Test_HTTPClient_RoundTripper_golden(t *testing.T) {
+	h3 := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Proto:      "HTTP/3.0",
+			Header:     http.Header{},
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	})
+
+	tests := []struct {
+		name    string
+		tls     config.TLSConfig
+		wantErr string
+	}{
+		{name: "custom round tripper"},
+		{name: "tls with custom round tripper", tls: config.TLSConfig{MinVersion: "1.3"}, wantErr: "cannot be combined with a custom round tripper"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultHTTPClientConfig()
+			cfg.WithRoundTripper(h3).WithTLS(tt.tls).WithTiming(true)
+			c := newTestClient(t, &cfg)
+
+			reqCfg := DefaultHTTPRequestConfig()
+			reqCfg.WithURL("https://h3.example/")
+			resp, err := c.ProcessRequest(context.Background(), &dto.RequestConfig{ReqConfig: &reqCfg})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err=%v want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || resp.StatusCode != http.StatusOK || resp.Timing.Protocol != "HTTP/3.0" {
+				t.Fatalf("resp=%+v err=%v", resp, err)
+			}
+			if c.PoolStats() != nil {
+				t.Fatalf("pool stats with custom round tripper")
+			}
+			if _, _, ok := c.CurlArgs(); ok {
+				t.Fatalf("curl should not replace a custom round tripper")
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// newForwardProxy serves plain HTTP proxy requests, requiring basic auth.
+func newForwardProxy(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	wantAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("svc:pw"))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Proxy-Authorization") != wantAuth {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		hits.Add(1)
+		out, err := http.NewRequestWithContext(r.Context(), r.Method, r.URL.String(), r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		resp, err := http.DefaultTransport.RoundTrip(out)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newSOCKS5Proxy accepts username/password auth and CONNECT to IPv4 or
+// domain targets.
+func newSOCKS5Proxy(t *testing.T, hits *atomic.Int32) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSOCKS5(conn, hits)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func serveSOCKS5(conn net.Conn, hits *atomic.Int32) {
+	defer conn.Close()
+	buf := make([]byte, 262)
+	// greeting: VER NMETHODS METHODS; choose username/password
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+		return
+	}
+	_, _ = conn.Write([]byte{5, 2})
+	// RFC 1929: VER ULEN UNAME PLEN PASSWD
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return
+	}
+	user := make([]byte, buf[1])
+	_, _ = io.ReadFull(conn, user)
+	_, _ = io.ReadFull(conn, buf[:1])
+	pass := make([]byte, buf[0])
+	_, _ = io.ReadFull(conn, pass)
+	if string(user) != "svc" || string(pass) != "pw" {
+		_, _ = conn.Write([]byte{1, 1})
+		return
+	}
+	_, _ = conn.Write([]byte{1, 0})
+	// request: VER CMD RSV ATYP ADDR PORT
+	if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+		return
+	}
+	var host string
+	switch buf[3] {
+	case 1:
+		_, _ = io.ReadFull(conn, buf[:4])
+		host = net.IP(buf[:4]).String()
+	case 3:
+		_, _ = io.ReadFull(conn, buf[:1])
+		name := make([]byte, buf[0])
+		_, _ = io.ReadFull(conn, name)
+		host = string(name)
+	default:
+		return
+	}
+	_, _ = io.ReadFull(conn, buf[:2])
+	port := binary.BigEndian.Uint16(buf[:2])
+	target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+	if err != nil {
+		_, _ = conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer target.Close()
+	hits.Add(1)
+	_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+	go func() { _, _ = io.Copy(target, conn) }()
+	_, _ = io.Copy(conn, target)
+}
+
+func Test_HTTPClient_Proxy_golden(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer target.Close()
+
+	var httpHits, socksHits atomic.Int32
+	forward := newForwardProxy(t, &httpHits)
+	socks := newSOCKS5Proxy(t, &socksHits)
+
+	tests := []struct {
+		name      string
+		svcProxy  config.ProxyConfig
+		client    *config.ProxyConfig
+		wantHTTP  int32
+		wantSOCKS int32
+		wantErr   bool
+	}{
+		{
+			name:     "service http proxy with auth",
+			svcProxy: config.ProxyConfig{URL: forward.URL, Username: "svc", Password: "pw"},
+			wantHTTP: 1,
+		},
+		{
+			name:     "wrong proxy password",
+			svcProxy: config.ProxyConfig{URL: forward.URL, Username: "svc", Password: "nope"},
+			wantErr:  true,
+		},
+		{
+			name:      "socks5 client override",
+			svcProxy:  config.ProxyConfig{URL: forward.URL, Username: "svc", Password: "pw"},
+			client:    &config.ProxyConfig{URL: "socks5://" + socks, Username: "svc", Password: "pw"},
+			wantSOCKS: 1,
+		},
+		{
+			name:     "client bypasses service proxy",
+			svcProxy: config.ProxyConfig{URL: forward.URL, Username: "svc", Password: "pw"},
+			client:   &config.ProxyConfig{Disabled: true},
+		},
+		{
+			name:     "no_proxy rule",
+			svcProxy: config.ProxyConfig{URL: forward.URL, Username: "svc", Password: "pw", NoProxy: []string{"127.0.0.0/8"}},
+		},
+		{
+			name:     "invalid proxy url",
+			svcProxy: config.ProxyConfig{URL: "ftp://proxy"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpHits.Store(0)
+			socksHits.Store(0)
+
+			netCfg := &config.NetSvcConfig{Proxy: tt.svcProxy}
+			cfg := DefaultHTTPClientConfig()
+			cfg.Proxy = tt.client
+			c := NewHTTPClient("test", netCfg, &cfg)
+
+			reqCfg := DefaultHTTPRequestConfig()
+			reqCfg.WithURL(target.URL)
+			resp, err := c.ProcessRequest(context.Background(), &dto.RequestConfig{ReqConfig: &reqCfg})
+			if tt.wantErr {
+				if err == nil && resp.StatusCode < 400 {
+					t.Fatalf("expected failure, got %d", resp.StatusCode)
+				}
+				return
+			}
+			if err != nil || string(resp.Body) != "ok" {
+				t.Fatalf("resp=%q err=%v", resp.Body, err)
+			}
+			if httpHits.Load() != tt.wantHTTP || socksHits.Load() != tt.wantSOCKS {
+				t.Fatalf("http hits=%d socks hits=%d want %d/%d", httpHits.Load(), socksHits.Load(), tt.wantHTTP, tt.wantSOCKS)
+			}
+		})
+	}
+}
@@ -2,15 +2,17 @@ package httpclient
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
 )
 
 // HTTPRequestConfig is immutable input (safe to reuse).
 type HTTPRequestConfig struct {
-	Method string `json:"method" yaml:"method"`
-	URL    string
+	Method string                 `json:"method" yaml:"method"`
+	URL    string                 `json:"url" yaml:"url"`
 	Body   map[string]interface{} `json:"body" yaml:"body"`
 	// BodyType application/json, application/x-www-form-urlencoded
 	BodyType string            `json:"body_type" yaml:"body_type"`
@@ -93,3 +95,25 @@ func (r *HTTPRequest) Header(k string) string {
 	}
 	return r.Headers[k]
 }
+
+// Describe returns the method, URL, configured headers and encoded body. Headers
+// added by the client at send time, such as Authorization, are not included.
+func (c *HTTPRequestConfig) Describe() (dto.RequestDescription, error) {
+	desc := dto.RequestDescription{
+		ClientType: NetClientHTTPRef,
+		Method:     c.Method,
+		URL:        c.URL,
+		Headers:    make(map[string]string, len(c.Headers)),
+	}
+	for k, v := range c.Headers {
+		desc.Headers[k] = v
+	}
+	if len(c.Body) > 0 {
+		body, _, err := utils.PrepareBody(c.Body, c.BodyType)
+		if err != nil {
+			return dto.RequestDescription{}, fmt.Errorf("describe: %w", err)
+		}
+		desc.Body = body
+	}
+	return desc, nil
+}
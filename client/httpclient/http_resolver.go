@@ -0,0 +1,211 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/joy-dx/gonetic/config"
+)
+
+const defaultFallbackDelay = 300 * time.Millisecond
+
+// resolverDialer dials through config.ResolverConfig: host overrides, a
+// custom DNS server, a lookup cache and IP family ordering.
+type resolverDialer struct {
+	cfg      config.ResolverConfig
+	dialer   *net.Dialer
+	resolver *net.Resolver
+
+	mu    sync.Mutex
+	cache map[string]cachedLookup
+}
+
+type cachedLookup struct {
+	ips     []net.IP
+	expires time.Time
+}
+
+func newResolverDialer(cfg config.ResolverConfig, dialer *net.Dialer) *resolverDialer {
+	d := &resolverDialer{cfg: cfg, dialer: dialer, resolver: net.DefaultResolver}
+	if cfg.Server != "" {
+		server := cfg.ServerAddr()
+		d.resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, server)
+			},
+		}
+	}
+	if cfg.CacheTTL > 0 {
+		d.cache = make(map[string]cachedLookup)
+	}
+	return d
+}
+
+// DialContext implements http.Transport.DialContext.
+func (d *resolverDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ips, err := d.lookup(ctx, host, port)
+	if err != nil {
+		return nil, err
+	}
+	ips = d.order(ips)
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("resolve %s: no %s addresses", host, d.cfg.IPFamily)
+	}
+	return d.dialAddrs(ctx, network, ips, port)
+}
+
+func (d *resolverDialer) lookup(ctx context.Context, host, port string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	for _, key := range []string{net.JoinHostPort(host, port), host} {
+		if addrs, ok := d.cfg.Hosts[key]; ok {
+			ips := make([]net.IP, 0, len(addrs))
+			for _, addr := range addrs {
+				ips = append(ips, net.ParseIP(addr))
+			}
+			return ips, nil
+		}
+	}
+
+	if d.cache != nil {
+		d.mu.Lock()
+		entry, ok := d.cache[host]
+		d.mu.Unlock()
+		if ok && time.Now().Before(entry.expires) {
+			return entry.ips, nil
+		}
+	}
+	addrs, err := d.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP)
+	}
+	if d.cache != nil {
+		d.mu.Lock()
+		d.cache[host] = cachedLookup{ips: ips, expires: time.Now().Add(d.cfg.CacheTTL)}
+		d.mu.Unlock()
+	}
+	return ips, nil
+}
+
+// order filters or reorders ips for the configured family.
+func (d *resolverDialer) order(ips []net.IP) []net.IP {
+	var v4, v6 []net.IP
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			v4 = append(v4, ip)
+		} else {
+			v6 = append(v6, ip)
+		}
+	}
+	switch d.cfg.IPFamily {
+	case config.IPFamilyV4Only:
+		return v4
+	case config.IPFamilyV6Only:
+		return v6
+	case config.IPFamilyPreferIPv4:
+		return append(v4, v6...)
+	case config.IPFamilyPreferIPv6:
+		return append(v6, v4...)
+	default:
+		return ips
+	}
+}
+
+// dialAddrs dials the family of ips[0] first and, after FallbackDelay or its
+// failure, races the other family (RFC 8305 Happy Eyeballs).
+func (d *resolverDialer) dialAddrs(ctx context.Context, network string, ips []net.IP, port string) (net.Conn, error) {
+	primaryV4 := ips[0].To4() != nil
+	var primaries, fallbacks []net.IP
+	for _, ip := range ips {
+		if (ip.To4() != nil) == primaryV4 {
+			primaries = append(primaries, ip)
+		} else {
+			fallbacks = append(fallbacks, ip)
+		}
+	}
+	if len(fallbacks) == 0 || d.cfg.FallbackDelay < 0 {
+		return d.dialSerial(ctx, network, ips, port)
+	}
+	delay := d.cfg.FallbackDelay
+	if delay == 0 {
+		delay = defaultFallbackDelay
+	}
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan result, 2)
+	race := func(addrs []net.IP) {
+		conn, err := d.dialSerial(ctx, network, addrs, port)
+		results <- result{conn, err}
+	}
+	go race(primaries)
+
+	fallbackTimer := time.NewTimer(delay)
+	defer fallbackTimer.Stop()
+	started, pending := 1, 1
+	var errs []error
+	for {
+		select {
+		case <-fallbackTimer.C:
+			if started == 1 {
+				started, pending = 2, pending+1
+				go race(fallbacks)
+			}
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				// Close a connection the losing racer may still return.
+				if pending > 0 {
+					go func() {
+						if late := <-results; late.conn != nil {
+							late.conn.Close()
+						}
+					}()
+				}
+				return res.conn, nil
+			}
+			errs = append(errs, res.err)
+			if started == 1 {
+				started, pending = 2, pending+1
+				go race(fallbacks)
+				continue
+			}
+			if pending == 0 {
+				return nil, errors.Join(errs...)
+			}
+		}
+	}
+}
+
+func (d *resolverDialer) dialSerial(ctx context.Context, network string, ips []net.IP, port string) (net.Conn, error) {
+	var errs []error
+	for _, ip := range ips {
+		conn, err := d.dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,245 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// newDNSServer answers A queries for any name with 127.0.0.1 and AAAA
+// queries with no records, counting the A queries it sees.
+func newDNSServer(t *testing.T, aQueries *atomic.Int32) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pc.Close() })
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := dnsAnswer(buf[:n], aQueries); resp != nil {
+				_, _ = pc.WriteTo(resp, addr)
+			}
+		}
+	}()
+	return pc.LocalAddr().String()
+}
+
+func dnsAnswer(query []byte, aQueries *atomic.Int32) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+	// question: labels, zero byte, QTYPE, QCLASS
+	end := 12
+	for end < len(query) && query[end] != 0 {
+		end += int(query[end]) + 1
+	}
+	end += 5
+	if end > len(query) {
+		return nil
+	}
+	qtype := binary.BigEndian.Uint16(query[end-4 : end-2])
+
+	resp := make([]byte, 0, end+16)
+	resp = append(resp, query[0], query[1], 0x81, 0x80, 0, 1, 0, 0, 0, 0, 0, 0)
+	resp = append(resp, query[12:end]...)
+	if qtype == 1 {
+		aQueries.Add(1)
+		resp[7] = 1
+		// name pointer, A, IN, TTL 60, RDLENGTH 4, 127.0.0.1
+		resp = append(resp, 0xc0, 0x0c, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 127, 0, 0, 1)
+	}
+	return resp
+}
+
+func Test_HTTPClient_Resolver_golden(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer target.Close()
+	_, port, _ := net.SplitHostPort(target.Listener.Addr().String())
+
+	var aQueries atomic.Int32
+	dnsServer := newDNSServer(t, &aQueries)
+
+	tests := []struct {
+		name     string
+		host     string
+		resolver config.ResolverConfig
+		wantErr  string
+	}{
+		{
+			name:     "host override",
+			host:     "api.staging.test",
+			resolver: config.ResolverConfig{Hosts: map[string][]string{"api.staging.test": {"127.0.0.1"}}},
+		},
+		{
+			name:     "host and port override",
+			host:     "api.staging.test",
+			resolver: config.ResolverConfig{Hosts: map[string][]string{"api.staging.test:" + port: {"127.0.0.1"}}},
+		},
+		{
+			name:     "custom dns server",
+			host:     "internal.corp.test",
+			resolver: config.ResolverConfig{Server: dnsServer},
+		},
+		{
+			name:     "ipv6 only without ipv6 addresses",
+			host:     "api.staging.test",
+			resolver: config.ResolverConfig{Hosts: map[string][]string{"api.staging.test": {"127.0.0.1"}}, IPFamily: config.IPFamilyV6Only},
+			wantErr:  "no ipv6 addresses",
+		},
+		{
+			name:     "invalid host override",
+			host:     "api.staging.test",
+			resolver: config.ResolverConfig{Hosts: map[string][]string{"api.staging.test": {"not-an-ip"}}},
+			wantErr:  `http client test: resolver host "api.staging.test": "not-an-ip" is not an IP address`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultHTTPClientConfig()
+			cfg.WithResolver(tt.resolver)
+			c := newTestClient(t, &cfg)
+
+			reqCfg := DefaultHTTPRequestConfig()
+			reqCfg.WithURL("http://" + net.JoinHostPort(tt.host, port) + "/")
+			resp, err := c.ProcessRequest(context.Background(), &dto.RequestConfig{ReqConfig: &reqCfg})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err=%v want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || string(resp.Body) != "ok" {
+				t.Fatalf("resp=%q err=%v", resp.Body, err)
+			}
+		})
+	}
+}
+
+func Test_resolverDialer_Cache_golden(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+
+	tests := []struct {
+		name        string
+		ttl         time.Duration
+		wantQueries int32
+	}{
+		{name: "no cache", wantQueries: 3},
+		{name: "cached", ttl: time.Minute, wantQueries: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var aQueries atomic.Int32
+			d := newResolverDialer(config.ResolverConfig{Server: newDNSServer(t, &aQueries), CacheTTL: tt.ttl}, &net.Dialer{Timeout: time.Second})
+			for range 3 {
+				conn, err := d.DialContext(context.Background(), "tcp", net.JoinHostPort("cache.test", port))
+				if err != nil {
+					t.Fatalf("dial: %v", err)
+				}
+				conn.Close()
+			}
+			if got := aQueries.Load(); got != tt.wantQueries {
+				t.Fatalf("A queries=%d want %d", got, tt.wantQueries)
+			}
+		})
+	}
+}
+
+func Test_resolverDialer_HappyEyeballs_golden(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+
+	tests := []struct {
+		name     string
+		resolver config.ResolverConfig
+		wantAddr string
+		wantErr  bool
+	}{
+		{
+			name:     "falls back to ipv4 when ipv6 fails",
+			resolver: config.ResolverConfig{Hosts: map[string][]string{"dual.test": {"::1", "127.0.0.1"}}, FallbackDelay: 50 * time.Millisecond},
+			wantAddr: ln.Addr().String(),
+		},
+		{
+			name:     "prefer ipv4 dials ipv4 first",
+			resolver: config.ResolverConfig{Hosts: map[string][]string{"dual.test": {"::1", "127.0.0.1"}}, IPFamily: config.IPFamilyPreferIPv4},
+			wantAddr: ln.Addr().String(),
+		},
+		{
+			name:     "serial dialing",
+			resolver: config.ResolverConfig{Hosts: map[string][]string{"dual.test": {"::1", "127.0.0.1"}}, FallbackDelay: -1},
+			wantAddr: ln.Addr().String(),
+		},
+		{
+			name:     "ipv6 only",
+			resolver: config.ResolverConfig{Hosts: map[string][]string{"dual.test": {"::1", "127.0.0.1"}}, IPFamily: config.IPFamilyV6Only},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newResolverDialer(tt.resolver, &net.Dialer{Timeout: time.Second})
+			conn, err := d.DialContext(context.Background(), "tcp", net.JoinHostPort("dual.test", port))
+			if tt.wantErr {
+				if err == nil {
+					conn.Close()
+					t.Fatalf("expected dial failure")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("dial: %v", err)
+			}
+			defer conn.Close()
+			if got := conn.RemoteAddr().String(); got != tt.wantAddr {
+				t.Fatalf("remote=%s want %s", got, tt.wantAddr)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package httpclient
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joy-dx/gonetic/config"
+)
+
+// PinMismatchError is returned when a server's certificate matches none of
+// the configured pins. It is never retried.
+type PinMismatchError struct {
+	Host string
+	// SPKI and Cert are the leaf certificate's pins in config.TLSConfig form
+	SPKI string
+	Cert string
+}
+
+func (e *PinMismatchError) Error() string {
+	return fmt.Sprintf("certificate pin mismatch for %s: got %s", e.Host, e.SPKI)
+}
+
+// Temporary implements the check in utils.IsTemporaryErr.
+func (e *PinMismatchError) Temporary() bool { return false }
+
+// tlsSource builds the tls.Config for a client and reloads the CA,
+// certificate and key files when they change.
+type tlsSource struct {
+	cfg config.TLSConfig
+
+	mu        sync.RWMutex
+	roots     *x509.CertPool
+	cert      *tls.Certificate
+	loadErr   error
+	modTimes  map[string]time.Time
+	checkedAt time.Time
+}
+
+func newTLSSource(cfg config.TLSConfig) *tlsSource {
+	src := &tlsSource{cfg: cfg}
+	src.loadErr = src.reload()
+	return src
+}
+
+// tlsConfig returns the client tls.Config. Verification against custom CAs
+// and pinning happen in VerifyConnection so rotated files apply to new
+// connections.
+func (s *tlsSource) tlsConfig() (*tls.Config, error) {
+	version, err := s.cfg.TLSVersion()
+	if err != nil {
+		return nil, err
+	}
+	tlsCfg := &tls.Config{
+		MinVersion: version,
+		ServerName: s.cfg.ServerName,
+	}
+	if s.cfg.CertFile != "" {
+		tlsCfg.GetClientCertificate = s.clientCertificate
+	}
+	if len(s.cfg.CAFiles) > 0 {
+		// Checked against the current roots in verify instead.
+		tlsCfg.InsecureSkipVerify = true
+	}
+	if len(s.cfg.CAFiles) > 0 || len(s.cfg.Pins) > 0 {
+		tlsCfg.VerifyConnection = s.verify
+	}
+	return tlsCfg, nil
+}
+
+// Reload reads the files now, keeping the previous material on failure.
+func (s *tlsSource) Reload() error {
+	if err := s.reload(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.loadErr = nil
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *tlsSource) reload() error {
+	modTimes := make(map[string]time.Time)
+	stat := func(path string) {
+		if info, err := os.Stat(path); err == nil {
+			modTimes[path] = info.ModTime()
+		}
+	}
+
+	var roots *x509.CertPool
+	if len(s.cfg.CAFiles) > 0 {
+		roots = x509.NewCertPool()
+		if s.cfg.IncludeSystemRoots {
+			system, err := x509.SystemCertPool()
+			if err != nil {
+				return fmt.Errorf("load system roots: %w", err)
+			}
+			roots = system
+		}
+		for _, path := range s.cfg.CAFiles {
+			stat(path)
+			pem, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("read ca file: %w", err)
+			}
+			if !roots.AppendCertsFromPEM(pem) {
+				return fmt.Errorf("ca file %s: no certificates found", path)
+			}
+		}
+	}
+
+	var cert *tls.Certificate
+	if s.cfg.CertFile != "" {
+		stat(s.cfg.CertFile)
+		stat(s.cfg.KeyFile)
+		pair, err := tls.LoadX509KeyPair(s.cfg.CertFile, s.cfg.KeyFile)
+		if err != nil {
+			return fmt.Errorf("load client certificate: %w", err)
+		}
+		cert = &pair
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roots = roots
+	s.cert = cert
+	s.modTimes = modTimes
+	s.checkedAt = time.Now()
+	return nil
+}
+
+// maybeReload reloads when ReloadInterval has passed and a file changed.
+func (s *tlsSource) maybeReload() {
+	if s.cfg.ReloadInterval <= 0 {
+		return
+	}
+	s.mu.Lock()
+	if time.Since(s.checkedAt) < s.cfg.ReloadInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.checkedAt = time.Now()
+	changed := false
+	for path, modTime := range s.modTimes {
+		if info, err := os.Stat(path); err == nil && !info.ModTime().Equal(modTime) {
+			changed = true
+		}
+	}
+	s.mu.Unlock()
+
+	if changed {
+		err := s.reload()
+		s.mu.Lock()
+		s.loadErr = err
+		s.mu.Unlock()
+	}
+}
+
+func (s *tlsSource) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	s.maybeReload()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.cert == nil {
+		return nil, fmt.Errorf("client certificate unavailable: %w", s.loadErr)
+	}
+	return s.cert, nil
+}
+
+func (s *tlsSource) verify(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: server sent no certificates")
+	}
+	leaf := cs.PeerCertificates[0]
+
+	if len(s.cfg.CAFiles) > 0 {
+		s.maybeReload()
+		s.mu.RLock()
+		roots, loadErr := s.roots, s.loadErr
+		s.mu.RUnlock()
+		if roots == nil {
+			return fmt.Errorf("tls roots unavailable: %w", loadErr)
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range cs.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+		if _, err := leaf.Verify(x509.VerifyOptions{
+			DNSName:       cs.ServerName,
+			Roots:         roots,
+			Intermediates: intermediates,
+		}); err != nil {
+			return fmt.Errorf("tls: verify certificate: %w", err)
+		}
+	}
+
+	if len(s.cfg.Pins) == 0 {
+		return nil
+	}
+	spkiSum := sha256.Sum256(leaf.RawSubjectPublicKeyInfo)
+	certSum := sha256.Sum256(leaf.Raw)
+	got := &PinMismatchError{
+		Host: cs.ServerName,
+		SPKI: config.PinPrefixSPKI + base64.StdEncoding.EncodeToString(spkiSum[:]),
+		Cert: config.PinPrefixCert + hex.EncodeToString(certSum[:]),
+	}
+	for _, pin := range s.cfg.Pins {
+		if pin == got.SPKI {
+			return nil
+		}
+		if strings.HasPrefix(pin, config.PinPrefixCert) &&
+			strings.EqualFold(strings.ReplaceAll(pin, ":", ""), got.Cert) {
+			return nil
+		}
+	}
+	return got
+}
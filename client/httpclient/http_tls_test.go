@@ -0,0 +1,223 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
+)
+
+func writePEM(t *testing.T, path string, blockType string, der []byte) string {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newTestCA returns a CA and a client certificate it signed, written as PEM
+// files.
+func newTestCA(t *testing.T, dir string) (ca *x509.Certificate, certFile string, keyFile string) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gonetic test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, _ = x509.ParseCertificate(caDER)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "gonetic test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTmpl, ca, &clientKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile = writePEM(t, filepath.Join(dir, "client.pem"), "CERTIFICATE", clientDER)
+	keyFile = writePEM(t, filepath.Join(dir, "client-key.pem"), "EC PRIVATE KEY", keyDER)
+	return ca, certFile, keyFile
+}
+
+// newQuietTLSServer is httptest.NewTLSServer without handshake error logs.
+func newQuietTLSServer(handler http.Handler) *httptest.Server {
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	return srv
+}
+
+func Test_HTTPClient_TLS_golden(t *testing.T) {
+	dir := t.TempDir()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	srv := newQuietTLSServer(handler)
+	defer srv.Close()
+	srvCA := writePEM(t, filepath.Join(dir, "server-ca.pem"), "CERTIFICATE", srv.Certificate().Raw)
+	spkiSum := sha256.Sum256(srv.Certificate().RawSubjectPublicKeyInfo)
+	certSum := sha256.Sum256(srv.Certificate().Raw)
+	spkiPin := config.PinPrefixSPKI + base64.StdEncoding.EncodeToString(spkiSum[:])
+	certPin := config.PinPrefixCert + strings.ToUpper(hex.EncodeToString(certSum[:]))
+	otherPin := config.PinPrefixSPKI + base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+	clientCA, certFile, keyFile := newTestCA(t, dir)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCA)
+	mtls := httptest.NewUnstartedServer(handler)
+	mtls.Config.ErrorLog = log.New(io.Discard, "", 0)
+	mtls.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	mtls.StartTLS()
+	defer mtls.Close()
+	mtlsCA := writePEM(t, filepath.Join(dir, "mtls-ca.pem"), "CERTIFICATE", mtls.Certificate().Raw)
+
+	tls12 := httptest.NewUnstartedServer(handler)
+	tls12.Config.ErrorLog = log.New(io.Discard, "", 0)
+	tls12.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	tls12.StartTLS()
+	defer tls12.Close()
+	tls12CA := writePEM(t, filepath.Join(dir, "tls12-ca.pem"), "CERTIFICATE", tls12.Certificate().Raw)
+
+	tests := []struct {
+		name         string
+		url          string
+		tls          config.TLSConfig
+		wantErr      string
+		wantMismatch bool
+	}{
+		{name: "untrusted without ca", url: srv.URL, wantErr: "certificate"},
+		{name: "custom ca", url: srv.URL, tls: config.TLSConfig{CAFiles: []string{srvCA}}},
+		{name: "server name override", url: srv.URL, tls: config.TLSConfig{CAFiles: []string{srvCA}, ServerName: "example.com"}},
+		{name: "server name mismatch", url: srv.URL, tls: config.TLSConfig{CAFiles: []string{srvCA}, ServerName: "other.test"}, wantErr: "certificate"},
+		{name: "spki pin", url: srv.URL, tls: config.TLSConfig{CAFiles: []string{srvCA}, Pins: []string{otherPin, spkiPin}}},
+		{name: "cert pin", url: srv.URL, tls: config.TLSConfig{CAFiles: []string{srvCA}, Pins: []string{certPin}}},
+		{name: "pin mismatch", url: srv.URL, tls: config.TLSConfig{CAFiles: []string{srvCA}, Pins: []string{otherPin}}, wantMismatch: true},
+		{name: "mtls without client cert", url: mtls.URL, tls: config.TLSConfig{CAFiles: []string{mtlsCA}}, wantErr: "perform request"},
+		{name: "mtls", url: mtls.URL, tls: config.TLSConfig{CAFiles: []string{mtlsCA}, CertFile: certFile, KeyFile: keyFile}},
+		{name: "min version above server", url: tls12.URL, tls: config.TLSConfig{CAFiles: []string{tls12CA}, MinVersion: "1.3"}, wantErr: "version"},
+		{name: "min version met", url: tls12.URL, tls: config.TLSConfig{CAFiles: []string{tls12CA}, MinVersion: "1.2"}},
+		{name: "invalid min version", url: srv.URL, tls: config.TLSConfig{MinVersion: "2"}, wantErr: "min_version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultHTTPClientConfig()
+			cfg.WithTLS(tt.tls)
+			c := newTestClient(t, &cfg)
+
+			reqCfg := DefaultHTTPRequestConfig()
+			reqCfg.WithURL(tt.url)
+			resp, err := c.ProcessRequest(context.Background(), &dto.RequestConfig{ReqConfig: &reqCfg})
+
+			var mismatch *PinMismatchError
+			switch {
+			case tt.wantMismatch:
+				if !errors.As(err, &mismatch) || mismatch.SPKI != spkiPin || utils.IsTemporaryErr(err) {
+					t.Fatalf("err=%v want non-temporary PinMismatchError for %s", err, spkiPin)
+				}
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err=%v want %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("ProcessRequest: %v", err)
+			case string(resp.Body) != "ok":
+				t.Fatalf("body=%q", resp.Body)
+			}
+		})
+	}
+}
+
+func Test_HTTPClient_TLSReload(t *testing.T) {
+	dir := t.TempDir()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	trusted := newQuietTLSServer(handler)
+	defer trusted.Close()
+	other, _, _ := newTestCA(t, dir)
+
+	caFile := writePEM(t, filepath.Join(dir, "ca.pem"), "CERTIFICATE", other.Raw)
+	tlsCfg := config.TLSConfig{CAFiles: []string{caFile}, ReloadInterval: time.Millisecond}
+	cfg := DefaultHTTPClientConfig()
+	cfg.WithTLS(tlsCfg)
+	c := newTestClient(t, &cfg)
+
+	send := func() error {
+		reqCfg := DefaultHTTPRequestConfig()
+		reqCfg.WithURL(trusted.URL)
+		_, err := c.ProcessRequest(context.Background(), &dto.RequestConfig{ReqConfig: &reqCfg})
+		return err
+	}
+	if err := send(); err == nil {
+		t.Fatalf("expected failure before the CA file is rotated")
+	}
+
+	writePEM(t, caFile, "CERTIFICATE", trusted.Certificate().Raw)
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(caFile, future, future); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if err := send(); err != nil {
+		t.Fatalf("after rotation: %v", err)
+	}
+
+	if err := os.WriteFile(caFile, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReloadTLS(); err == nil {
+		t.Fatalf("expected ReloadTLS to reject a bad CA file")
+	}
+	c.CloseIdleConnections()
+	if err := send(); err != nil {
+		t.Fatalf("previous roots should stay in use: %v", err)
+	}
+}
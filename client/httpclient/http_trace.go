@@ -0,0 +1,103 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// traceTimes are the httptrace events observed for one request. Zero times
+// were not observed.
+type traceTimes struct {
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	gotConn      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	reused       bool
+	remoteAddr   string
+}
+
+// connTrace collects traceTimes from httptrace callbacks, which may run on
+// other goroutines.
+type connTrace struct {
+	mu    sync.Mutex
+	times traceTimes
+}
+
+func newConnTrace(ctx context.Context) (context.Context, *connTrace) {
+	t := &connTrace{times: traceTimes{start: time.Now()}}
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.mark(&t.times.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.mark(&t.times.dnsDone) },
+		ConnectStart: func(string, string) {
+			// Dialing several addresses reports several starts; keep the first.
+			t.mu.Lock()
+			if t.times.connectStart.IsZero() {
+				t.times.connectStart = time.Now()
+			}
+			t.mu.Unlock()
+		},
+		ConnectDone:          func(string, string, error) { t.mark(&t.times.connectDone) },
+		TLSHandshakeStart:    func() { t.mark(&t.times.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.mark(&t.times.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.mark(&t.times.wroteRequest) },
+		GotFirstResponseByte: func() { t.mark(&t.times.firstByte) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.times.gotConn = time.Now()
+			t.times.reused = info.Reused
+			if info.Conn != nil {
+				t.times.remoteAddr = info.Conn.RemoteAddr().String()
+			}
+		},
+	}), t
+}
+
+func (t *connTrace) mark(field *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*field = time.Now()
+}
+
+func (t *connTrace) snapshot() traceTimes {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.times
+}
+
+// between is end-start, or -1 when either was not observed.
+func between(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return -1
+	}
+	return end.Sub(start)
+}
+
+// responseTiming converts t to the dto form; end is when the body was read.
+func (t traceTimes) responseTiming(resp *http.Response, end time.Time) *dto.ResponseTiming {
+	timing := &dto.ResponseTiming{
+		DNSLookup:       max(between(t.dnsStart, t.dnsDone), 0),
+		TCPConnect:      max(between(t.connectStart, t.connectDone), 0),
+		TLSHandshake:    max(between(t.tlsStart, t.tlsDone), 0),
+		TimeToFirstByte: max(between(t.start, t.firstByte), 0),
+		Total:           end.Sub(t.start),
+		ConnReused:      t.reused,
+		RemoteAddr:      t.remoteAddr,
+	}
+	if resp != nil {
+		timing.Protocol = resp.Proto
+	}
+	return timing
+}
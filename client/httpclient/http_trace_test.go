@@ -0,0 +1,87 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joy-dx/gonetic/dto"
+)
+
+func Test_HTTPClient_Timing_golden(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	plain := httptest.NewServer(handler)
+	defer plain.Close()
+	secure := httptest.NewTLSServer(handler)
+	defer secure.Close()
+
+	tests := []struct {
+		name       string
+		srv        *httptest.Server
+		timing     bool
+		wantTLS    bool
+		wantReused bool
+	}{
+		{name: "disabled", srv: plain},
+		{name: "fresh connection", srv: plain, timing: true},
+		{name: "reused connection", srv: plain, timing: true, wantReused: true},
+		{name: "tls handshake", srv: secure, timing: true, wantTLS: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultHTTPClientConfig()
+			cfg.WithTiming(tt.timing)
+			c := newTestClient(t, &cfg)
+			if tt.srv.TLS != nil {
+				c.client.Transport = tt.srv.Client().Transport
+			}
+
+			send := func() dto.Response {
+				reqCfg := DefaultHTTPRequestConfig()
+				reqCfg.WithURL(tt.srv.URL)
+				resp, err := c.ProcessRequest(context.Background(), &dto.RequestConfig{ReqConfig: &reqCfg})
+				if err != nil {
+					t.Fatalf("ProcessRequest: %v", err)
+				}
+				return resp
+			}
+			resp := send()
+			if tt.wantReused {
+				resp = send()
+			}
+
+			if !tt.timing {
+				if resp.Timing != nil {
+					t.Fatalf("timing=%+v want nil", resp.Timing)
+				}
+				return
+			}
+			timing := resp.Timing
+			if timing == nil {
+				t.Fatalf("timing not collected")
+			}
+			if timing.ConnReused != tt.wantReused {
+				t.Fatalf("reused=%v want %v", timing.ConnReused, tt.wantReused)
+			}
+			if tt.wantReused && timing.TCPConnect != 0 {
+				t.Fatalf("connect=%v on reused connection", timing.TCPConnect)
+			}
+			if !tt.wantReused && timing.TCPConnect <= 0 {
+				t.Fatalf("connect=%v want > 0", timing.TCPConnect)
+			}
+			if (timing.TLSHandshake > 0) != tt.wantTLS {
+				t.Fatalf("tls=%v wantTLS=%v", timing.TLSHandshake, tt.wantTLS)
+			}
+			if timing.TimeToFirstByte <= 0 || timing.Total < timing.TimeToFirstByte {
+				t.Fatalf("ttfb=%v total=%v", timing.TimeToFirstByte, timing.Total)
+			}
+			if timing.RemoteAddr != tt.srv.Listener.Addr().String() || timing.Protocol != "HTTP/1.1" {
+				t.Fatalf("remote=%q protocol=%q", timing.RemoteAddr, timing.Protocol)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/joy-dx/gonetic/config"
+)
+
+// UnixScheme selects a Unix domain socket per request. The URL path holds the
+// socket path and the request path separated by a colon:
+//
+//	http+unix:///var/run/docker.sock:/v1.43/info
+const UnixScheme = "http+unix"
+
+// SplitUnixURL returns the socket path of an http+unix URL and the
+// equivalent http://localhost URL, as curl --unix-socket expects. ok is false
+// for other schemes.
+func SplitUnixURL(raw string) (socket string, httpURL string, ok bool) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != UnixScheme {
+		return "", "", false
+	}
+	socket, target, err := splitUnixURL(u)
+	if err != nil {
+		return "", "", false
+	}
+	target.Host = "localhost"
+	return socket, target.String(), true
+}
+
+// splitUnixURL splits u into the socket path and an http URL without host.
+func splitUnixURL(u *url.URL) (string, *url.URL, error) {
+	if u.Host != "" {
+		return "", nil, fmt.Errorf("%s url %q: socket path must be absolute, e.g. %s:///run/app.sock:/path", UnixScheme, u.Redacted(), UnixScheme)
+	}
+	socket, path, _ := strings.Cut(u.Path, ":")
+	if socket == "" {
+		return "", nil, fmt.Errorf("%s url %q: no socket path", UnixScheme, u.Redacted())
+	}
+	if path == "" {
+		path = "/"
+	}
+	return socket, &url.URL{Scheme: "http", Path: path, RawQuery: u.RawQuery}, nil
+}
+
+// unixTransport serves http+unix URLs. The inner transport's pool is keyed
+// by the hex encoded socket path, so each socket keeps its own connections.
+type unixTransport struct {
+	inner *http.Transport
+}
+
+func newUnixTransport(cfg config.TransportConfig, dial dialFunc) *unixTransport {
+	inner := newTransport(cfg)
+	inner.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		socket, err := hex.DecodeString(host)
+		if err != nil {
+			return nil, errors.New("invalid unix socket address")
+		}
+		return dial(ctx, "unix", string(socket))
+	}
+	return &unixTransport{inner: inner}
+}
+
+func (t *unixTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	socket, target, err := splitUnixURL(req.URL)
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	target.Host = hex.EncodeToString([]byte(socket))
+	out := req.Clone(req.Context())
+	out.URL = target
+	if out.Host == "" {
+		out.Host = "localhost"
+	}
+	return t.inner.RoundTrip(out)
+}
+
+func (t *unixTransport) CloseIdleConnections() {
+	t.inner.CloseIdleConnections()
+}
+
+// dialSocket ignores the address and always dials socket.
+func dialSocket(dialer *net.Dialer, socket string) dialFunc {
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", socket)
+	}
+}
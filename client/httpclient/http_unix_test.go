@@ -0,0 +1,132 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joy-dx/gonetic/dto"
+	"golang.org/x/oauth2"
+)
+
+// newUnixServer serves on a socket in a temp dir, replying with name, the
+// request path and query, and the Authorization header.
+func newUnixServer(t *testing.T, name string) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), name+".sock")
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(name + " " + r.Host + " " + r.URL.RequestURI() + " " + r.Header.Get("Authorization")))
+	})}
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() { srv.Close() })
+	return socket
+}
+
+func Test_HTTPClient_UnixSocket_golden(t *testing.T) {
+	docker := newUnixServer(t, "docker")
+	sidecar := newUnixServer(t, "sidecar")
+
+	tests := []struct {
+		name     string
+		socket   string
+		baseURL  string
+		url      string
+		wantBody string
+		wantErr  string
+	}{
+		{
+			name:     "socket path option",
+			socket:   docker,
+			url:      "http://localhost/v1.43/info?all=1",
+			wantBody: "docker localhost /v1.43/info?all=1 Bearer tok",
+		},
+		{
+			name:     "socket path with base url",
+			socket:   docker,
+			baseURL:  "http://docker/v1.43/",
+			url:      "containers/json",
+			wantBody: "docker docker /v1.43/containers/json Bearer tok",
+		},
+		{
+			name:     "unix url",
+			url:      "http+unix://" + docker + ":/v1.43/info",
+			wantBody: "docker localhost /v1.43/info Bearer tok",
+		},
+		{
+			name:     "unix url picks its own socket",
+			url:      "http+unix://" + sidecar + ":/healthz?verbose",
+			wantBody: "sidecar localhost /healthz?verbose Bearer tok",
+		},
+		{
+			name:     "unix url without request path",
+			url:      "http+unix://" + sidecar,
+			wantBody: "sidecar localhost / Bearer tok",
+		},
+		{
+			name:    "unix url with host",
+			url:     "http+unix://docker.sock/info",
+			wantErr: "socket path must be absolute",
+		},
+		{
+			name:    "missing socket",
+			url:     "http+unix://" + filepath.Join(t.TempDir(), "gone.sock") + ":/info",
+			wantErr: "connect",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultHTTPClientConfig()
+			cfg.WithSocketPath(tt.socket).
+				WithBaseURL(tt.baseURL).
+				WithOAuthSource(&staticTokenSource{tok: &oauth2.Token{AccessToken: "tok", TokenType: "Bearer"}})
+			c := newTestClient(t, &cfg)
+			t.Cleanup(c.CloseIdleConnections)
+
+			reqCfg := DefaultHTTPRequestConfig()
+			reqCfg.WithURL(tt.url)
+			resp, err := c.ProcessRequest(context.Background(), &dto.RequestConfig{ReqConfig: &reqCfg})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err=%v want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || string(resp.Body) != tt.wantBody {
+				t.Fatalf("body=%q err=%v want %q", resp.Body, err, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestSplitUnixURL_Golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		raw        string
+		wantSocket string
+		wantURL    string
+		wantOK     bool
+	}{
+		{raw: "http+unix:///var/run/docker.sock:/v1.43/info?all=1", wantSocket: "/var/run/docker.sock", wantURL: "http://localhost/v1.43/info?all=1", wantOK: true},
+		{raw: "http+unix:///run/app.sock", wantSocket: "/run/app.sock", wantURL: "http://localhost/", wantOK: true},
+		{raw: "http+unix://app.sock/info"},
+		{raw: "http://localhost/info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			socket, httpURL, ok := SplitUnixURL(tt.raw)
+			if socket != tt.wantSocket || httpURL != tt.wantURL || ok != tt.wantOK {
+				t.Fatalf("got (%q, %q, %v) want (%q, %q, %v)", socket, httpURL, ok, tt.wantSocket, tt.wantURL, tt.wantOK)
+			}
+		})
+	}
+}
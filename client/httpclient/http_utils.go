@@ -4,10 +4,37 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/joy-dx/gonetic/dto"
 )
 
+// applyClientDefaults resolves a relative URL against BaseURL and adds the
+// client's default headers the request does not set itself.
+func (c *HTTPClient) applyClientDefaults(req *HTTPRequest) error {
+	for k, v := range c.cfg.Headers {
+		if req.Header(k) == "" {
+			req.SetHeader(k, v)
+		}
+	}
+	if c.cfg.BaseURL == "" {
+		return nil
+	}
+	target, err := url.Parse(req.URL)
+	if err != nil {
+		return fmt.Errorf("parse url: %w", err)
+	}
+	if target.IsAbs() {
+		return nil
+	}
+	base, err := url.Parse(c.cfg.BaseURL)
+	if err != nil {
+		return fmt.Errorf("parse base url: %w", err)
+	}
+	req.URL = base.ResolveReference(target).String()
+	return nil
+}
+
 // ensureToken verifies if an active token is valid, auto-refreshing if necessary.
 func (c *HTTPClient) ensureToken(ctx context.Context) error {
 	c.tokenMu.RLock()
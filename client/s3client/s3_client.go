@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/joy-dx/gonetic/dto"
@@ -17,13 +18,34 @@ type s3API interface {
 	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
 	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
 	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+	CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
+	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+	ListObjectVersions(ctx context.Context, params *s3.ListObjectVersionsInput, optFns ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error)
+	GetObjectTagging(ctx context.Context, params *s3.GetObjectTaggingInput, optFns ...func(*s3.Options)) (*s3.GetObjectTaggingOutput, error)
+	PutObjectTagging(ctx context.Context, params *s3.PutObjectTaggingInput, optFns ...func(*s3.Options)) (*s3.PutObjectTaggingOutput, error)
+}
+
+// s3Presigner abstracts s3.PresignClient for testing.
+type s3Presigner interface {
+	PresignGetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
+	PresignPutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
 }
 
 type S3Client struct {
 	NetClient dto.NetClient
 	cfg       *S3ClientConfig
 	client    s3API
+	presigner s3Presigner
 	mu        sync.RWMutex
+	publish   func(dto.TransferNotification)
+	// minPartSize overrides MinMultipartPartSize when set; tests lower it to
+	// upload small payloads to fakes
+	minPartSize int64
 }
 
 func NewS3Client(ref string, cfg *S3ClientConfig) (*S3Client, error) {
@@ -43,13 +65,14 @@ func NewS3Client(ref string, cfg *S3ClientConfig) (*S3Client, error) {
 	})
 
 	return &S3Client{
-		cfg:    cfg,
-		client: client,
+		cfg:       cfg,
+		client:    client,
+		presigner: s3.NewPresignClient(client),
 		NetClient: dto.NetClient{
 			Name:        "S3 Client",
 			Ref:         ref,
 			ClientType:  NetClientS3Ref,
-			Description: "Performs basic S3 operations (get, put, list, delete)",
+			Description: "Performs S3 object operations (get, put, head, copy, list, delete, versions, tagging, multipart upload, presign)",
 		},
 	}, nil
 }
@@ -58,6 +81,34 @@ func (c *S3Client) Ref() string {
 	return c.NetClient.Ref
 }
 
+// Descriptor implements dto.NetClientDescriptor.
+func (c *S3Client) Descriptor() dto.NetClient {
+	return c.NetClient
+}
+
 func (c *S3Client) Type() dto.NetClientType {
 	return NetClientS3Ref
 }
+
+// SetTransferPublisher implements dto.TransferPublisher so multipart uploads
+// report progress through NetSvc transfer listeners.
+func (c *S3Client) SetTransferPublisher(publish func(dto.TransferNotification)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.publish = publish
+}
+
+// publishTransfer forwards a notification to the registered publisher, if any.
+func (c *S3Client) publishTransfer(state dto.TransferNotification) {
+	c.mu.RLock()
+	publish := c.publish
+	c.mu.RUnlock()
+	if publish != nil {
+		publish(state)
+	}
+}
+
+// s3URI formats a bucket and key as an s3:// URI, used to identify transfers.
+func s3URI(bucket, key string) string {
+	return "s3://" + bucket + "/" + key
+}
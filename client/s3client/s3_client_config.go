@@ -2,6 +2,7 @@ package s3client
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/joy-dx/gonetic/dto"
@@ -9,6 +10,26 @@ import (
 
 const NetClientS3Ref dto.NetClientType = "net.client.s3"
 
+const (
+	// DefaultMultipartPartSize is used when S3RequestConfig.PartSize is unset.
+	// S3 requires every part except the last to be at least 5 MiB.
+	DefaultMultipartPartSize int64 = 8 * 1024 * 1024
+	// MinMultipartPartSize is the S3 minimum for every part except the last.
+	MinMultipartPartSize int64 = 5 * 1024 * 1024
+	// MaxMultipartParts is the S3 limit on parts per multipart upload.
+	MaxMultipartParts = 10000
+	// DefaultMultipartConcurrency is the number of parts uploaded in parallel.
+	DefaultMultipartConcurrency = 4
+	// DefaultMultipartPartRetries is the number of retries for a single failed part.
+	DefaultMultipartPartRetries = 3
+	// DefaultPresignExpiry is the lifetime of presigned URLs when unset.
+	DefaultPresignExpiry = 15 * time.Minute
+	// MaxPresignExpiry is the longest lifetime SigV4 presigned URLs allow.
+	MaxPresignExpiry = 7 * 24 * time.Hour
+	// MaxDeleteManyBatch is the S3 limit on keys per DeleteObjects call.
+	MaxDeleteManyBatch = 1000
+)
+
 type Middleware func(ctx context.Context, req *S3Request) error
 
 // S3ClientConfig defines the static properties for an S3 client instance.
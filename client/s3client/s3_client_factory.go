@@ -0,0 +1,70 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+func init() {
+	config.RegisterClientType(config.ClientFactory{
+		Type:     NetClientS3Ref,
+		Name:     config.ClientTypeS3,
+		New:      newFromConfig,
+		Validate: validateConfig,
+		NewReqConfig: func() dto.ReqConfigInterface {
+			return &S3RequestConfig{}
+		},
+	})
+}
+
+func validateConfig(client *config.ClientConfig) error {
+	var errs []error
+	if client.Region == "" {
+		errs = append(errs, errors.New("region required"))
+	}
+	errs = append(errs, client.Credentials.Validate())
+	return errors.Join(errs...)
+}
+
+// newFromConfig builds an S3Client from a declarative client config.
+func newFromConfig(netCfg *config.NetSvcConfig, declared *config.ClientConfig) (dto.NetClientInterface, error) {
+	s3Cfg := DefaultS3ClientConfig(declared.Region)
+	s3Cfg.Endpoint = declared.Endpoint
+	s3Cfg.ForcePathStyle = declared.ForcePathStyle
+
+	if declared.Credentials.Source == config.CredentialsSourceStatic {
+		creds, err := resolveStaticCredentials(declared.Credentials)
+		if err != nil {
+			return nil, err
+		}
+		s3Cfg.Credentials = aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return creds, nil
+		})
+	}
+	return NewS3Client(declared.Ref, &s3Cfg)
+}
+
+func resolveStaticCredentials(c config.CredentialsConfig) (aws.Credentials, error) {
+	keyID, err := c.AccessKeyID.Resolve()
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	secret, err := c.SecretAccessKey.Resolve()
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	session, err := c.SessionToken.Resolve()
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	return aws.Credentials{
+		AccessKeyID:     keyID,
+		SecretAccessKey: secret,
+		SessionToken:    session,
+		Source:          "gonetic config",
+	}, nil
+}
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -32,6 +34,186 @@ type fakeS3 struct {
 	delErr  error
 	listOut *s3.ListObjectsV2Output
 	listErr error
+
+	// Multipart calls may arrive concurrently
+	mu          sync.Mutex
+	gotCreate   []*s3.CreateMultipartUploadInput
+	gotComplete []*s3.CompleteMultipartUploadInput
+	gotAbort    []*s3.AbortMultipartUploadInput
+	partBodies  map[int32][]byte
+	gotParts    []*s3.UploadPartInput
+	partCalls   map[int32]int
+	createErr   error
+	completeErr error
+	// getFn overrides getOut/getErr when set; used for ranged reads
+	getFn         func(params *s3.GetObjectInput) (*s3.GetObjectOutput, error)
+	gotHead       []*s3.HeadObjectInput
+	gotCopy       []*s3.CopyObjectInput
+	gotDeleteMany []*s3.DeleteObjectsInput
+	headOut       *s3.HeadObjectOutput
+	headErr       error
+	copyOut       *s3.CopyObjectOutput
+	// deleteManyFn answers DeleteObjects; every key is deleted when nil
+	deleteManyFn func(params *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error)
+	gotTagging   []*s3.PutObjectTaggingInput
+	versionPages []*s3.ListObjectVersionsOutput
+	gotVersions  []*s3.ListObjectVersionsInput
+	taggingOut   *s3.GetObjectTaggingOutput
+	// partErr is consulted for every UploadPart call with the 1-based attempt count
+	partErr func(partNumber int32, attempt int) error
+}
+
+func (f *fakeS3) HeadObject(
+	ctx context.Context,
+	params *s3.HeadObjectInput,
+	optFns ...func(*s3.Options),
+) (*s3.HeadObjectOutput, error) {
+	f.gotHead = append(f.gotHead, params)
+	if f.headErr != nil {
+		return nil, f.headErr
+	}
+	if f.headOut != nil {
+		return f.headOut, nil
+	}
+	return &s3.HeadObjectOutput{}, nil
+}
+
+func (f *fakeS3) CopyObject(
+	ctx context.Context,
+	params *s3.CopyObjectInput,
+	optFns ...func(*s3.Options),
+) (*s3.CopyObjectOutput, error) {
+	f.gotCopy = append(f.gotCopy, params)
+	if f.copyOut != nil {
+		return f.copyOut, nil
+	}
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func (f *fakeS3) DeleteObjects(
+	ctx context.Context,
+	params *s3.DeleteObjectsInput,
+	optFns ...func(*s3.Options),
+) (*s3.DeleteObjectsOutput, error) {
+	f.gotDeleteMany = append(f.gotDeleteMany, params)
+	if f.deleteManyFn != nil {
+		return f.deleteManyFn(params)
+	}
+	out := &s3.DeleteObjectsOutput{}
+	for _, obj := range params.Delete.Objects {
+		out.Deleted = append(out.Deleted, s3types.DeletedObject{Key: obj.Key})
+	}
+	return out, nil
+}
+
+func (f *fakeS3) ListObjectVersions(
+	ctx context.Context,
+	params *s3.ListObjectVersionsInput,
+	optFns ...func(*s3.Options),
+) (*s3.ListObjectVersionsOutput, error) {
+	f.gotVersions = append(f.gotVersions, params)
+	page := len(f.gotVersions) - 1
+	if page >= len(f.versionPages) {
+		return &s3.ListObjectVersionsOutput{}, nil
+	}
+	return f.versionPages[page], nil
+}
+
+func (f *fakeS3) GetObjectTagging(
+	ctx context.Context,
+	params *s3.GetObjectTaggingInput,
+	optFns ...func(*s3.Options),
+) (*s3.GetObjectTaggingOutput, error) {
+	if f.taggingOut != nil {
+		return f.taggingOut, nil
+	}
+	return &s3.GetObjectTaggingOutput{}, nil
+}
+
+func (f *fakeS3) PutObjectTagging(
+	ctx context.Context,
+	params *s3.PutObjectTaggingInput,
+	optFns ...func(*s3.Options),
+) (*s3.PutObjectTaggingOutput, error) {
+	f.gotTagging = append(f.gotTagging, params)
+	return &s3.PutObjectTaggingOutput{VersionId: params.VersionId}, nil
+}
+
+func (f *fakeS3) CreateMultipartUpload(
+	ctx context.Context,
+	params *s3.CreateMultipartUploadInput,
+	optFns ...func(*s3.Options),
+) (*s3.CreateMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.gotCreate = append(f.gotCreate, params)
+	if f.createErr != nil {
+		return nil, f.createErr
+	}
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload-1")}, nil
+}
+
+func (f *fakeS3) UploadPart(
+	ctx context.Context,
+	params *s3.UploadPartInput,
+	optFns ...func(*s3.Options),
+) (*s3.UploadPartOutput, error) {
+	num := aws.ToInt32(params.PartNumber)
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	if f.partCalls == nil {
+		f.partCalls = map[int32]int{}
+		f.partBodies = map[int32][]byte{}
+	}
+	f.partCalls[num]++
+	attempt := f.partCalls[num]
+	partErr := f.partErr
+	f.mu.Unlock()
+
+	if partErr != nil {
+		if err := partErr(num, attempt); err != nil {
+			return nil, err
+		}
+	}
+
+	f.mu.Lock()
+	f.partBodies[num] = body
+	f.gotParts = append(f.gotParts, params)
+	f.mu.Unlock()
+	out := &s3.UploadPartOutput{ETag: aws.String(fmt.Sprintf("etag-%d", num))}
+	if params.ChecksumAlgorithm == s3types.ChecksumAlgorithmCrc32c {
+		out.ChecksumCRC32C = aws.String(fmt.Sprintf("crc-%d", num))
+	}
+	return out, nil
+}
+
+func (f *fakeS3) CompleteMultipartUpload(
+	ctx context.Context,
+	params *s3.CompleteMultipartUploadInput,
+	optFns ...func(*s3.Options),
+) (*s3.CompleteMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.gotComplete = append(f.gotComplete, params)
+	if f.completeErr != nil {
+		return nil, f.completeErr
+	}
+	return &s3.CompleteMultipartUploadOutput{ETag: aws.String("etag-final")}, nil
+}
+
+func (f *fakeS3) AbortMultipartUpload(
+	ctx context.Context,
+	params *s3.AbortMultipartUploadInput,
+	optFns ...func(*s3.Options),
+) (*s3.AbortMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.gotAbort = append(f.gotAbort, params)
+	return &s3.AbortMultipartUploadOutput{}, nil
 }
 
 func (f *fakeS3) GetObject(
@@ -39,7 +221,13 @@ func (f *fakeS3) GetObject(
 	params *s3.GetObjectInput,
 	optFns ...func(*s3.Options),
 ) (*s3.GetObjectOutput, error) {
+	f.mu.Lock()
 	f.gotGet = append(f.gotGet, params)
+	getFn := f.getFn
+	f.mu.Unlock()
+	if getFn != nil {
+		return getFn(params)
+	}
 	if f.getErr != nil {
 		return nil, f.getErr
 	}
@@ -54,6 +242,8 @@ func (f *fakeS3) PutObject(
 	params *s3.PutObjectInput,
 	optFns ...func(*s3.Options),
 ) (*s3.PutObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.gotPut = append(f.gotPut, params)
 	if f.putErr != nil {
 		return nil, f.putErr
@@ -103,6 +293,8 @@ func newTestClient(t *testing.T, mw ...Middleware) (*S3Client, *fakeS3) {
 			Middlewares: mw,
 		},
 		client: f,
+		// The fakes accept parts of any size; keep payloads small.
+		minPartSize: 10,
 		NetClient: dto.NetClient{
 			Name:        "S3 Client",
 			Ref:         "test",
@@ -132,7 +324,7 @@ func TestS3Request_Finalize_Golden(t *testing.T) {
 		{
 			name: "get builds GetObjectInput",
 			req: &S3Request{
-				Operation: "get",
+				Operation: S3OpGet,
 				Bucket:    "b",
 				Key:       "k",
 			},
@@ -144,7 +336,7 @@ func TestS3Request_Finalize_Golden(t *testing.T) {
 		{
 			name: "put builds PutObjectInput with content-type and metadata from map[string]string",
 			req: &S3Request{
-				Operation:   "put",
+				Operation:   S3OpPut,
 				Bucket:      "b",
 				Key:         "k",
 				Body:        []byte("payload"),
@@ -172,7 +364,7 @@ func TestS3Request_Finalize_Golden(t *testing.T) {
 		{
 			name: "put builds PutObjectInput metadata from map[string]any (string-only values)",
 			req: &S3Request{
-				Operation: "put",
+				Operation: S3OpPut,
 				Bucket:    "b",
 				Key:       "k",
 				Body:      []byte("x"),
@@ -192,10 +384,43 @@ func TestS3Request_Finalize_Golden(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "get maps conditional, version and SSE-C fields",
+			req: &S3Request{
+				Operation:      S3OpGet,
+				Bucket:         "b",
+				Key:            "k",
+				Range:          "bytes=0-9",
+				IfMatch:        `"e1"`,
+				IfNoneMatch:    `"e2"`,
+				VersionId:      "v1",
+				SSECustomerKey: []byte("0123456789abcdef0123456789abcdef"),
+			},
+			wantGet: &s3.GetObjectInput{
+				Bucket:               aws.String("b"),
+				Key:                  aws.String("k"),
+				Range:                aws.String("bytes=0-9"),
+				IfMatch:              aws.String(`"e1"`),
+				IfNoneMatch:          aws.String(`"e2"`),
+				VersionId:            aws.String("v1"),
+				SSECustomerAlgorithm: aws.String("AES256"),
+				SSECustomerKey:       aws.String("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="),
+				SSECustomerKeyMD5:    aws.String("hRasmdxgYDKV3nvbahU1MA=="),
+			},
+		},
+		{
+			name: "get rejects Stream with DestinationPath",
+			req: &S3Request{
+				Operation:       S3OpGet,
+				Stream:          true,
+				DestinationPath: "/tmp/x",
+			},
+			wantErr: "s3 get: Stream and DestinationPath are mutually exclusive",
+		},
 		{
 			name: "delete builds DeleteObjectInput",
 			req: &S3Request{
-				Operation: "delete",
+				Operation: S3OpDelete,
 				Bucket:    "b",
 				Key:       "k",
 			},
@@ -207,7 +432,7 @@ func TestS3Request_Finalize_Golden(t *testing.T) {
 		{
 			name: "list builds ListObjectsV2Input with prefix",
 			req: &S3Request{
-				Operation: "list",
+				Operation: S3OpList,
 				Bucket:    "b",
 				Prefix:    "p/",
 			},
@@ -216,17 +441,38 @@ func TestS3Request_Finalize_Golden(t *testing.T) {
 				Prefix: aws.String("p/"),
 			},
 		},
+		{
+			name: "list caps MaxKeys at one page",
+			req: &S3Request{
+				Operation: S3OpList,
+				Bucket:    "b",
+				MaxKeys:   5000,
+			},
+			wantList: &s3.ListObjectsV2Input{
+				Bucket:  aws.String("b"),
+				MaxKeys: aws.Int32(1000),
+			},
+		},
+		{
+			name: "list rejects negative MaxKeys",
+			req: &S3Request{
+				Operation: S3OpList,
+				Bucket:    "b",
+				MaxKeys:   -1,
+			},
+			wantErr: "list MaxKeys must not be negative",
+		},
 		{
 			name: "unsupported operation returns error",
 			req: &S3Request{
-				Operation: "nope",
+				Operation: S3Operation(99),
 			},
-			wantErr: "unsupported s3 operation: nope",
+			wantErr: "unsupported s3 operation: S3Operation(99)",
 		},
 		{
 			name: "Finalize clears previously prepared inputs before rebuilding",
 			req: &S3Request{
-				Operation: "get",
+				Operation: S3OpGet,
 				Bucket:    "b",
 				Key:       "k",
 				PutInput:  &s3.PutObjectInput{Bucket: aws.String("old")},
@@ -326,7 +572,7 @@ func TestS3Client_ProcessRequest_Golden(t *testing.T) {
 		{
 			name: "middleware aborts before Finalize/SDK call",
 			reqCfg: mustReq(t, &S3RequestConfig{
-				Operation: "get",
+				Operation: S3OpGet,
 				Bucket:    "b",
 				Key:       "k",
 			}),
@@ -346,16 +592,16 @@ func TestS3Client_ProcessRequest_Golden(t *testing.T) {
 		{
 			name: "unsupported operation after Finalize returns error",
 			reqCfg: mustReq(t, &S3RequestConfig{
-				Operation: "nope",
+				Operation: S3Operation(99),
 				Bucket:    "b",
 				Key:       "k",
 			}),
-			wantErrSubstr: "unsupported s3 operation: nope",
+			wantErrSubstr: "unsupported s3 operation: S3Operation(99)",
 		},
 		{
 			name: "get routes to GetObject and returns body and metadata headers",
 			reqCfg: mustReq(t, &S3RequestConfig{
-				Operation: "get",
+				Operation: S3OpGet,
 				Bucket:    "b",
 				Key:       "k",
 			}),
@@ -394,7 +640,7 @@ func TestS3Client_ProcessRequest_Golden(t *testing.T) {
 		{
 			name: "get sdk error is wrapped",
 			reqCfg: mustReq(t, &S3RequestConfig{
-				Operation: "get",
+				Operation: S3OpGet,
 				Bucket:    "b",
 				Key:       "k",
 			}),
@@ -407,7 +653,7 @@ func TestS3Client_ProcessRequest_Golden(t *testing.T) {
 		{
 			name: "put routes to PutObject and returns 200",
 			reqCfg: mustReq(t, &S3RequestConfig{
-				Operation:   "put",
+				Operation:   S3OpPut,
 				Bucket:      "b",
 				Key:         "k",
 				Body:        []byte("data"),
@@ -441,7 +687,7 @@ func TestS3Client_ProcessRequest_Golden(t *testing.T) {
 		{
 			name: "delete routes to DeleteObject and returns 200",
 			reqCfg: mustReq(t, &S3RequestConfig{
-				Operation: "delete",
+				Operation: S3OpDelete,
 				Bucket:    "b",
 				Key:       "k",
 			}),
@@ -457,7 +703,7 @@ func TestS3Client_ProcessRequest_Golden(t *testing.T) {
 		{
 			name: "list routes to ListObjectsV2 and returns newline separated keys",
 			reqCfg: mustReq(t, &S3RequestConfig{
-				Operation: "list",
+				Operation: S3OpList,
 				Bucket:    "b",
 				Prefix:    "p/",
 			}),
@@ -485,7 +731,7 @@ func TestS3Client_ProcessRequest_Golden(t *testing.T) {
 		{
 			name: "list sdk error is wrapped",
 			reqCfg: mustReq(t, &S3RequestConfig{
-				Operation: "list",
+				Operation: S3OpList,
 				Bucket:    "b",
 			}),
 			fake: func(f *fakeS3) {
@@ -497,7 +743,7 @@ func TestS3Client_ProcessRequest_Golden(t *testing.T) {
 		{
 			name: "delete sdk error is wrapped",
 			reqCfg: mustReq(t, &S3RequestConfig{
-				Operation: "delete",
+				Operation: S3OpDelete,
 				Bucket:    "b",
 				Key:       "k",
 			}),
@@ -510,7 +756,7 @@ func TestS3Client_ProcessRequest_Golden(t *testing.T) {
 		{
 			name: "put sdk error is wrapped",
 			reqCfg: mustReq(t, &S3RequestConfig{
-				Operation: "put",
+				Operation: S3OpPut,
 				Bucket:    "b",
 				Key:       "k",
 				Body:      []byte("x"),
@@ -524,7 +770,7 @@ func TestS3Client_ProcessRequest_Golden(t *testing.T) {
 		{
 			name: "middleware can enrich metadata for put (integration with Finalize)",
 			reqCfg: mustReq(t, &S3RequestConfig{
-				Operation: "put",
+				Operation: S3OpPut,
 				Bucket:    "b",
 				Key:       "k",
 				Body:      []byte("x"),
@@ -546,7 +792,7 @@ func TestS3Client_ProcessRequest_Golden(t *testing.T) {
 		{
 			name: "logging middleware is executed (smoke)",
 			reqCfg: mustReq(t, &S3RequestConfig{
-				Operation: "get",
+				Operation: S3OpGet,
 				Bucket:    "b",
 				Key:       "k",
 			}),
@@ -661,7 +907,7 @@ func TestDoGet_ClosesBody_Golden(t *testing.T) {
 	}
 
 	req := &S3Request{
-		Operation: "get",
+		Operation: S3OpGet,
 		Bucket:    "b",
 		Key:       "k",
 		GetInput: &s3.GetObjectInput{
@@ -705,7 +951,7 @@ func TestDoGet_ReturnsHTTPHeaderType_Golden(t *testing.T) {
 	}
 
 	req := &S3Request{
-		Operation: "get",
+		Operation: S3OpGet,
 		Bucket:    "b",
 		Key:       "k",
 		GetInput: &s3.GetObjectInput{
@@ -730,7 +976,7 @@ func TestProcessRequest_CallsFinalize_ByObservingPreparedInputs_Golden(t *testin
 	c, f := newTestClient(t)
 
 	reqCfg := mustReq(t, &S3RequestConfig{
-		Operation: "put",
+		Operation: S3OpPut,
 		Bucket:    "b",
 		Key:       "k",
 		Body:      []byte("x"),
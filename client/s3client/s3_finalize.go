@@ -2,10 +2,18 @@ package s3client
 
 import (
 	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 // Finalize builds the deterministic AWS SDK input struct for the operation.
@@ -16,23 +24,46 @@ func (r *S3Request) Finalize() error {
 	r.GetInput = nil
 	r.DeleteInput = nil
 	r.ListInput = nil
+	r.CreateMultipartInput = nil
+	r.HeadInput = nil
+	r.CopyInput = nil
+	r.DeleteManyInputs = nil
+	r.ListVersionsInput = nil
+	r.GetTaggingInput = nil
+	r.PutTaggingInput = nil
+
+	if err := r.checkExtraOpts(); err != nil {
+		return err
+	}
+	if (r.Operation == S3OpPresignGet || r.Operation == S3OpPresignPut) && r.Expires > MaxPresignExpiry {
+		return fmt.Errorf("s3 %s: Expires %s exceeds the SigV4 maximum of %s", r.Operation, r.Expires, MaxPresignExpiry)
+	}
 
 	switch r.Operation {
-	case "get":
-		r.GetInput = &s3.GetObjectInput{
-			Bucket: aws.String(r.Bucket),
-			Key:    aws.String(r.Key),
+	case S3OpGet, S3OpPresignGet:
+		if r.Stream && r.DestinationPath != "" {
+			return fmt.Errorf("s3 get: Stream and DestinationPath are mutually exclusive")
+		}
+		in := &s3.GetObjectInput{
+			Bucket:      aws.String(r.Bucket),
+			Key:         aws.String(r.Key),
+			Range:       optionalString(r.Range),
+			IfMatch:     optionalString(r.IfMatch),
+			IfNoneMatch: optionalString(r.IfNoneMatch),
+			VersionId:   optionalString(r.VersionId),
 		}
-		// Note: headers/customizations for GetObject are limited. If you want
-		// Range, IfMatch, SSE-C, etc, add explicit fields to S3Request rather
-		// than trying to pipe via Headers/ExtraOpts.
+		in.SSECustomerAlgorithm, in.SSECustomerKey, in.SSECustomerKeyMD5 = r.sseCustomerFields()
+		r.GetInput = in
 		return nil
 
-	case "put":
+	case S3OpPut, S3OpPresignPut:
 		in := &s3.PutObjectInput{
 			Bucket: aws.String(r.Bucket),
 			Key:    aws.String(r.Key),
-			Body:   bytes.NewReader(r.Body),
+		}
+		// A presigned PUT carries no body; the URL holder uploads it.
+		if r.Operation == S3OpPut {
+			in.Body = bytes.NewReader(r.Body)
 		}
 		if r.ContentType != "" {
 			in.ContentType = aws.String(r.ContentType)
@@ -50,23 +81,184 @@ func (r *S3Request) Finalize() error {
 			in.CacheControl = aws.String(v)
 		}
 
+		in.Tagging = r.taggingQuery()
+		lock, err := r.objectLockFields()
+		if err != nil {
+			return err
+		}
+		in.ObjectLockMode, in.ObjectLockRetainUntilDate, in.ObjectLockLegalHoldStatus = lock.mode, lock.retainUntil, lock.legalHold
+
+		opts, err := r.objectOptionFields()
+		if err != nil {
+			return err
+		}
+		in.ServerSideEncryption, in.SSEKMSKeyId, in.SSEKMSEncryptionContext = opts.sse, opts.kmsKeyID, opts.kmsContext
+		in.SSECustomerAlgorithm, in.SSECustomerKey, in.SSECustomerKeyMD5 = opts.sseCAlgorithm, opts.sseCKey, opts.sseCKeyMD5
+		in.StorageClass, in.ACL, in.ChecksumAlgorithm = opts.storageClass, opts.acl, opts.checksum
+		in.ContentEncoding, in.ContentDisposition = opts.contentEncoding, opts.contentDisposition
+
 		r.PutInput = in
 		return nil
 
-	case "delete":
-		r.DeleteInput = &s3.DeleteObjectInput{
+	case S3OpMultipartUpload:
+		if r.Reader == nil && r.FilePath == "" {
+			return fmt.Errorf("multipart upload requires Reader or FilePath")
+		}
+		if err := validatePartSize(r.PartSize, r.minPartSize); err != nil {
+			return err
+		}
+		in := &s3.CreateMultipartUploadInput{
 			Bucket: aws.String(r.Bucket),
 			Key:    aws.String(r.Key),
 		}
+		if r.ContentType != "" {
+			in.ContentType = aws.String(r.ContentType)
+		}
+		if md, ok := extractStringMap(r.ExtraOpts, "metadata"); ok && len(md) > 0 {
+			in.Metadata = md
+		}
+		if v, ok := r.ExtraOpts["cache_control"].(string); ok && v != "" {
+			in.CacheControl = aws.String(v)
+		}
+
+		in.Tagging = r.taggingQuery()
+		lock, err := r.objectLockFields()
+		if err != nil {
+			return err
+		}
+		in.ObjectLockMode, in.ObjectLockRetainUntilDate, in.ObjectLockLegalHoldStatus = lock.mode, lock.retainUntil, lock.legalHold
+
+		opts, err := r.objectOptionFields()
+		if err != nil {
+			return err
+		}
+		in.ServerSideEncryption, in.SSEKMSKeyId, in.SSEKMSEncryptionContext = opts.sse, opts.kmsKeyID, opts.kmsContext
+		in.SSECustomerAlgorithm, in.SSECustomerKey, in.SSECustomerKeyMD5 = opts.sseCAlgorithm, opts.sseCKey, opts.sseCKeyMD5
+		in.StorageClass, in.ACL, in.ChecksumAlgorithm = opts.storageClass, opts.acl, opts.checksum
+		in.ContentEncoding, in.ContentDisposition = opts.contentEncoding, opts.contentDisposition
+
+		r.CreateMultipartInput = in
+		return nil
+
+	case S3OpDelete:
+		r.DeleteInput = &s3.DeleteObjectInput{
+			Bucket:    aws.String(r.Bucket),
+			Key:       aws.String(r.Key),
+			VersionId: optionalString(r.VersionId),
+		}
+		return nil
+
+	case S3OpListVersions:
+		r.ListVersionsInput = &s3.ListObjectVersionsInput{
+			Bucket: aws.String(r.Bucket),
+			Prefix: optionalString(r.Prefix),
+		}
+		return nil
+
+	case S3OpGetTagging:
+		r.GetTaggingInput = &s3.GetObjectTaggingInput{
+			Bucket:    aws.String(r.Bucket),
+			Key:       aws.String(r.Key),
+			VersionId: optionalString(r.VersionId),
+		}
+		return nil
+
+	case S3OpPutTagging:
+		keys := make([]string, 0, len(r.Tags))
+		for k := range r.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		tagSet := make([]s3types.Tag, 0, len(keys))
+		for _, k := range keys {
+			tagSet = append(tagSet, s3types.Tag{Key: aws.String(k), Value: aws.String(r.Tags[k])})
+		}
+		r.PutTaggingInput = &s3.PutObjectTaggingInput{
+			Bucket:    aws.String(r.Bucket),
+			Key:       aws.String(r.Key),
+			VersionId: optionalString(r.VersionId),
+			Tagging:   &s3types.Tagging{TagSet: tagSet},
+		}
+		return nil
+
+	case S3OpHead:
+		in := &s3.HeadObjectInput{
+			Bucket:      aws.String(r.Bucket),
+			Key:         aws.String(r.Key),
+			IfMatch:     optionalString(r.IfMatch),
+			IfNoneMatch: optionalString(r.IfNoneMatch),
+			VersionId:   optionalString(r.VersionId),
+		}
+		in.SSECustomerAlgorithm, in.SSECustomerKey, in.SSECustomerKeyMD5 = r.sseCustomerFields()
+		r.HeadInput = in
+		return nil
+
+	case S3OpCopy:
+		if r.SourceKey == "" {
+			return fmt.Errorf("s3 copy requires SourceKey")
+		}
+		srcBucket := r.SourceBucket
+		if srcBucket == "" {
+			srcBucket = r.Bucket
+		}
+		source := srcBucket + "/" + escapeKey(r.SourceKey)
+		if r.SourceVersionId != "" {
+			source += "?versionId=" + url.QueryEscape(r.SourceVersionId)
+		}
+		in := &s3.CopyObjectInput{
+			Bucket:     aws.String(r.Bucket),
+			Key:        aws.String(r.Key),
+			CopySource: aws.String(source),
+		}
+		// Metadata is copied from the source unless new values are supplied.
+		if md, ok := extractStringMap(r.ExtraOpts, "metadata"); ok && len(md) > 0 {
+			in.Metadata = md
+			in.MetadataDirective = s3types.MetadataDirectiveReplace
+			if r.ContentType != "" {
+				in.ContentType = aws.String(r.ContentType)
+			}
+		}
+		opts, err := r.objectOptionFields()
+		if err != nil {
+			return err
+		}
+		in.ServerSideEncryption, in.SSEKMSKeyId, in.SSEKMSEncryptionContext = opts.sse, opts.kmsKeyID, opts.kmsContext
+		in.SSECustomerAlgorithm, in.SSECustomerKey, in.SSECustomerKeyMD5 = opts.sseCAlgorithm, opts.sseCKey, opts.sseCKeyMD5
+		in.StorageClass, in.ACL, in.ChecksumAlgorithm = opts.storageClass, opts.acl, opts.checksum
+		in.CopySourceSSECustomerAlgorithm, in.CopySourceSSECustomerKey, in.CopySourceSSECustomerKeyMD5 = sseCustomerFields(r.SourceSSECustomerAlgorithm, r.SourceSSECustomerKey)
+		r.CopyInput = in
+		return nil
+
+	case S3OpDeleteMany:
+		if len(r.Keys) == 0 {
+			return fmt.Errorf("s3 delete_many requires Keys")
+		}
+		for start := 0; start < len(r.Keys); start += MaxDeleteManyBatch {
+			batch := r.Keys[start:min(start+MaxDeleteManyBatch, len(r.Keys))]
+			objects := make([]s3types.ObjectIdentifier, len(batch))
+			for i, key := range batch {
+				objects[i] = s3types.ObjectIdentifier{Key: aws.String(key)}
+			}
+			r.DeleteManyInputs = append(r.DeleteManyInputs, &s3.DeleteObjectsInput{
+				Bucket: aws.String(r.Bucket),
+				Delete: &s3types.Delete{Objects: objects},
+			})
+		}
 		return nil
 
-	case "list":
+	case S3OpList:
+		if r.MaxKeys < 0 {
+			return fmt.Errorf("list MaxKeys must not be negative")
+		}
 		r.ListInput = &s3.ListObjectsV2Input{
 			Bucket: aws.String(r.Bucket),
 		}
 		if r.Prefix != "" {
 			r.ListInput.Prefix = aws.String(r.Prefix)
 		}
+		if r.MaxKeys > 0 {
+			r.ListInput.MaxKeys = aws.Int32(int32(min(r.MaxKeys, maxListPage)))
+		}
 		return nil
 
 	default:
@@ -74,6 +266,75 @@ func (r *S3Request) Finalize() error {
 	}
 }
 
+// knownExtraOpts lists the ExtraOpts keys Finalize understands. Anything else
+// is rejected so misspelt or unsupported options are not silently dropped.
+var knownExtraOpts = map[string]bool{
+	"metadata":      true,
+	"cache_control": true,
+}
+
+// checkExtraOpts reports unknown ExtraOpts keys in sorted order.
+func (r *S3Request) checkExtraOpts() error {
+	var unknown []string
+	for k := range r.ExtraOpts {
+		if !knownExtraOpts[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("s3 %s: unknown ExtraOpts keys: %s", r.Operation, strings.Join(unknown, ", "))
+}
+
+type objectOptions struct {
+	sse                s3types.ServerSideEncryption
+	kmsKeyID           *string
+	kmsContext         *string
+	sseCAlgorithm      *string
+	sseCKey            *string
+	sseCKeyMD5         *string
+	storageClass       s3types.StorageClass
+	acl                s3types.ObjectCannedACL
+	checksum           s3types.ChecksumAlgorithm
+	contentEncoding    *string
+	contentDisposition *string
+}
+
+// objectOptionFields validates and maps the encryption, storage and checksum
+// options shared by the object-writing operations.
+func (r *S3Request) objectOptionFields() (objectOptions, error) {
+	opts := objectOptions{
+		sse:                r.ServerSideEncryption,
+		kmsKeyID:           optionalString(r.SSEKMSKeyId),
+		storageClass:       r.StorageClass,
+		acl:                r.ACL,
+		checksum:           r.ChecksumAlgorithm,
+		contentEncoding:    optionalString(r.ContentEncoding),
+		contentDisposition: optionalString(r.ContentDisposition),
+	}
+	opts.sseCAlgorithm, opts.sseCKey, opts.sseCKeyMD5 = r.sseCustomerFields()
+
+	if opts.sseCKey != nil && r.ServerSideEncryption != "" {
+		return opts, fmt.Errorf("s3 %s: SSECustomerKey and ServerSideEncryption are mutually exclusive", r.Operation)
+	}
+	isKMS := r.ServerSideEncryption == s3types.ServerSideEncryptionAwsKms ||
+		r.ServerSideEncryption == s3types.ServerSideEncryptionAwsKmsDsse
+	if !isKMS && (r.SSEKMSKeyId != "" || len(r.SSEKMSEncryptionContext) > 0) {
+		return opts, fmt.Errorf("s3 %s: SSEKMSKeyId and SSEKMSEncryptionContext require aws:kms encryption", r.Operation)
+	}
+	if len(r.SSEKMSEncryptionContext) > 0 {
+		// S3 expects the context as base64-encoded JSON.
+		raw, err := json.Marshal(r.SSEKMSEncryptionContext)
+		if err != nil {
+			return opts, fmt.Errorf("encode kms encryption context: %w", err)
+		}
+		opts.kmsContext = aws.String(base64.StdEncoding.EncodeToString(raw))
+	}
+	return opts, nil
+}
+
 // extractStringMap reads ExtraOpts[key] as either map[string]string or map[string]any
 // with string values, returning a map[string]string.
 func extractStringMap(extra map[string]any, key string) (map[string]string, bool) {
@@ -106,3 +367,84 @@ func extractStringMap(extra map[string]any, key string) (map[string]string, bool
 		return nil, false
 	}
 }
+
+// escapeKey URL-encodes each path segment of an object key for CopySource.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// taggingQuery encodes Tags in the URL query form PutObject expects.
+func (r *S3Request) taggingQuery() *string {
+	if len(r.Tags) == 0 {
+		return nil
+	}
+	values := make(url.Values, len(r.Tags))
+	for k, v := range r.Tags {
+		values.Set(k, v)
+	}
+	return aws.String(values.Encode())
+}
+
+type objectLock struct {
+	mode        s3types.ObjectLockMode
+	retainUntil *time.Time
+	legalHold   s3types.ObjectLockLegalHoldStatus
+}
+
+// objectLockFields validates and maps the object lock settings.
+func (r *S3Request) objectLockFields() (objectLock, error) {
+	var lock objectLock
+	if (r.ObjectLockMode == "") != r.ObjectLockRetainUntil.IsZero() {
+		return lock, fmt.Errorf("s3 %s: ObjectLockMode and ObjectLockRetainUntil must be set together", r.Operation)
+	}
+	if r.ObjectLockMode != "" {
+		lock.mode = r.ObjectLockMode
+		lock.retainUntil = aws.Time(r.ObjectLockRetainUntil.UTC())
+	}
+	if r.LegalHold {
+		lock.legalHold = s3types.ObjectLockLegalHoldStatusOn
+	}
+	return lock, nil
+}
+
+// optionalString maps an empty string to a nil SDK field.
+func optionalString(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return aws.String(v)
+}
+
+// sseCustomerFields derives the SDK SSE-C triple from the raw customer key.
+func (r *S3Request) sseCustomerFields() (algorithm, key, keyMD5 *string) {
+	return sseCustomerFields(r.SSECustomerAlgorithm, r.SSECustomerKey)
+}
+
+func sseCustomerFields(alg string, rawKey []byte) (algorithm, key, keyMD5 *string) {
+	if len(rawKey) == 0 {
+		return nil, nil, nil
+	}
+	if alg == "" {
+		alg = "AES256"
+	}
+	sum := md5.Sum(rawKey)
+	return aws.String(alg),
+		aws.String(base64.StdEncoding.EncodeToString(rawKey)),
+		aws.String(base64.StdEncoding.EncodeToString(sum[:]))
+}
+
+// validatePartSize rejects part sizes S3 would refuse; zero means the default.
+// A zero minimum means MinMultipartPartSize.
+func validatePartSize(size, minimum int64) error {
+	if minimum <= 0 {
+		minimum = MinMultipartPartSize
+	}
+	if size < 0 || (size > 0 && size < minimum) {
+		return fmt.Errorf("multipart upload PartSize %d is below the S3 minimum of %d bytes", size, minimum)
+	}
+	return nil
+}
@@ -9,7 +9,7 @@ import (
 // StaticS3MetaMiddleware adds default metadata to each S3 put operation.
 func StaticS3MetaMiddleware(meta map[string]string) Middleware {
 	return func(ctx context.Context, r *S3Request) error {
-		if r.Operation != "put" {
+		if r.Operation != S3OpPut {
 			return nil
 		}
 		if r.ExtraOpts == nil {
@@ -41,7 +41,7 @@ func LoggingMiddleware(logger func(msg string)) Middleware {
 	return func(ctx context.Context, r *S3Request) error {
 		logger(fmt.Sprintf(
 			"[S3] %s s3://%s/%s",
-			strings.ToUpper(r.Operation),
+			strings.ToUpper(r.Operation.String()),
 			r.Bucket,
 			r.Key,
 		))
@@ -17,7 +17,7 @@ func TestStaticS3MetaMiddleware_Golden(t *testing.T) {
 		{
 			name: "no-op for non-put",
 			req: &S3Request{
-				Operation: "get",
+				Operation: S3OpGet,
 				ExtraOpts: map[string]any{},
 			},
 			meta: map[string]string{"a": "1"},
@@ -26,7 +26,7 @@ func TestStaticS3MetaMiddleware_Golden(t *testing.T) {
 		{
 			name: "creates ExtraOpts and metadata map when missing",
 			req: &S3Request{
-				Operation: "put",
+				Operation: S3OpPut,
 			},
 			meta: map[string]string{"a": "1"},
 			want: map[string]any{
@@ -36,7 +36,7 @@ func TestStaticS3MetaMiddleware_Golden(t *testing.T) {
 		{
 			name: "merges into existing metadata map",
 			req: &S3Request{
-				Operation: "put",
+				Operation: S3OpPut,
 				ExtraOpts: map[string]any{
 					"metadata": map[string]string{"a": "old", "keep": "y"},
 				},
@@ -49,7 +49,7 @@ func TestStaticS3MetaMiddleware_Golden(t *testing.T) {
 		{
 			name: "if metadata exists but is wrong type, replaces with new map",
 			req: &S3Request{
-				Operation: "put",
+				Operation: S3OpPut,
 				ExtraOpts: map[string]any{
 					"metadata": "nope",
 				},
@@ -79,7 +79,7 @@ func TestLoggingMiddleware_Format_Golden(t *testing.T) {
 	mw := LoggingMiddleware(func(msg string) { got = msg })
 
 	r := &S3Request{
-		Operation: "put",
+		Operation: S3OpPut,
 		Bucket:    "bucket",
 		Key:       "key",
 	}
@@ -94,7 +94,7 @@ func TestLoggingMiddleware_Format_Golden(t *testing.T) {
 }
 
 func ExampleStaticS3MetaMiddleware() {
-	r := &S3Request{Operation: "put"}
+	r := &S3Request{Operation: S3OpPut}
 	_ = StaticS3MetaMiddleware(map[string]string{"a": "1"})(context.Background(), r)
 	fmt.Println(r.ExtraOpts["metadata"])
 	// Output: map[a:1]
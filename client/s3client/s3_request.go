@@ -28,19 +28,36 @@ func (c *S3Client) ProcessRequest(ctx context.Context, reqCfg *dto.RequestConfig
 		}
 	}
 
+	r.minPartSize = c.minPartSize
 	if err := r.Finalize(); err != nil {
 		return dto.Response{}, err
 	}
 
 	switch r.Operation {
-	case "get":
+	case S3OpGet:
 		return c.doGet(ctx, r)
-	case "put":
+	case S3OpPut:
 		return c.doPut(ctx, r)
-	case "delete":
+	case S3OpDelete:
 		return c.doDelete(ctx, r)
-	case "list":
+	case S3OpList:
 		return c.doList(ctx, r)
+	case S3OpMultipartUpload:
+		return c.doMultipartUpload(ctx, r)
+	case S3OpHead:
+		return c.doHead(ctx, r)
+	case S3OpCopy:
+		return c.doCopy(ctx, r)
+	case S3OpDeleteMany:
+		return c.doDeleteMany(ctx, r)
+	case S3OpPresignGet, S3OpPresignPut:
+		return c.doPresign(ctx, r)
+	case S3OpListVersions:
+		return c.doListVersions(ctx, r)
+	case S3OpGetTagging:
+		return c.doGetTagging(ctx, r)
+	case S3OpPutTagging:
+		return c.doPutTagging(ctx, r)
 	default:
 		return dto.Response{}, fmt.Errorf("unsupported s3 operation: %s", r.Operation)
 	}
@@ -2,31 +2,259 @@ package s3client
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
 )
 
-// S3RequestConfig defines the structure of an S3 request operation.
+// S3Operation selects the S3 API call performed by a request. It is an int
+// enum so only the S3Op constants name an operation; in JSON and YAML it is
+// written as its name, e.g. "multipart_upload".
+type S3Operation int
+
+const (
+	S3OpGet S3Operation = iota + 1
+	S3OpPut
+	S3OpDelete
+	S3OpList
+	S3OpMultipartUpload
+	S3OpHead
+	S3OpCopy
+	S3OpDeleteMany
+	S3OpPresignGet
+	S3OpPresignPut
+	S3OpListVersions
+	S3OpGetTagging
+	S3OpPutTagging
+)
+
+var s3OperationNames = map[S3Operation]string{
+	S3OpGet:             "get",
+	S3OpPut:             "put",
+	S3OpDelete:          "delete",
+	S3OpList:            "list",
+	S3OpMultipartUpload: "multipart_upload",
+	S3OpHead:            "head",
+	S3OpCopy:            "copy",
+	S3OpDeleteMany:      "delete_many",
+	S3OpPresignGet:      "presign_get",
+	S3OpPresignPut:      "presign_put",
+	S3OpListVersions:    "list_versions",
+	S3OpGetTagging:      "get_tagging",
+	S3OpPutTagging:      "put_tagging",
+}
+
+// ParseS3Operation returns the operation named s.
+func ParseS3Operation(s string) (S3Operation, error) {
+	for op, name := range s3OperationNames {
+		if name == s {
+			return op, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown s3 operation %q", s)
+}
+
+func (o S3Operation) String() string {
+	if name, ok := s3OperationNames[o]; ok {
+		return name
+	}
+	if o == 0 {
+		return ""
+	}
+	return "S3Operation(" + strconv.Itoa(int(o)) + ")"
+}
+
+func (o S3Operation) MarshalText() ([]byte, error) {
+	if o == 0 {
+		return nil, nil
+	}
+	if _, ok := s3OperationNames[o]; !ok {
+		return nil, fmt.Errorf("unknown s3 operation %d", int(o))
+	}
+	return []byte(o.String()), nil
+}
+
+func (o *S3Operation) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*o = 0
+		return nil
+	}
+	op, err := ParseS3Operation(string(text))
+	if err != nil {
+		return err
+	}
+	*o = op
+	return nil
+}
+
+// S3RequestConfig defines the structure of an S3 request operation. Reader,
+// PartRetryDelay and the SSE-C keys are not serialised; set them again after
+// decoding a persisted request.
 type S3RequestConfig struct {
-	Operation string // "get", "put", "delete", "list"
-	Bucket    string
-	Key       string
+	Operation S3Operation `json:"operation,omitempty" yaml:"operation,omitempty"`
+	Bucket    string      `json:"bucket,omitempty" yaml:"bucket,omitempty"`
+	Key       string      `json:"key,omitempty" yaml:"key,omitempty"`
 
 	// Optional depending on operation
-	Body        []byte
-	Prefix      string
-	ContentType string
-	ExtraOpts   map[string]interface{}
-	Headers     map[string]string
+	Body        []byte                 `json:"body,omitempty" yaml:"body,omitempty"`
+	Prefix      string                 `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	ContentType string                 `json:"content_type,omitempty" yaml:"content_type,omitempty"`
+	ExtraOpts   map[string]interface{} `json:"extra_opts,omitempty" yaml:"extra_opts,omitempty"`
+	Headers     map[string]string      `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Get options. Range uses the HTTP form, e.g. "bytes=0-1023".
+	Range       string `json:"range,omitempty" yaml:"range,omitempty"`
+	IfMatch     string `json:"if_match,omitempty" yaml:"if_match,omitempty"`
+	IfNoneMatch string `json:"if_none_match,omitempty" yaml:"if_none_match,omitempty"`
+	VersionId   string `json:"version_id,omitempty" yaml:"version_id,omitempty"`
+	// SSECustomerKey is the raw 256-bit key for SSE-C; it is base64 encoded and
+	// its MD5 computed when the request is finalized. Algorithm defaults to AES256.
+	SSECustomerAlgorithm string `json:"sse_customer_algorithm,omitempty" yaml:"sse_customer_algorithm,omitempty"`
+	SSECustomerKey       []byte `json:"-" yaml:"-"`
+
+	// Stream returns the object body unread in dto.Response.Stream.
+	Stream bool `json:"stream,omitempty" yaml:"stream,omitempty"`
+	// DestinationPath writes the object to a file using parallel ranged fetches
+	// sized by PartSize and Concurrency.
+	DestinationPath string `json:"destination_path,omitempty" yaml:"destination_path,omitempty"`
+
+	// Copy source. SourceBucket defaults to Bucket.
+	SourceBucket    string `json:"source_bucket,omitempty" yaml:"source_bucket,omitempty"`
+	SourceKey       string `json:"source_key,omitempty" yaml:"source_key,omitempty"`
+	SourceVersionId string `json:"source_version_id,omitempty" yaml:"source_version_id,omitempty"`
+	// SourceSSECustomerKey decrypts a source object stored with SSE-C. It is
+	// independent of SSECustomerKey, which encrypts the copy.
+	SourceSSECustomerAlgorithm string `json:"source_sse_customer_algorithm,omitempty" yaml:"source_sse_customer_algorithm,omitempty"`
+	SourceSSECustomerKey       []byte `json:"-" yaml:"-"`
+
+	// Keys lists the objects removed by delete_many.
+	Keys []string `json:"keys,omitempty" yaml:"keys,omitempty"`
+	// MaxKeys caps the keys returned by list; zero follows every page.
+	MaxKeys int `json:"max_keys,omitempty" yaml:"max_keys,omitempty"`
+
+	// Object options applied on put, presign_put, multipart upload and copy.
+	// SSE-C is configured through SSECustomerKey and is exclusive with
+	// ServerSideEncryption. SSEKMSKeyId and SSEKMSEncryptionContext require
+	// ServerSideEncryption aws:kms or aws:kms:dsse.
+	ServerSideEncryption    s3types.ServerSideEncryption `json:"server_side_encryption,omitempty" yaml:"server_side_encryption,omitempty"`
+	SSEKMSKeyId             string                       `json:"sse_kms_key_id,omitempty" yaml:"sse_kms_key_id,omitempty"`
+	SSEKMSEncryptionContext map[string]string            `json:"sse_kms_encryption_context,omitempty" yaml:"sse_kms_encryption_context,omitempty"`
+	StorageClass            s3types.StorageClass         `json:"storage_class,omitempty" yaml:"storage_class,omitempty"`
+	ContentEncoding         string                       `json:"content_encoding,omitempty" yaml:"content_encoding,omitempty"`
+	ContentDisposition      string                       `json:"content_disposition,omitempty" yaml:"content_disposition,omitempty"`
+	ACL                     s3types.ObjectCannedACL      `json:"acl,omitempty" yaml:"acl,omitempty"`
+	// ChecksumAlgorithm asks the SDK to compute and S3 to verify a flexible
+	// checksum of the body (each part for multipart uploads).
+	ChecksumAlgorithm s3types.ChecksumAlgorithm `json:"checksum_algorithm,omitempty" yaml:"checksum_algorithm,omitempty"`
+
+	// Tags are written by put_tagging and attached to put and multipart uploads.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// Object lock settings applied on put and multipart upload. The bucket must
+	// have object lock enabled. Mode and RetainUntil must be set together.
+	ObjectLockMode        s3types.ObjectLockMode `json:"object_lock_mode,omitempty" yaml:"object_lock_mode,omitempty"`
+	ObjectLockRetainUntil time.Time              `json:"object_lock_retain_until,omitempty" yaml:"object_lock_retain_until,omitempty"`
+	LegalHold             bool                   `json:"legal_hold,omitempty" yaml:"legal_hold,omitempty"`
+
+	// Expires is the lifetime of presigned URLs, DefaultPresignExpiry when zero.
+	Expires time.Duration `json:"expires,omitempty" yaml:"expires,omitempty"`
+
+	// Multipart upload source. FilePath is preferred as parts are read in
+	// place and can be re-sent; Reader is consumed once and buffered per part.
+	Reader   io.Reader `json:"-" yaml:"-"`
+	FilePath string    `json:"file_path,omitempty" yaml:"file_path,omitempty"`
+	// PartSize and Concurrency fall back to the package defaults when zero.
+	// PartSize must be at least MinMultipartPartSize.
+	PartSize    int64 `json:"part_size,omitempty" yaml:"part_size,omitempty"`
+	Concurrency int   `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+	// PartRetries nil uses DefaultMultipartPartRetries; zero disables retries.
+	PartRetries    *int             `json:"part_retries,omitempty" yaml:"part_retries,omitempty"`
+	PartRetryDelay utils.RetryDelay `json:"-" yaml:"-"`
 }
 
 func (c *S3RequestConfig) Ref() dto.NetClientType {
 	return NetClientS3Ref
 }
 
+// Describe returns the operation as Method and the object as an s3:// URL with
+// operation parameters in the query. Body holds the put body, or the keys of a
+// delete_many one per line; Reader and FilePath sources are not read. The
+// SSE-C key is never included.
+func (c *S3RequestConfig) Describe() (dto.RequestDescription, error) {
+	query := url.Values{}
+	for k, v := range map[string]string{
+		"prefix":          c.Prefix,
+		"versionId":       c.VersionId,
+		"source":          sourceRef(c.SourceBucket, c.Bucket, c.SourceKey),
+		"sourceVersionId": c.SourceVersionId,
+	} {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+	if c.MaxKeys > 0 {
+		query.Set("maxKeys", strconv.Itoa(c.MaxKeys))
+	}
+	if len(c.Tags) > 0 {
+		tags := url.Values{}
+		for k, v := range c.Tags {
+			tags.Set(k, v)
+		}
+		query.Set("tagging", tags.Encode())
+	}
+
+	desc := dto.RequestDescription{
+		ClientType: NetClientS3Ref,
+		Method:     c.Operation.String(),
+		URL:        s3URI(c.Bucket, c.Key),
+		Headers:    make(map[string]string, len(c.Headers)+4),
+		Body:       c.Body,
+
+		DestinationPath: c.DestinationPath,
+	}
+	if len(query) > 0 {
+		desc.URL += "?" + query.Encode()
+	}
+	for k, v := range c.Headers {
+		desc.Headers[k] = v
+	}
+	for k, v := range map[string]string{
+		"Range":         c.Range,
+		"If-Match":      c.IfMatch,
+		"If-None-Match": c.IfNoneMatch,
+		"Content-Type":  c.ContentType,
+	} {
+		if v != "" {
+			desc.Headers[k] = v
+		}
+	}
+	if c.Operation == S3OpDeleteMany {
+		desc.Body = []byte(strings.Join(c.Keys, "\n"))
+	}
+	return desc, nil
+}
+
+// sourceRef formats a copy source as bucket/key, defaulting to bucket.
+func sourceRef(sourceBucket, bucket, key string) string {
+	if key == "" {
+		return ""
+	}
+	if sourceBucket == "" {
+		sourceBucket = bucket
+	}
+	return sourceBucket + "/" + key
+}
+
 type S3Request struct {
-	Operation string
+	Operation S3Operation
 	Bucket    string
 	Key       string
 
@@ -37,11 +265,62 @@ type S3Request struct {
 	ExtraOpts map[string]any
 	Headers   map[string]string
 
+	Range                string
+	IfMatch              string
+	IfNoneMatch          string
+	VersionId            string
+	SSECustomerAlgorithm string
+	SSECustomerKey       []byte
+	Stream               bool
+	DestinationPath      string
+
+	SourceBucket    string
+	SourceKey       string
+	SourceVersionId string
+
+	SourceSSECustomerAlgorithm string
+	SourceSSECustomerKey       []byte
+	Keys                       []string
+	MaxKeys                    int
+	Expires                    time.Duration
+
+	ServerSideEncryption    s3types.ServerSideEncryption
+	SSEKMSKeyId             string
+	SSEKMSEncryptionContext map[string]string
+	StorageClass            s3types.StorageClass
+	ContentEncoding         string
+	ContentDisposition      string
+	ACL                     s3types.ObjectCannedACL
+	ChecksumAlgorithm       s3types.ChecksumAlgorithm
+
+	Tags                  map[string]string
+	ObjectLockMode        s3types.ObjectLockMode
+	ObjectLockRetainUntil time.Time
+	LegalHold             bool
+
+	Reader         io.Reader
+	FilePath       string
+	PartSize       int64
+	Concurrency    int
+	PartRetries    *int
+	PartRetryDelay utils.RetryDelay
+
 	// Deterministic prepared AWS inputs (built after middleware)
-	PutInput    *s3.PutObjectInput
-	GetInput    *s3.GetObjectInput
-	DeleteInput *s3.DeleteObjectInput
-	ListInput   *s3.ListObjectsV2Input
+	PutInput             *s3.PutObjectInput
+	GetInput             *s3.GetObjectInput
+	DeleteInput          *s3.DeleteObjectInput
+	ListInput            *s3.ListObjectsV2Input
+	CreateMultipartInput *s3.CreateMultipartUploadInput
+	HeadInput            *s3.HeadObjectInput
+	CopyInput            *s3.CopyObjectInput
+	DeleteManyInputs     []*s3.DeleteObjectsInput
+	ListVersionsInput    *s3.ListObjectVersionsInput
+	GetTaggingInput      *s3.GetObjectTaggingInput
+	PutTaggingInput      *s3.PutObjectTaggingInput
+
+	// minPartSize is the owning client's multipart minimum, zero for
+	// MinMultipartPartSize
+	minPartSize int64
 }
 
 func (c *S3RequestConfig) NewRequest(ctx context.Context) (any, error) {
@@ -54,6 +333,45 @@ func (c *S3RequestConfig) NewRequest(ctx context.Context) (any, error) {
 		ContentType: c.ContentType,
 		ExtraOpts:   make(map[string]any, len(c.ExtraOpts)),
 		Headers:     make(map[string]string, len(c.Headers)),
+
+		Range:                c.Range,
+		IfMatch:              c.IfMatch,
+		IfNoneMatch:          c.IfNoneMatch,
+		VersionId:            c.VersionId,
+		SSECustomerAlgorithm: c.SSECustomerAlgorithm,
+		SSECustomerKey:       c.SSECustomerKey,
+		Stream:               c.Stream,
+		DestinationPath:      c.DestinationPath,
+
+		SourceBucket:    c.SourceBucket,
+		SourceKey:       c.SourceKey,
+		SourceVersionId: c.SourceVersionId,
+
+		SourceSSECustomerAlgorithm: c.SourceSSECustomerAlgorithm,
+		SourceSSECustomerKey:       c.SourceSSECustomerKey,
+		Keys:                       append([]string(nil), c.Keys...),
+		MaxKeys:                    c.MaxKeys,
+		Expires:                    c.Expires,
+
+		ServerSideEncryption: c.ServerSideEncryption,
+		SSEKMSKeyId:          c.SSEKMSKeyId,
+		StorageClass:         c.StorageClass,
+		ContentEncoding:      c.ContentEncoding,
+		ContentDisposition:   c.ContentDisposition,
+		ACL:                  c.ACL,
+		ChecksumAlgorithm:    c.ChecksumAlgorithm,
+
+		Tags:                  make(map[string]string, len(c.Tags)),
+		ObjectLockMode:        c.ObjectLockMode,
+		ObjectLockRetainUntil: c.ObjectLockRetainUntil,
+		LegalHold:             c.LegalHold,
+
+		Reader:         c.Reader,
+		FilePath:       c.FilePath,
+		PartSize:       c.PartSize,
+		Concurrency:    c.Concurrency,
+		PartRetries:    c.PartRetries,
+		PartRetryDelay: c.PartRetryDelay,
 	}
 
 	for k, v := range c.Headers {
@@ -62,6 +380,15 @@ func (c *S3RequestConfig) NewRequest(ctx context.Context) (any, error) {
 	for k, v := range c.ExtraOpts {
 		r.ExtraOpts[k] = v
 	}
+	for k, v := range c.Tags {
+		r.Tags[k] = v
+	}
+	if c.SSEKMSEncryptionContext != nil {
+		r.SSEKMSEncryptionContext = make(map[string]string, len(c.SSEKMSEncryptionContext))
+		for k, v := range c.SSEKMSEncryptionContext {
+			r.SSEKMSEncryptionContext[k] = v
+		}
+	}
 
 	return r, nil
 }
@@ -3,8 +3,11 @@ package s3client
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"reflect"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestS3RequestConfig_NewRequest_CopiesMaps_Golden(t *testing.T) {
@@ -17,7 +20,7 @@ func TestS3RequestConfig_NewRequest_CopiesMaps_Golden(t *testing.T) {
 		{
 			name: "copies headers and extra opts (no aliasing)",
 			in: &S3RequestConfig{
-				Operation: "put",
+				Operation: S3OpPut,
 				Bucket:    "b",
 				Key:       "k",
 				Body:      []byte("x"),
@@ -35,7 +38,7 @@ func TestS3RequestConfig_NewRequest_CopiesMaps_Golden(t *testing.T) {
 				cfg.ExtraOpts["new"] = "x"
 			},
 			want: &S3Request{
-				Operation: "put",
+				Operation: S3OpPut,
 				Bucket:    "b",
 				Key:       "k",
 				Body:      []byte("x"),
@@ -88,3 +91,104 @@ func TestS3RequestConfig_Ref_Golden(t *testing.T) {
 		t.Fatalf("Ref mismatch: got=%v want=%v", cfg.Ref(), NetClientS3Ref)
 	}
 }
+
+func TestS3RequestConfig_Describe_Golden(t *testing.T) {
+	cases := []struct {
+		name     string
+		in       *S3RequestConfig
+		wantURL  string
+		wantBody string
+		wantHdr  map[string]string
+	}{
+		{
+			name:    "ranged get",
+			in:      &S3RequestConfig{Operation: S3OpGet, Bucket: "b", Key: "k", Range: "bytes=0-9", VersionId: "v1"},
+			wantURL: "s3://b/k?versionId=v1",
+			wantHdr: map[string]string{"Range": "bytes=0-9"},
+		},
+		{
+			name:     "put with tags",
+			in:       &S3RequestConfig{Operation: S3OpPut, Bucket: "b", Key: "k", Body: []byte("x"), Tags: map[string]string{"env": "ci"}},
+			wantURL:  "s3://b/k?tagging=env%3Dci",
+			wantBody: "x",
+			wantHdr:  map[string]string{},
+		},
+		{
+			name:     "delete many",
+			in:       &S3RequestConfig{Operation: S3OpDeleteMany, Bucket: "b", Keys: []string{"a", "b"}},
+			wantURL:  "s3://b/",
+			wantBody: "a\nb",
+			wantHdr:  map[string]string{},
+		},
+		{
+			name:    "copy excludes sse-c key",
+			in:      &S3RequestConfig{Operation: S3OpCopy, Bucket: "b", Key: "dst", SourceKey: "src", SSECustomerKey: []byte("secret")},
+			wantURL: "s3://b/dst?source=b%2Fsrc",
+			wantHdr: map[string]string{},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			desc, err := tc.in.Describe()
+			if err != nil {
+				t.Fatalf("Describe: %v", err)
+			}
+			if desc.ClientType != NetClientS3Ref || desc.Method != tc.in.Operation.String() {
+				t.Fatalf("type=%s method=%s", desc.ClientType, desc.Method)
+			}
+			if desc.URL != tc.wantURL {
+				t.Fatalf("url=%q want %q", desc.URL, tc.wantURL)
+			}
+			if string(desc.Body) != tc.wantBody {
+				t.Fatalf("body=%q want %q", desc.Body, tc.wantBody)
+			}
+			if !reflect.DeepEqual(desc.Headers, tc.wantHdr) {
+				t.Fatalf("headers=%v want %v", desc.Headers, tc.wantHdr)
+			}
+		})
+	}
+}
+
+func TestS3Operation_Text_Golden(t *testing.T) {
+	cases := []struct {
+		name    string
+		json    string
+		want    S3Operation
+		wantErr bool
+	}{
+		{name: "named operation", json: `{"operation":"multipart_upload"}`, want: S3OpMultipartUpload},
+		{name: "unset", json: `{}`},
+		{name: "typo", json: `{"operation":"gett"}`, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var cfg S3RequestConfig
+			err := json.Unmarshal([]byte(tc.json), &cfg)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if cfg.Operation != tc.want {
+				t.Fatalf("operation=%v want %v", cfg.Operation, tc.want)
+			}
+			out, err := json.Marshal(&cfg)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			var back S3RequestConfig
+			if err := json.Unmarshal(out, &back); err != nil || back.Operation != tc.want {
+				t.Fatalf("round trip %s: operation=%v err=%v", out, back.Operation, err)
+			}
+		})
+	}
+
+	var fromYAML S3RequestConfig
+	if err := yaml.Unmarshal([]byte("operation: presign_get\n"), &fromYAML); err != nil || fromYAML.Operation != S3OpPresignGet {
+		t.Fatalf("yaml operation=%v err=%v", fromYAML.Operation, err)
+	}
+	if _, err := json.Marshal(&S3RequestConfig{Operation: S3Operation(99)}); err == nil {
+		t.Fatalf("expected marshal error for an unknown operation")
+	}
+}
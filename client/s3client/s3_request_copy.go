@@ -0,0 +1,44 @@
+package s3client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// CopyResult is the JSON body returned by the copy operation.
+type CopyResult struct {
+	ETag            string `json:"etag,omitempty"`
+	VersionId       string `json:"version_id,omitempty"`
+	SourceVersionId string `json:"source_version_id,omitempty"`
+}
+
+// doCopy performs a server-side copy; object data never passes through the client.
+func (c *S3Client) doCopy(ctx context.Context, r *S3Request) (dto.Response, error) {
+	out, err := c.client.CopyObject(ctx, r.CopyInput)
+	if err != nil {
+		return dto.Response{}, fmt.Errorf("s3 copy object: %w", err)
+	}
+
+	result := CopyResult{
+		VersionId:       aws.ToString(out.VersionId),
+		SourceVersionId: aws.ToString(out.CopySourceVersionId),
+	}
+	if out.CopyObjectResult != nil {
+		result.ETag = aws.ToString(out.CopyObjectResult.ETag)
+	}
+	body, err := json.Marshal(result)
+	if err != nil {
+		return dto.Response{}, fmt.Errorf("encode copy result: %w", err)
+	}
+
+	headers := make(http.Header)
+	if result.ETag != "" {
+		headers.Set("ETag", result.ETag)
+	}
+	return dto.Response{StatusCode: http.StatusOK, Headers: headers, Body: body}, nil
+}
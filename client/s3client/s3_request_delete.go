@@ -3,14 +3,26 @@ package s3client
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strconv"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/joy-dx/gonetic/dto"
 )
 
 func (c *S3Client) doDelete(ctx context.Context, r *S3Request) (dto.Response, error) {
-	_, err := c.client.DeleteObject(ctx, r.DeleteInput)
+	out, err := c.client.DeleteObject(ctx, r.DeleteInput)
 	if err != nil {
 		return dto.Response{}, fmt.Errorf("s3 delete object: %w", err)
 	}
-	return dto.Response{StatusCode: 200}, nil
+
+	// On versioned buckets a delete without VersionId creates a delete marker.
+	headers := make(http.Header)
+	if out.VersionId != nil {
+		headers.Set("X-Amz-Version-Id", aws.ToString(out.VersionId))
+	}
+	if out.DeleteMarker != nil {
+		headers.Set("X-Amz-Delete-Marker", strconv.FormatBool(aws.ToBool(out.DeleteMarker)))
+	}
+	return dto.Response{StatusCode: 200, Headers: headers}, nil
 }
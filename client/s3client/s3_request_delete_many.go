@@ -0,0 +1,58 @@
+package s3client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// DeleteManyResult is the JSON body returned by the delete_many operation.
+type DeleteManyResult struct {
+	Deleted []string          `json:"deleted"`
+	Errors  []DeleteManyError `json:"errors,omitempty"`
+}
+
+// DeleteManyError describes a key S3 refused to delete.
+type DeleteManyError struct {
+	Key     string `json:"key"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// doDeleteMany removes Keys in batches of MaxDeleteManyBatch. Per-key
+// failures do not fail the request; they are listed in the result and the
+// status is 207 Multi-Status.
+func (c *S3Client) doDeleteMany(ctx context.Context, r *S3Request) (dto.Response, error) {
+	result := DeleteManyResult{Deleted: []string{}}
+
+	for _, in := range r.DeleteManyInputs {
+		out, err := c.client.DeleteObjects(ctx, in)
+		if err != nil {
+			return dto.Response{}, fmt.Errorf("s3 delete objects: %w", err)
+		}
+		for _, d := range out.Deleted {
+			result.Deleted = append(result.Deleted, aws.ToString(d.Key))
+		}
+		for _, e := range out.Errors {
+			result.Errors = append(result.Errors, DeleteManyError{
+				Key:     aws.ToString(e.Key),
+				Code:    aws.ToString(e.Code),
+				Message: aws.ToString(e.Message),
+			})
+		}
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return dto.Response{}, fmt.Errorf("encode delete result: %w", err)
+	}
+	status := http.StatusOK
+	if len(result.Errors) > 0 {
+		status = http.StatusMultiStatus
+	}
+	return dto.Response{StatusCode: status, Body: body}, nil
+}
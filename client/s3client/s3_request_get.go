@@ -4,26 +4,72 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"strconv"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/joy-dx/gonetic/dto"
 	"github.com/joy-dx/gonetic/utils"
 )
 
 func (c *S3Client) doGet(ctx context.Context, r *S3Request) (dto.Response, error) {
+	if r.DestinationPath != "" {
+		return c.doGetToFile(ctx, r)
+	}
+
 	out, err := c.client.GetObject(ctx, r.GetInput)
 	if err != nil {
 		return dto.Response{}, fmt.Errorf("s3 get object: %w", err)
 	}
+
+	resp := dto.Response{
+		StatusCode: getObjectStatus(out),
+		Headers:    getObjectHeaders(out),
+	}
+	if r.Stream {
+		resp.Stream = out.Body
+		return resp, nil
+	}
 	defer out.Body.Close()
 
 	data, err := io.ReadAll(out.Body)
 	if err != nil {
 		return dto.Response{}, fmt.Errorf("read s3 object: %w", err)
 	}
+	resp.Body = data
+
+	return resp, nil
+}
+
+// getObjectStatus reports 206 for ranged responses to mirror HTTP semantics.
+func getObjectStatus(out *s3.GetObjectOutput) int {
+	if out.ContentRange != nil {
+		return http.StatusPartialContent
+	}
+	return http.StatusOK
+}
 
-	return dto.Response{
-		StatusCode: 200,
-		Body:       data,
-		Headers:    utils.MapToHeader(out.Metadata),
-	}, nil
+// getObjectHeaders returns the object metadata plus the standard object headers.
+func getObjectHeaders(out *s3.GetObjectOutput) http.Header {
+	headers := utils.MapToHeader(out.Metadata)
+	if out.ETag != nil {
+		headers.Set("ETag", aws.ToString(out.ETag))
+	}
+	if out.ContentType != nil {
+		headers.Set("Content-Type", aws.ToString(out.ContentType))
+	}
+	if out.ContentLength != nil {
+		headers.Set("Content-Length", strconv.FormatInt(aws.ToInt64(out.ContentLength), 10))
+	}
+	if out.ContentRange != nil {
+		headers.Set("Content-Range", aws.ToString(out.ContentRange))
+	}
+	if out.LastModified != nil {
+		headers.Set("Last-Modified", out.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if out.VersionId != nil {
+		headers.Set("X-Amz-Version-Id", aws.ToString(out.VersionId))
+	}
+	return headers
 }
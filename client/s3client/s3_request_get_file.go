@@ -0,0 +1,269 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
+)
+
+// DownloadFile implements dto.FileDownloader for s3://bucket/key URLs so S3
+// objects can be fetched through NetSvc.DownloadFile with progress listeners.
+func (c *S3Client) DownloadFile(ctx context.Context, cfg *dto.DownloadFileConfig, destination string) error {
+	bucket, key, err := parseS3URI(cfg.URL)
+	if err != nil {
+		return err
+	}
+
+	reqCfg := &S3RequestConfig{
+		Operation:       S3OpGet,
+		Bucket:          bucket,
+		Key:             key,
+		DestinationPath: destination,
+	}
+	if _, err := c.ProcessRequest(ctx, (&dto.RequestConfig{}).WithReqConfig(reqCfg)); err != nil {
+		return err
+	}
+
+	if cfg.Checksum != "" {
+		if checkErr := utils.Sha256SumVerify(destination, cfg.Checksum); checkErr != nil {
+			c.publishTransfer(dto.TransferNotification{
+				Source:      cfg.URL,
+				Destination: destination,
+				Status:      dto.ERROR,
+				Percentage:  100,
+				Message:     "failed to verify checksum",
+			})
+			return fmt.Errorf("checksum verification failed: %w", checkErr)
+		}
+	}
+	return nil
+}
+
+// doGetToFile writes the object to DestinationPath. The first chunk is fetched
+// alone to learn the object size and ETag; the remaining chunks are fetched
+// in parallel, pinned to that ETag so a concurrent overwrite cannot produce a
+// mixed file. An explicit Range is honoured as a single request. Chunks go to
+// a temporary file renamed over DestinationPath once complete, so a failed
+// download leaves any existing file untouched.
+func (c *S3Client) doGetToFile(ctx context.Context, r *S3Request) (dto.Response, error) {
+	source := s3URI(r.Bucket, r.Key)
+	destination := r.DestinationPath
+
+	fail := func(err error) (dto.Response, error) {
+		status := dto.ERROR
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			status = dto.STOPPED
+		}
+		c.publishTransfer(dto.TransferNotification{
+			Source:      source,
+			Destination: destination,
+			Status:      status,
+			Message:     err.Error(),
+		})
+		return dto.Response{}, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return fail(fmt.Errorf("could not create destination folder %q: %w", destination, err))
+	}
+	out, err := os.CreateTemp(filepath.Dir(destination), filepath.Base(destination)+".*.tmp")
+	if err != nil {
+		return fail(fmt.Errorf("could not create output file %q: %w", destination, err))
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	partSize := r.PartSize
+	if partSize <= 0 {
+		partSize = DefaultMultipartPartSize
+	}
+
+	first := *r.GetInput
+	if first.Range == nil {
+		first.Range = aws.String(fmt.Sprintf("bytes=0-%d", partSize-1))
+	}
+	head, err := c.client.GetObject(ctx, &first)
+	if err != nil && r.Range == "" && hasHTTPStatus(err, http.StatusRequestedRangeNotSatisfiable) {
+		// Zero-length objects cannot satisfy any range.
+		plain := *r.GetInput
+		head, err = c.client.GetObject(ctx, &plain)
+	}
+	if err != nil {
+		return fail(fmt.Errorf("s3 get object: %w", err))
+	}
+
+	written, err := io.Copy(io.NewOffsetWriter(out, 0), head.Body)
+	head.Body.Close()
+	if err != nil {
+		return fail(fmt.Errorf("file transfer failed for %s: %w", source, err))
+	}
+
+	total, ok := contentRangeTotal(aws.ToString(head.ContentRange))
+	if !ok || r.Range != "" {
+		total = written
+	}
+
+	var done atomic.Int64
+	report := func(n int64) {
+		downloaded := done.Add(n)
+		var pct float64
+		if total > 0 {
+			pct = float64(downloaded) / float64(total) * 100
+		}
+		c.publishTransfer(dto.TransferNotification{
+			Source:      source,
+			Destination: destination,
+			Status:      dto.IN_PROGRESS,
+			Downloaded:  downloaded,
+			TotalSize:   total,
+			Percentage:  pct,
+		})
+	}
+	report(written)
+
+	if written < total {
+		etag := head.ETag
+		if r.GetInput.IfMatch != nil {
+			etag = r.GetInput.IfMatch
+		}
+		if err := c.getRanges(ctx, r, out, written, total, partSize, etag, report); err != nil {
+			return fail(err)
+		}
+	}
+	if err := commitFile(out, destination); err != nil {
+		return fail(fmt.Errorf("could not write output file %q: %w", destination, err))
+	}
+
+	c.publishTransfer(dto.TransferNotification{
+		Source:      source,
+		Destination: destination,
+		Status:      dto.COMPLETE,
+		Downloaded:  total,
+		TotalSize:   total,
+		Percentage:  100,
+		Message:     "download complete",
+	})
+
+	headers := getObjectHeaders(head)
+	headers.Del("Content-Range")
+	headers.Set("Content-Length", strconv.FormatInt(total, 10))
+	return dto.Response{StatusCode: http.StatusOK, Headers: headers}, nil
+}
+
+// getRanges fetches [offset, total) in partSize chunks with bounded concurrency.
+func (c *S3Client) getRanges(
+	ctx context.Context,
+	r *S3Request,
+	out io.WriterAt,
+	offset, total, partSize int64,
+	etag *string,
+	report func(n int64),
+) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultMultipartConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+schedule:
+	for start := offset; start < total; start += partSize {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break schedule
+		}
+
+		end := min(start+partSize, total) - 1
+		wg.Add(1)
+		go func(start, end int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			in := *r.GetInput
+			in.Range = aws.String(fmt.Sprintf("bytes=%d-%d", start, end))
+			in.IfMatch = etag
+			part, err := c.client.GetObject(ctx, &in)
+			if err != nil {
+				cancel(fmt.Errorf("s3 get object range %d-%d: %w", start, end, err))
+				return
+			}
+			defer part.Body.Close()
+
+			n, err := io.Copy(io.NewOffsetWriter(out, start), part.Body)
+			if err == nil && n != end-start+1 {
+				err = fmt.Errorf("short read: got %d bytes want %d", n, end-start+1)
+			}
+			if err != nil {
+				cancel(fmt.Errorf("s3 get object range %d-%d: %w", start, end, err))
+				return
+			}
+			report(n)
+		}(start, end)
+	}
+	wg.Wait()
+
+	return context.Cause(ctx)
+}
+
+// commitFile flushes and closes tmp, then renames it over destination.
+func commitFile(tmp *os.File, destination string) error {
+	if err := tmp.Chmod(0o644); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), destination)
+}
+
+// contentRangeTotal extracts the complete length from "bytes 0-99/1234".
+func contentRangeTotal(contentRange string) (int64, bool) {
+	slash := strings.LastIndexByte(contentRange, '/')
+	if slash < 0 {
+		return 0, false
+	}
+	total, err := strconv.ParseInt(contentRange[slash+1:], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return total, true
+}
+
+// hasHTTPStatus reports whether an SDK error carries the given HTTP status.
+func hasHTTPStatus(err error, status int) bool {
+	var respErr interface{ HTTPStatusCode() int }
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == status
+}
+
+// parseS3URI splits s3://bucket/key into its parts.
+func parseS3URI(raw string) (string, string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", fmt.Errorf("parse s3 url: %w", err)
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	if u.Scheme != "s3" || u.Host == "" || key == "" {
+		return "", "", fmt.Errorf("invalid s3 url %q: want s3://bucket/key", raw)
+	}
+	return u.Host, key, nil
+}
@@ -0,0 +1,312 @@
+package s3client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// rangedObject serves a single in-memory object honouring Range and IfMatch.
+func rangedObject(data []byte, etag string) func(*s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	return func(in *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+		if in.IfMatch != nil && aws.ToString(in.IfMatch) != etag {
+			return nil, fmt.Errorf("precondition failed")
+		}
+		out := &s3.GetObjectOutput{ETag: aws.String(etag)}
+		if in.Range == nil {
+			out.Body = io.NopCloser(bytes.NewReader(data))
+			out.ContentLength = aws.Int64(int64(len(data)))
+			return out, nil
+		}
+		var start, end int64
+		if _, err := fmt.Sscanf(aws.ToString(in.Range), "bytes=%d-%d", &start, &end); err != nil {
+			return nil, err
+		}
+		if start >= int64(len(data)) {
+			return nil, fmt.Errorf("invalid range")
+		}
+		end = min(end, int64(len(data))-1)
+		out.Body = io.NopCloser(bytes.NewReader(data[start : end+1]))
+		out.ContentLength = aws.Int64(end - start + 1)
+		out.ContentRange = aws.String(fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+		return out, nil
+	}
+}
+
+func TestS3Client_GetToFile_Golden(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789", 25)) // 250 bytes
+
+	cases := []struct {
+		name      string
+		cfg       func(dest string) *S3RequestConfig
+		wantData  []byte
+		wantCalls int
+	}{
+		{
+			name: "parallel ranged fetch reassembles object",
+			cfg: func(dest string) *S3RequestConfig {
+				return &S3RequestConfig{
+					Operation:       S3OpGet,
+					Bucket:          "b",
+					Key:             "k",
+					DestinationPath: dest,
+					PartSize:        40,
+					Concurrency:     3,
+				}
+			},
+			wantData:  data,
+			wantCalls: 7,
+		},
+		{
+			name: "object smaller than part size needs a single request",
+			cfg: func(dest string) *S3RequestConfig {
+				return &S3RequestConfig{
+					Operation:       S3OpGet,
+					Bucket:          "b",
+					Key:             "k",
+					DestinationPath: dest,
+				}
+			},
+			wantData:  data,
+			wantCalls: 1,
+		},
+		{
+			name: "explicit range is written as-is",
+			cfg: func(dest string) *S3RequestConfig {
+				return &S3RequestConfig{
+					Operation:       S3OpGet,
+					Bucket:          "b",
+					Key:             "k",
+					Range:           "bytes=10-19",
+					DestinationPath: dest,
+					PartSize:        4,
+				}
+			},
+			wantData:  data[10:20],
+			wantCalls: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, f := newTestClient(t)
+			f.getFn = rangedObject(data, `"etag-1"`)
+			log := &transferLog{}
+			c.SetTransferPublisher(log.publish)
+
+			dest := filepath.Join(t.TempDir(), "nested", "out.bin")
+			resp, err := c.ProcessRequest(context.Background(), mustReq(t, tc.cfg(dest)))
+			if err != nil {
+				t.Fatalf("ProcessRequest error: %v", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status=%d want 200", resp.StatusCode)
+			}
+
+			got, err := os.ReadFile(dest)
+			if err != nil {
+				t.Fatalf("read dest: %v", err)
+			}
+			if !bytes.Equal(got, tc.wantData) {
+				t.Fatalf("file mismatch: got %q want %q", got, tc.wantData)
+			}
+			if len(f.gotGet) != tc.wantCalls {
+				t.Fatalf("GetObject calls=%d want %d", len(f.gotGet), tc.wantCalls)
+			}
+			if last := log.last(); last.Status != dto.COMPLETE || last.TotalSize != int64(len(tc.wantData)) {
+				t.Fatalf("unexpected final notification: %+v", last)
+			}
+		})
+	}
+}
+
+func TestS3Client_GetToFile_ETagChangeFails_Golden(t *testing.T) {
+	data := []byte(strings.Repeat("x", 100))
+
+	c, f := newTestClient(t)
+	calls := 0
+	serve := rangedObject(data, `"v1"`)
+	f.getFn = func(in *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+		f.mu.Lock()
+		calls++
+		first := calls == 1
+		f.mu.Unlock()
+		if first {
+			return serve(in)
+		}
+		// Object replaced after the first chunk: pinned IfMatch must fail.
+		return rangedObject(data, `"v2"`)(in)
+	}
+	log := &transferLog{}
+	c.SetTransferPublisher(log.publish)
+
+	_, err := c.ProcessRequest(context.Background(), mustReq(t, &S3RequestConfig{
+		Operation:       S3OpGet,
+		Bucket:          "b",
+		Key:             "k",
+		DestinationPath: filepath.Join(t.TempDir(), "out.bin"),
+		PartSize:        30,
+	}))
+	if err == nil || !strings.Contains(err.Error(), "precondition failed") {
+		t.Fatalf("expected precondition error, got %v", err)
+	}
+	if log.last().Status != dto.ERROR {
+		t.Fatalf("final status=%s want %s", log.last().Status, dto.ERROR)
+	}
+}
+
+// failingReader returns err after the first n bytes.
+type failingReader struct {
+	n   int
+	err error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, r.err
+	}
+	n := min(len(p), r.n)
+	clear(p[:n])
+	r.n -= n
+	return n, nil
+}
+
+func TestS3Client_GetToFile_ReadErrorKeepsDestination_Golden(t *testing.T) {
+	data := []byte(strings.Repeat("y", 100))
+	errRead := errors.New("connection reset")
+
+	cases := []struct {
+		name string
+		// failAt is the range start whose body read fails
+		failAt int64
+	}{
+		{name: "first chunk", failAt: 0},
+		{name: "later chunk", failAt: 60},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, f := newTestClient(t)
+			serve := rangedObject(data, `"e"`)
+			f.getFn = func(in *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+				out, err := serve(in)
+				if err == nil && strings.HasPrefix(aws.ToString(in.Range), fmt.Sprintf("bytes=%d-", tc.failAt)) {
+					out.Body = io.NopCloser(&failingReader{n: 5, err: errRead})
+				}
+				return out, err
+			}
+
+			dir := t.TempDir()
+			dest := filepath.Join(dir, "out.bin")
+			if err := os.WriteFile(dest, []byte("previous"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := c.ProcessRequest(context.Background(), mustReq(t, &S3RequestConfig{
+				Operation:       S3OpGet,
+				Bucket:          "b",
+				Key:             "k",
+				DestinationPath: dest,
+				PartSize:        30,
+			}))
+			if err == nil || !errors.Is(err, errRead) {
+				t.Fatalf("expected read error, got %v", err)
+			}
+
+			if got, _ := os.ReadFile(dest); string(got) != "previous" {
+				t.Fatalf("destination=%q want unchanged", got)
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+				t.Fatalf("temporary file left behind: %v", entries)
+			}
+		})
+	}
+}
+
+func TestS3Client_GetStream_Golden(t *testing.T) {
+	c, f := newTestClient(t)
+	f.getFn = rangedObject([]byte("streamed body"), `"e"`)
+
+	resp, err := c.ProcessRequest(context.Background(), mustReq(t, &S3RequestConfig{
+		Operation: S3OpGet,
+		Bucket:    "b",
+		Key:       "k",
+		Range:     "bytes=0-7",
+		Stream:    true,
+	}))
+	if err != nil {
+		t.Fatalf("ProcessRequest error: %v", err)
+	}
+	if resp.Stream == nil || len(resp.Body) != 0 {
+		t.Fatalf("expected Stream set and Body empty, got stream=%v body=%q", resp.Stream, resp.Body)
+	}
+	defer resp.Stream.Close()
+
+	got, err := io.ReadAll(resp.Stream)
+	if err != nil {
+		t.Fatalf("read stream: %v", err)
+	}
+	if string(got) != "streamed" {
+		t.Fatalf("stream=%q want %q", got, "streamed")
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		t.Fatalf("status=%d want 206", resp.StatusCode)
+	}
+	if resp.Headers.Get("ETag") != `"e"` {
+		t.Fatalf("ETag header=%q", resp.Headers.Get("ETag"))
+	}
+}
+
+func TestS3Client_DownloadFile_Golden(t *testing.T) {
+	data := []byte("downloaded via NetSvc")
+	sum := sha256.Sum256(data)
+
+	cases := []struct {
+		name     string
+		url      string
+		checksum string
+		wantErr  string
+	}{
+		{name: "valid url and checksum", url: "s3://b/dir/file.txt", checksum: hex.EncodeToString(sum[:])},
+		{name: "bad checksum", url: "s3://b/dir/file.txt", checksum: "deadbeef", wantErr: "checksum verification failed"},
+		{name: "non s3 url", url: "https://b/dir/file.txt", wantErr: "invalid s3 url"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, f := newTestClient(t)
+			f.getFn = rangedObject(data, `"e"`)
+
+			dest := filepath.Join(t.TempDir(), "file.txt")
+			err := c.DownloadFile(context.Background(), &dto.DownloadFileConfig{
+				URL:      tc.url,
+				Checksum: tc.checksum,
+			}, dest)
+
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadFile error: %v", err)
+			}
+			if aws.ToString(f.gotGet[0].Key) != "dir/file.txt" {
+				t.Fatalf("key=%q", aws.ToString(f.gotGet[0].Key))
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package s3client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
+)
+
+// HeadResult is the JSON body returned by the head operation.
+type HeadResult struct {
+	Exists        bool              `json:"exists"`
+	ContentLength int64             `json:"content_length,omitempty"`
+	ContentType   string            `json:"content_type,omitempty"`
+	ETag          string            `json:"etag,omitempty"`
+	LastModified  *time.Time        `json:"last_modified,omitempty"`
+	VersionId     string            `json:"version_id,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// doHead reports object existence and attributes. A missing object is not an
+// error: the response has status 404 and Exists=false.
+func (c *S3Client) doHead(ctx context.Context, r *S3Request) (dto.Response, error) {
+	out, err := c.client.HeadObject(ctx, r.HeadInput)
+	if err != nil {
+		var notFound *s3types.NotFound
+		if !errors.As(err, &notFound) && !hasHTTPStatus(err, http.StatusNotFound) {
+			return dto.Response{}, fmt.Errorf("s3 head object: %w", err)
+		}
+		body, _ := json.Marshal(HeadResult{})
+		return dto.Response{StatusCode: http.StatusNotFound, Headers: http.Header{}, Body: body}, nil
+	}
+
+	result := HeadResult{
+		Exists:        true,
+		ContentLength: aws.ToInt64(out.ContentLength),
+		ContentType:   aws.ToString(out.ContentType),
+		ETag:          aws.ToString(out.ETag),
+		LastModified:  out.LastModified,
+		VersionId:     aws.ToString(out.VersionId),
+		Metadata:      out.Metadata,
+	}
+	body, err := json.Marshal(result)
+	if err != nil {
+		return dto.Response{}, fmt.Errorf("encode head result: %w", err)
+	}
+	return dto.Response{StatusCode: http.StatusOK, Headers: headObjectHeaders(out), Body: body}, nil
+}
+
+// headObjectHeaders mirrors getObjectHeaders for HeadObject output.
+func headObjectHeaders(out *s3.HeadObjectOutput) http.Header {
+	headers := utils.MapToHeader(out.Metadata)
+	if out.ETag != nil {
+		headers.Set("ETag", aws.ToString(out.ETag))
+	}
+	if out.ContentType != nil {
+		headers.Set("Content-Type", aws.ToString(out.ContentType))
+	}
+	if out.ContentLength != nil {
+		headers.Set("Content-Length", strconv.FormatInt(aws.ToInt64(out.ContentLength), 10))
+	}
+	if out.LastModified != nil {
+		headers.Set("Last-Modified", out.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if out.VersionId != nil {
+		headers.Set("X-Amz-Version-Id", aws.ToString(out.VersionId))
+	}
+	return headers
+}
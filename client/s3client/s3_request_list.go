@@ -6,17 +6,24 @@ import (
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/joy-dx/gonetic/dto"
 )
 
+// maxListPage is the most keys S3 returns from one ListObjectsV2 call.
+const maxListPage = 1000
+
+// doList writes the keys under Prefix one per line, following pagination
+// until the listing is complete or MaxKeys keys were read.
 func (c *S3Client) doList(ctx context.Context, r *S3Request) (dto.Response, error) {
-	out, err := c.client.ListObjectsV2(ctx, r.ListInput)
+	objects, err := c.listObjects(ctx, r.ListInput, r.MaxKeys)
 	if err != nil {
-		return dto.Response{}, fmt.Errorf("s3 list objects: %w", err)
+		return dto.Response{}, err
 	}
 
 	buf := bytes.NewBuffer(nil)
-	for _, obj := range out.Contents {
+	for _, obj := range objects {
 		fmt.Fprintf(buf, "%s\n", aws.ToString(obj.Key))
 	}
 
@@ -25,3 +32,24 @@ func (c *S3Client) doList(ctx context.Context, r *S3Request) (dto.Response, erro
 		Body:       buf.Bytes(),
 	}, nil
 }
+
+// listObjects follows ListObjectsV2 continuation tokens and returns every
+// object, or the first limit objects when limit is positive.
+func (c *S3Client) listObjects(ctx context.Context, input *s3.ListObjectsV2Input, limit int) ([]s3types.Object, error) {
+	in := *input
+	var objects []s3types.Object
+	for {
+		out, err := c.client.ListObjectsV2(ctx, &in)
+		if err != nil {
+			return nil, fmt.Errorf("s3 list objects: %w", err)
+		}
+		objects = append(objects, out.Contents...)
+		if limit > 0 && len(objects) >= limit {
+			return objects[:limit], nil
+		}
+		if !aws.ToBool(out.IsTruncated) || out.NextContinuationToken == nil {
+			return objects, nil
+		}
+		in.ContinuationToken = out.NextContinuationToken
+	}
+}
@@ -0,0 +1,318 @@
+package s3client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
+)
+
+// multipartPart is a single chunk of the upload source. Body must be
+// seekable so a failed part can be re-sent.
+type multipartPart struct {
+	number int32
+	body   io.ReadSeeker
+	size   int64
+}
+
+// doMultipartUpload streams the request source to S3 in parts, uploading up
+// to Concurrency parts at a time. Any part failing after its retries cancels
+// the remaining work and aborts the upload so no orphaned parts are billed.
+func (c *S3Client) doMultipartUpload(ctx context.Context, r *S3Request) (dto.Response, error) {
+	destination := s3URI(r.Bucket, r.Key)
+	source := r.FilePath
+	if source == "" {
+		source = destination
+	}
+
+	fail := func(status dto.TransferStatus, err error) (dto.Response, error) {
+		c.publishTransfer(dto.TransferNotification{
+			Source:      source,
+			Destination: destination,
+			Status:      status,
+			Message:     err.Error(),
+		})
+		return dto.Response{}, err
+	}
+
+	next, total, closeSource, err := r.multipartSource()
+	if err != nil {
+		return fail(dto.ERROR, err)
+	}
+	defer closeSource()
+
+	created, err := c.client.CreateMultipartUpload(ctx, r.CreateMultipartInput)
+	if err != nil {
+		return fail(dto.ERROR, fmt.Errorf("s3 create multipart upload: %w", err))
+	}
+	uploadID := created.UploadId
+
+	var uploaded atomic.Int64
+	onPart := func(size int64) {
+		done := uploaded.Add(size)
+		var pct float64
+		if total > 0 {
+			pct = float64(done) / float64(total) * 100
+		}
+		c.publishTransfer(dto.TransferNotification{
+			Source:      source,
+			Destination: destination,
+			Status:      dto.IN_PROGRESS,
+			Downloaded:  done,
+			TotalSize:   total,
+			Percentage:  pct,
+		})
+	}
+
+	parts, err := c.uploadParts(ctx, r, uploadID, next, onPart)
+	if err == nil {
+		var out *s3.CompleteMultipartUploadOutput
+		complete := &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(r.Bucket),
+			Key:             aws.String(r.Key),
+			UploadId:        uploadID,
+			MultipartUpload: &s3types.CompletedMultipartUpload{Parts: parts},
+		}
+		complete.SSECustomerAlgorithm, complete.SSECustomerKey, complete.SSECustomerKeyMD5 = r.sseCustomerFields()
+		out, err = c.client.CompleteMultipartUpload(ctx, complete)
+		if err == nil {
+			c.publishTransfer(dto.TransferNotification{
+				Source:      source,
+				Destination: destination,
+				Status:      dto.COMPLETE,
+				Downloaded:  uploaded.Load(),
+				TotalSize:   uploaded.Load(),
+				Percentage:  100,
+				Message:     "upload complete",
+			})
+			headers := make(http.Header)
+			if out.ETag != nil {
+				headers.Set("ETag", aws.ToString(out.ETag))
+			}
+			return dto.Response{StatusCode: 200, Headers: headers}, nil
+		}
+		err = fmt.Errorf("s3 complete multipart upload: %w", err)
+	}
+
+	// The caller's context may already be cancelled; abort regardless.
+	_, abortErr := c.client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(r.Bucket),
+		Key:      aws.String(r.Key),
+		UploadId: uploadID,
+	})
+	if abortErr != nil {
+		err = errors.Join(err, fmt.Errorf("s3 abort multipart upload: %w", abortErr))
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return fail(dto.STOPPED, err)
+	}
+	return fail(dto.ERROR, err)
+}
+
+// uploadParts pulls parts from next and uploads them with bounded
+// concurrency. At most Concurrency parts are held in memory at any time.
+func (c *S3Client) uploadParts(
+	ctx context.Context,
+	r *S3Request,
+	uploadID *string,
+	next func() (multipartPart, error),
+	onPart func(size int64),
+) ([]s3types.CompletedPart, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultMultipartConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		completed []s3types.CompletedPart
+	)
+
+produce:
+	for {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break produce
+		}
+
+		part, err := next()
+		if err != nil {
+			<-sem
+			if !errors.Is(err, io.EOF) {
+				cancel(err)
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(part multipartPart) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			done, err := c.uploadPart(ctx, r, uploadID, part)
+			if err != nil {
+				cancel(err)
+				return
+			}
+			mu.Lock()
+			completed = append(completed, done)
+			mu.Unlock()
+			onPart(part.size)
+		}(part)
+	}
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(completed, func(i, j int) bool {
+		return aws.ToInt32(completed[i].PartNumber) < aws.ToInt32(completed[j].PartNumber)
+	})
+	return completed, nil
+}
+
+// uploadPart sends one part, retrying up to PartRetries times.
+func (c *S3Client) uploadPart(
+	ctx context.Context,
+	r *S3Request,
+	uploadID *string,
+	part multipartPart,
+) (s3types.CompletedPart, error) {
+	retries := DefaultMultipartPartRetries
+	if r.PartRetries != nil {
+		retries = max(*r.PartRetries, 0)
+	}
+	delay := r.PartRetryDelay
+	if delay == nil {
+		delay = utils.ExponentialBackoff{}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			delay.Wait(fmt.Sprintf("%s part %d", s3URI(r.Bucket, r.Key), part.number), attempt)
+		}
+		if err := ctx.Err(); err != nil {
+			return s3types.CompletedPart{}, err
+		}
+		if _, err := part.body.Seek(0, io.SeekStart); err != nil {
+			return s3types.CompletedPart{}, fmt.Errorf("rewind part %d: %w", part.number, err)
+		}
+
+		in := &s3.UploadPartInput{
+			Bucket:            aws.String(r.Bucket),
+			Key:               aws.String(r.Key),
+			UploadId:          uploadID,
+			PartNumber:        aws.Int32(part.number),
+			Body:              part.body,
+			ContentLength:     aws.Int64(part.size),
+			ChecksumAlgorithm: r.ChecksumAlgorithm,
+		}
+		// SSE-C keys must accompany every part.
+		in.SSECustomerAlgorithm, in.SSECustomerKey, in.SSECustomerKeyMD5 = r.sseCustomerFields()
+
+		out, err := c.client.UploadPart(ctx, in)
+		if err == nil {
+			// Part checksums are required to complete an upload created with
+			// a checksum algorithm.
+			return s3types.CompletedPart{
+				ETag:              out.ETag,
+				PartNumber:        aws.Int32(part.number),
+				ChecksumCRC32:     out.ChecksumCRC32,
+				ChecksumCRC32C:    out.ChecksumCRC32C,
+				ChecksumCRC64NVME: out.ChecksumCRC64NVME,
+				ChecksumSHA1:      out.ChecksumSHA1,
+				ChecksumSHA256:    out.ChecksumSHA256,
+			}, nil
+		}
+		lastErr = err
+	}
+	return s3types.CompletedPart{}, fmt.Errorf("s3 upload part %d: %w", part.number, lastErr)
+}
+
+// multipartSource returns a part iterator over FilePath or Reader, the total
+// size if known (-1 otherwise) and a cleanup function.
+// S3 requires at least one part, so an empty source yields a single empty part.
+func (r *S3Request) multipartSource() (func() (multipartPart, error), int64, func(), error) {
+	partSize := r.PartSize
+	if partSize <= 0 {
+		partSize = DefaultMultipartPartSize
+	}
+
+	if r.FilePath != "" {
+		f, err := os.Open(r.FilePath)
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("open upload source: %w", err)
+		}
+		info, err := f.Stat()
+		if err != nil {
+			_ = f.Close()
+			return nil, 0, nil, fmt.Errorf("stat upload source: %w", err)
+		}
+		total := info.Size()
+		if parts := (total + partSize - 1) / partSize; parts > MaxMultipartParts {
+			_ = f.Close()
+			return nil, 0, nil, fmt.Errorf("multipart upload of %d bytes needs %d parts, over the limit of %d: raise PartSize", total, parts, MaxMultipartParts)
+		}
+
+		var offset int64
+		var number int32
+		next := func() (multipartPart, error) {
+			if offset >= total && number > 0 {
+				return multipartPart{}, io.EOF
+			}
+			size := min(partSize, total-offset)
+			number++
+			part := multipartPart{
+				number: number,
+				body:   io.NewSectionReader(f, offset, size),
+				size:   size,
+			}
+			offset += size
+			return part, nil
+		}
+		return next, total, func() { _ = f.Close() }, nil
+	}
+
+	var number int32
+	next := func() (multipartPart, error) {
+		buf := make([]byte, partSize)
+		n, err := io.ReadFull(r.Reader, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return multipartPart{}, fmt.Errorf("read upload source: %w", err)
+		}
+		if n == 0 && number > 0 {
+			return multipartPart{}, io.EOF
+		}
+		if number >= MaxMultipartParts {
+			return multipartPart{}, fmt.Errorf("multipart upload source exceeds %d parts of %d bytes: raise PartSize", MaxMultipartParts, partSize)
+		}
+		number++
+		return multipartPart{
+			number: number,
+			body:   bytes.NewReader(buf[:n]),
+			size:   int64(n),
+		}, nil
+	}
+	return next, -1, func() {}, nil
+}
@@ -0,0 +1,258 @@
+package s3client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+type noWaitDelay struct{}
+
+func (noWaitDelay) Wait(taskName string, attempt int) {}
+
+type transferLog struct {
+	mu    sync.Mutex
+	notes []dto.TransferNotification
+}
+
+func (l *transferLog) publish(n dto.TransferNotification) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.notes = append(l.notes, n)
+}
+
+func (l *transferLog) last() dto.TransferNotification {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.notes) == 0 {
+		return dto.TransferNotification{}
+	}
+	return l.notes[len(l.notes)-1]
+}
+
+func TestS3Client_MultipartUpload_Golden(t *testing.T) {
+	payload := []byte(strings.Repeat("abcdefghij", 10)) // 100 bytes
+
+	filePath := filepath.Join(t.TempDir(), "payload.bin")
+	if err := os.WriteFile(filePath, payload, 0o644); err != nil {
+		t.Fatalf("write payload: %v", err)
+	}
+
+	// 10001 parts of the 10 byte test minimum
+	largeFile := filepath.Join(t.TempDir(), "large.bin")
+	if err := os.WriteFile(largeFile, bytes.Repeat([]byte("x"), 10*(MaxMultipartParts+1)), 0o644); err != nil {
+		t.Fatalf("write large payload: %v", err)
+	}
+
+	errBoom := errors.New("boom")
+
+	cases := []struct {
+		name    string
+		cfg     *S3RequestConfig
+		partErr func(partNumber int32, attempt int) error
+
+		wantErrSubstr string
+		wantBody      []byte
+		wantParts     int
+		wantAbort     bool
+		wantStatus    dto.TransferStatus
+		// wantPartCalls is the number of UploadPart attempts for part 1
+		wantPartCalls int
+	}{
+		{
+			name: "reader source is split into parts",
+			cfg: &S3RequestConfig{
+				Operation:   S3OpMultipartUpload,
+				Bucket:      "b",
+				Key:         "k",
+				Reader:      bytes.NewReader(payload),
+				PartSize:    30,
+				Concurrency: 2,
+			},
+			wantBody:   payload,
+			wantParts:  4,
+			wantStatus: dto.COMPLETE,
+		},
+		{
+			name: "file source is split into parts",
+			cfg: &S3RequestConfig{
+				Operation:   S3OpMultipartUpload,
+				Bucket:      "b",
+				Key:         "k",
+				FilePath:    filePath,
+				PartSize:    25,
+				Concurrency: 3,
+			},
+			wantBody:   payload,
+			wantParts:  4,
+			wantStatus: dto.COMPLETE,
+		},
+		{
+			name: "empty reader uploads a single empty part",
+			cfg: &S3RequestConfig{
+				Operation: S3OpMultipartUpload,
+				Bucket:    "b",
+				Key:       "k",
+				Reader:    bytes.NewReader(nil),
+			},
+			wantParts:  1,
+			wantStatus: dto.COMPLETE,
+		},
+		{
+			name: "failed part is retried",
+			cfg: &S3RequestConfig{
+				Operation:      S3OpMultipartUpload,
+				Bucket:         "b",
+				Key:            "k",
+				FilePath:       filePath,
+				PartSize:       50,
+				PartRetries:    aws.Int(2),
+				PartRetryDelay: noWaitDelay{},
+			},
+			partErr: func(partNumber int32, attempt int) error {
+				if partNumber == 2 && attempt < 3 {
+					return errBoom
+				}
+				return nil
+			},
+			wantBody:   payload,
+			wantParts:  2,
+			wantStatus: dto.COMPLETE,
+		},
+		{
+			name: "exhausted part retries abort the upload",
+			cfg: &S3RequestConfig{
+				Operation:      S3OpMultipartUpload,
+				Bucket:         "b",
+				Key:            "k",
+				Reader:         bytes.NewReader(payload),
+				PartSize:       50,
+				PartRetries:    aws.Int(1),
+				PartRetryDelay: noWaitDelay{},
+			},
+			partErr: func(partNumber int32, attempt int) error {
+				if partNumber == 1 {
+					return errBoom
+				}
+				return nil
+			},
+			wantErrSubstr: "s3 upload part 1: boom",
+			wantAbort:     true,
+			wantStatus:    dto.ERROR,
+		},
+		{
+			name: "zero part retries disables retrying",
+			cfg: &S3RequestConfig{
+				Operation:      S3OpMultipartUpload,
+				Bucket:         "b",
+				Key:            "k",
+				Reader:         bytes.NewReader(payload),
+				PartSize:       50,
+				PartRetries:    aws.Int(0),
+				PartRetryDelay: noWaitDelay{},
+			},
+			partErr: func(partNumber int32, attempt int) error {
+				if partNumber == 1 {
+					return errBoom
+				}
+				return nil
+			},
+			wantErrSubstr: "s3 upload part 1: boom",
+			wantAbort:     true,
+			wantStatus:    dto.ERROR,
+			wantPartCalls: 1,
+		},
+		{
+			name: "part size below the S3 minimum is rejected by Finalize",
+			cfg: &S3RequestConfig{
+				Operation: S3OpMultipartUpload,
+				Bucket:    "b",
+				Key:       "k",
+				Reader:    bytes.NewReader(payload),
+				PartSize:  5,
+			},
+			wantErrSubstr: "below the S3 minimum",
+		},
+		{
+			name: "file needing more than the part limit is rejected before upload",
+			cfg: &S3RequestConfig{
+				Operation: S3OpMultipartUpload,
+				Bucket:    "b",
+				Key:       "k",
+				FilePath:  largeFile,
+				PartSize:  10,
+			},
+			wantErrSubstr: "over the limit of 10000",
+			wantStatus:    dto.ERROR,
+		},
+		{
+			name: "missing source is rejected by Finalize",
+			cfg: &S3RequestConfig{
+				Operation: S3OpMultipartUpload,
+				Bucket:    "b",
+				Key:       "k",
+			},
+			wantErrSubstr: "multipart upload requires Reader or FilePath",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, f := newTestClient(t)
+			f.partErr = tc.partErr
+			log := &transferLog{}
+			c.SetTransferPublisher(log.publish)
+
+			_, err := c.ProcessRequest(context.Background(), mustReq(t, tc.cfg))
+
+			if tc.wantErrSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrSubstr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErrSubstr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.wantPartCalls > 0 && f.partCalls[1] != tc.wantPartCalls {
+				t.Fatalf("part 1 attempts=%d want %d", f.partCalls[1], tc.wantPartCalls)
+			}
+			if (len(f.gotAbort) > 0) != tc.wantAbort {
+				t.Fatalf("abort calls=%d wantAbort=%v", len(f.gotAbort), tc.wantAbort)
+			}
+			if tc.wantStatus != "" && log.last().Status != tc.wantStatus {
+				t.Fatalf("final transfer status=%s want %s", log.last().Status, tc.wantStatus)
+			}
+			if tc.wantParts == 0 {
+				return
+			}
+
+			if len(f.gotComplete) != 1 {
+				t.Fatalf("expected 1 CompleteMultipartUpload call, got %d", len(f.gotComplete))
+			}
+			parts := f.gotComplete[0].MultipartUpload.Parts
+			if len(parts) != tc.wantParts {
+				t.Fatalf("completed parts=%d want %d", len(parts), tc.wantParts)
+			}
+
+			// Parts must be listed in order and reassemble to the source.
+			var joined []byte
+			for i, p := range parts {
+				if aws.ToInt32(p.PartNumber) != int32(i+1) {
+					t.Fatalf("part %d out of order: %d", i, aws.ToInt32(p.PartNumber))
+				}
+				joined = append(joined, f.partBodies[int32(i+1)]...)
+			}
+			if !bytes.Equal(joined, tc.wantBody) {
+				t.Fatalf("reassembled body mismatch: got %d bytes want %d", len(joined), len(tc.wantBody))
+			}
+		})
+	}
+}
@@ -0,0 +1,412 @@
+package s3client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+type fakePresigner struct {
+	gotGet     *s3.GetObjectInput
+	gotPut     *s3.PutObjectInput
+	gotExpires time.Duration
+}
+
+func (p *fakePresigner) expiry(optFns []func(*s3.PresignOptions)) {
+	var o s3.PresignOptions
+	for _, fn := range optFns {
+		fn(&o)
+	}
+	p.gotExpires = o.Expires
+}
+
+func (p *fakePresigner) PresignGetObject(
+	ctx context.Context,
+	params *s3.GetObjectInput,
+	optFns ...func(*s3.PresignOptions),
+) (*v4.PresignedHTTPRequest, error) {
+	p.gotGet = params
+	p.expiry(optFns)
+	return &v4.PresignedHTTPRequest{
+		URL:    "https://example.test/" + aws.ToString(params.Key) + "?X-Amz-Signature=sig",
+		Method: http.MethodGet,
+	}, nil
+}
+
+func (p *fakePresigner) PresignPutObject(
+	ctx context.Context,
+	params *s3.PutObjectInput,
+	optFns ...func(*s3.PresignOptions),
+) (*v4.PresignedHTTPRequest, error) {
+	p.gotPut = params
+	p.expiry(optFns)
+	return &v4.PresignedHTTPRequest{
+		URL:          "https://example.test/" + aws.ToString(params.Key) + "?X-Amz-Signature=sig",
+		Method:       http.MethodPut,
+		SignedHeader: http.Header{"Content-Type": []string{aws.ToString(params.ContentType)}},
+	}, nil
+}
+
+func TestS3Client_Head_Golden(t *testing.T) {
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	cases := []struct {
+		name       string
+		headOut    *s3.HeadObjectOutput
+		headErr    error
+		wantStatus int
+		want       HeadResult
+		wantErr    string
+	}{
+		{
+			name: "existing object",
+			headOut: &s3.HeadObjectOutput{
+				ContentLength: aws.Int64(42),
+				ContentType:   aws.String("text/plain"),
+				ETag:          aws.String(`"abc"`),
+				LastModified:  &modified,
+				Metadata:      map[string]string{"owner": "me"},
+			},
+			wantStatus: http.StatusOK,
+			want: HeadResult{
+				Exists:        true,
+				ContentLength: 42,
+				ContentType:   "text/plain",
+				ETag:          `"abc"`,
+				LastModified:  &modified,
+				Metadata:      map[string]string{"owner": "me"},
+			},
+		},
+		{
+			name:       "missing object is not an error",
+			headErr:    &s3types.NotFound{},
+			wantStatus: http.StatusNotFound,
+			want:       HeadResult{},
+		},
+		{
+			name:    "other failures are returned",
+			headErr: fmt.Errorf("access denied"),
+			wantErr: "s3 head object: access denied",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, f := newTestClient(t)
+			f.headOut, f.headErr = tc.headOut, tc.headErr
+
+			resp, err := c.ProcessRequest(context.Background(), mustReq(t, &S3RequestConfig{
+				Operation: S3OpHead,
+				Bucket:    "b",
+				Key:       "k",
+				VersionId: "v1",
+			}))
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("err=%v want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessRequest error: %v", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("status=%d want %d", resp.StatusCode, tc.wantStatus)
+			}
+			if aws.ToString(f.gotHead[0].VersionId) != "v1" {
+				t.Fatalf("VersionId not forwarded")
+			}
+
+			var got HeadResult
+			if err := json.Unmarshal(resp.Body, &got); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("result mismatch:\n got=%+v\nwant=%+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestS3Client_Copy_Golden(t *testing.T) {
+	cases := []struct {
+		name          string
+		cfg           *S3RequestConfig
+		wantSource    string
+		wantDirective s3types.MetadataDirective
+		// wantSourceKey is the base64 SSE-C key sent for the copy source
+		wantSourceKey string
+		wantErr       string
+	}{
+		{
+			name: "same bucket copy escapes key segments",
+			cfg: &S3RequestConfig{
+				Operation: S3OpCopy,
+				Bucket:    "b",
+				Key:       "dst/new.txt",
+				SourceKey: "src/a file+1.txt",
+			},
+			wantSource: "b/src/a%20file+1.txt",
+		},
+		{
+			name: "cross bucket versioned copy with replaced metadata",
+			cfg: &S3RequestConfig{
+				Operation:       S3OpCopy,
+				Bucket:          "dst",
+				Key:             "k",
+				SourceBucket:    "src",
+				SourceKey:       "k",
+				SourceVersionId: "v1",
+				ContentType:     "application/json",
+				ExtraOpts:       map[string]any{"metadata": map[string]string{"a": "b"}},
+			},
+			wantSource:    "src/k?versionId=v1",
+			wantDirective: s3types.MetadataDirectiveReplace,
+		},
+		{
+			name: "sse-c source is decrypted with its own key",
+			cfg: &S3RequestConfig{
+				Operation:            S3OpCopy,
+				Bucket:               "b",
+				Key:                  "dst",
+				SourceKey:            "src",
+				SourceSSECustomerKey: []byte("0123456789abcdef0123456789abcdef"),
+				SSECustomerKey:       []byte("fedcba9876543210fedcba9876543210"),
+			},
+			wantSource:    "b/src",
+			wantSourceKey: base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")),
+		},
+		{
+			name:    "missing source key",
+			cfg:     &S3RequestConfig{Operation: S3OpCopy, Bucket: "b", Key: "k"},
+			wantErr: "s3 copy requires SourceKey",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, f := newTestClient(t)
+			f.copyOut = &s3.CopyObjectOutput{
+				CopyObjectResult: &s3types.CopyObjectResult{ETag: aws.String(`"e"`)},
+			}
+
+			resp, err := c.ProcessRequest(context.Background(), mustReq(t, tc.cfg))
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("err=%v want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessRequest error: %v", err)
+			}
+
+			in := f.gotCopy[0]
+			if aws.ToString(in.CopySource) != tc.wantSource {
+				t.Fatalf("CopySource=%q want %q", aws.ToString(in.CopySource), tc.wantSource)
+			}
+			if in.MetadataDirective != tc.wantDirective {
+				t.Fatalf("MetadataDirective=%q want %q", in.MetadataDirective, tc.wantDirective)
+			}
+			if got := aws.ToString(in.CopySourceSSECustomerKey); got != tc.wantSourceKey {
+				t.Fatalf("CopySourceSSECustomerKey=%q want %q", got, tc.wantSourceKey)
+			}
+			if tc.wantSourceKey != "" && (aws.ToString(in.CopySourceSSECustomerAlgorithm) != "AES256" || in.CopySourceSSECustomerKeyMD5 == nil) {
+				t.Fatalf("source sse-c algorithm=%q md5=%v", aws.ToString(in.CopySourceSSECustomerAlgorithm), in.CopySourceSSECustomerKeyMD5)
+			}
+			if tc.wantSourceKey != "" && aws.ToString(in.SSECustomerKey) == tc.wantSourceKey {
+				t.Fatalf("destination reused the source sse-c key")
+			}
+
+			var got CopyResult
+			if err := json.Unmarshal(resp.Body, &got); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if got.ETag != `"e"` || resp.Headers.Get("ETag") != `"e"` {
+				t.Fatalf("unexpected ETag: result=%q header=%q", got.ETag, resp.Headers.Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestS3Client_DeleteMany_Golden(t *testing.T) {
+	manyKeys := make([]string, MaxDeleteManyBatch+5)
+	for i := range manyKeys {
+		manyKeys[i] = fmt.Sprintf("k%04d", i)
+	}
+
+	cases := []struct {
+		name         string
+		keys         []string
+		failKey      string
+		wantBatches  int
+		wantDeleted  int
+		wantStatus   int
+		wantErrorKey string
+		wantErr      string
+	}{
+		{
+			name:        "keys are batched by the S3 limit",
+			keys:        manyKeys,
+			wantBatches: 2,
+			wantDeleted: len(manyKeys),
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:         "per key failures are reported",
+			keys:         []string{"a", "locked", "c"},
+			failKey:      "locked",
+			wantBatches:  1,
+			wantDeleted:  2,
+			wantStatus:   http.StatusMultiStatus,
+			wantErrorKey: "locked",
+		},
+		{
+			name:    "no keys",
+			wantErr: "s3 delete_many requires Keys",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, f := newTestClient(t)
+			f.deleteManyFn = func(in *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+				out := &s3.DeleteObjectsOutput{}
+				for _, obj := range in.Delete.Objects {
+					if aws.ToString(obj.Key) == tc.failKey {
+						out.Errors = append(out.Errors, s3types.Error{
+							Key:     obj.Key,
+							Code:    aws.String("AccessDenied"),
+							Message: aws.String("Access Denied"),
+						})
+						continue
+					}
+					out.Deleted = append(out.Deleted, s3types.DeletedObject{Key: obj.Key})
+				}
+				return out, nil
+			}
+
+			resp, err := c.ProcessRequest(context.Background(), mustReq(t, &S3RequestConfig{
+				Operation: S3OpDeleteMany,
+				Bucket:    "b",
+				Keys:      tc.keys,
+			}))
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("err=%v want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessRequest error: %v", err)
+			}
+			if len(f.gotDeleteMany) != tc.wantBatches {
+				t.Fatalf("DeleteObjects calls=%d want %d", len(f.gotDeleteMany), tc.wantBatches)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("status=%d want %d", resp.StatusCode, tc.wantStatus)
+			}
+
+			var got DeleteManyResult
+			if err := json.Unmarshal(resp.Body, &got); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if len(got.Deleted) != tc.wantDeleted {
+				t.Fatalf("deleted=%d want %d", len(got.Deleted), tc.wantDeleted)
+			}
+			if tc.wantErrorKey != "" && (len(got.Errors) != 1 || got.Errors[0].Key != tc.wantErrorKey) {
+				t.Fatalf("unexpected errors: %+v", got.Errors)
+			}
+		})
+	}
+}
+
+func TestS3Client_Presign_Golden(t *testing.T) {
+	cases := []struct {
+		name        string
+		cfg         *S3RequestConfig
+		wantMethod  string
+		wantExpires time.Duration
+		wantErr     string
+	}{
+		{
+			name:        "get uses default expiry",
+			cfg:         &S3RequestConfig{Operation: S3OpPresignGet, Bucket: "b", Key: "k", VersionId: "v1"},
+			wantMethod:  http.MethodGet,
+			wantExpires: DefaultPresignExpiry,
+		},
+		{
+			name: "put signs content type without a body",
+			cfg: &S3RequestConfig{
+				Operation:   S3OpPresignPut,
+				Bucket:      "b",
+				Key:         "k",
+				ContentType: "image/png",
+				Body:        []byte("ignored"),
+				Expires:     time.Hour,
+			},
+			wantMethod:  http.MethodPut,
+			wantExpires: time.Hour,
+		},
+		{
+			name:    "expiry over seven days is rejected",
+			cfg:     &S3RequestConfig{Operation: S3OpPresignGet, Bucket: "b", Key: "k", Expires: 8 * 24 * time.Hour},
+			wantErr: "exceeds the SigV4 maximum",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := newTestClient(t)
+			p := &fakePresigner{}
+			c.presigner = p
+
+			resp, err := c.ProcessRequest(context.Background(), mustReq(t, tc.cfg))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("err=%v want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessRequest error: %v", err)
+			}
+
+			var got PresignResult
+			if err := json.Unmarshal(resp.Body, &got); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if got.Method != tc.wantMethod || !strings.HasPrefix(got.URL, "https://example.test/k?") {
+				t.Fatalf("unexpected result: %+v", got)
+			}
+			if p.gotExpires != tc.wantExpires {
+				t.Fatalf("expires=%s want %s", p.gotExpires, tc.wantExpires)
+			}
+			if time.Until(got.ExpiresAt) > tc.wantExpires || time.Until(got.ExpiresAt) < tc.wantExpires-time.Minute {
+				t.Fatalf("ExpiresAt %s not ~%s from now", got.ExpiresAt, tc.wantExpires)
+			}
+
+			switch tc.wantMethod {
+			case http.MethodGet:
+				if aws.ToString(p.gotGet.VersionId) != "v1" {
+					t.Fatalf("VersionId not signed")
+				}
+			case http.MethodPut:
+				if p.gotPut.Body != nil || got.SignedHeaders.Get("Content-Type") != "image/png" {
+					t.Fatalf("unexpected put input: body=%v headers=%v", p.gotPut.Body, got.SignedHeaders)
+				}
+			}
+		})
+	}
+}
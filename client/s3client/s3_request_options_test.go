@@ -0,0 +1,168 @@
+package s3client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestS3Request_Finalize_ObjectOptions_Golden(t *testing.T) {
+	sseCKey := []byte("0123456789abcdef0123456789abcdef")
+
+	cases := []struct {
+		name    string
+		req     *S3Request
+		check   func(t *testing.T, r *S3Request)
+		wantErr string
+	}{
+		{
+			name: "put maps kms, storage, encoding, acl and checksum",
+			req: &S3Request{
+				Operation:               S3OpPut,
+				Bucket:                  "b",
+				Key:                     "k",
+				ServerSideEncryption:    s3types.ServerSideEncryptionAwsKms,
+				SSEKMSKeyId:             "alias/app",
+				SSEKMSEncryptionContext: map[string]string{"tenant": "t1"},
+				StorageClass:            s3types.StorageClassStandardIa,
+				ContentEncoding:         "gzip",
+				ContentDisposition:      `attachment; filename="a.csv"`,
+				ACL:                     s3types.ObjectCannedACLBucketOwnerFullControl,
+				ChecksumAlgorithm:       s3types.ChecksumAlgorithmSha256,
+			},
+			check: func(t *testing.T, r *S3Request) {
+				in := r.PutInput
+				ctx, _ := base64.StdEncoding.DecodeString(aws.ToString(in.SSEKMSEncryptionContext))
+				if in.ServerSideEncryption != s3types.ServerSideEncryptionAwsKms ||
+					aws.ToString(in.SSEKMSKeyId) != "alias/app" ||
+					string(ctx) != `{"tenant":"t1"}` {
+					t.Fatalf("unexpected kms fields: %v %v %s", in.ServerSideEncryption, aws.ToString(in.SSEKMSKeyId), ctx)
+				}
+				if in.StorageClass != s3types.StorageClassStandardIa ||
+					in.ACL != s3types.ObjectCannedACLBucketOwnerFullControl ||
+					in.ChecksumAlgorithm != s3types.ChecksumAlgorithmSha256 ||
+					aws.ToString(in.ContentEncoding) != "gzip" ||
+					aws.ToString(in.ContentDisposition) != `attachment; filename="a.csv"` {
+					t.Fatalf("unexpected object fields: %+v", in)
+				}
+			},
+		},
+		{
+			name: "put maps SSE-C",
+			req: &S3Request{
+				Operation:      S3OpPut,
+				Bucket:         "b",
+				Key:            "k",
+				SSECustomerKey: sseCKey,
+			},
+			check: func(t *testing.T, r *S3Request) {
+				in := r.PutInput
+				if aws.ToString(in.SSECustomerAlgorithm) != "AES256" || aws.ToString(in.SSECustomerKeyMD5) != "hRasmdxgYDKV3nvbahU1MA==" {
+					t.Fatalf("unexpected SSE-C fields: %v %v", aws.ToString(in.SSECustomerAlgorithm), aws.ToString(in.SSECustomerKeyMD5))
+				}
+			},
+		},
+		{
+			name: "copy maps storage class and sse",
+			req: &S3Request{
+				Operation:            S3OpCopy,
+				Bucket:               "b",
+				Key:                  "k",
+				SourceKey:            "src",
+				StorageClass:         s3types.StorageClassGlacierIr,
+				ServerSideEncryption: s3types.ServerSideEncryptionAes256,
+			},
+			check: func(t *testing.T, r *S3Request) {
+				if r.CopyInput.StorageClass != s3types.StorageClassGlacierIr ||
+					r.CopyInput.ServerSideEncryption != s3types.ServerSideEncryptionAes256 {
+					t.Fatalf("unexpected copy fields: %+v", r.CopyInput)
+				}
+			},
+		},
+		{
+			name: "SSE-C and SSE are exclusive",
+			req: &S3Request{
+				Operation:            S3OpPut,
+				SSECustomerKey:       sseCKey,
+				ServerSideEncryption: s3types.ServerSideEncryptionAes256,
+			},
+			wantErr: "s3 put: SSECustomerKey and ServerSideEncryption are mutually exclusive",
+		},
+		{
+			name: "kms key requires kms encryption",
+			req: &S3Request{
+				Operation:   S3OpMultipartUpload,
+				FilePath:    "/tmp/x",
+				SSEKMSKeyId: "alias/app",
+			},
+			wantErr: "s3 multipart_upload: SSEKMSKeyId and SSEKMSEncryptionContext require aws:kms encryption",
+		},
+		{
+			name: "unknown ExtraOpts keys are sorted in the error",
+			req: &S3Request{
+				Operation: S3OpPut,
+				ExtraOpts: map[string]any{
+					"metadata":     map[string]string{},
+					"storageclass": "GLACIER",
+					"acl":          "private",
+				},
+			},
+			wantErr: "s3 put: unknown ExtraOpts keys: acl, storageclass",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.req.Finalize()
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("err=%v want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Finalize error: %v", err)
+			}
+			tc.check(t, tc.req)
+		})
+	}
+}
+
+func TestS3Client_MultipartUpload_ChecksumAndSSEC_Golden(t *testing.T) {
+	c, f := newTestClient(t)
+
+	_, err := c.ProcessRequest(context.Background(), mustReq(t, &S3RequestConfig{
+		Operation:         S3OpMultipartUpload,
+		Bucket:            "b",
+		Key:               "k",
+		Reader:            bytes.NewReader(bytes.Repeat([]byte("x"), 25)),
+		PartSize:          10,
+		ChecksumAlgorithm: s3types.ChecksumAlgorithmCrc32c,
+		SSECustomerKey:    []byte("0123456789abcdef0123456789abcdef"),
+	}))
+	if err != nil {
+		t.Fatalf("ProcessRequest error: %v", err)
+	}
+
+	if got := f.gotCreate[0].ChecksumAlgorithm; got != s3types.ChecksumAlgorithmCrc32c {
+		t.Fatalf("create ChecksumAlgorithm=%q", got)
+	}
+	for _, in := range f.gotParts {
+		if in.ChecksumAlgorithm != s3types.ChecksumAlgorithmCrc32c || in.SSECustomerKey == nil {
+			t.Fatalf("part %d missing checksum or SSE-C: %+v", aws.ToInt32(in.PartNumber), in)
+		}
+	}
+	complete := f.gotComplete[0]
+	if complete.SSECustomerKey == nil {
+		t.Fatalf("complete missing SSE-C key")
+	}
+	for _, p := range complete.MultipartUpload.Parts {
+		if aws.ToString(p.ChecksumCRC32C) == "" {
+			t.Fatalf("part %d missing CRC32C in completion", aws.ToInt32(p.PartNumber))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package s3client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// PresignResult is the JSON body returned by the presign operations.
+// SignedHeaders must be sent unchanged by whoever uses the URL.
+type PresignResult struct {
+	URL           string      `json:"url"`
+	Method        string      `json:"method"`
+	SignedHeaders http.Header `json:"signed_headers,omitempty"`
+	ExpiresAt     time.Time   `json:"expires_at"`
+}
+
+// doPresign signs a GET or PUT URL valid for Expires without contacting S3.
+func (c *S3Client) doPresign(ctx context.Context, r *S3Request) (dto.Response, error) {
+	if c.presigner == nil {
+		return dto.Response{}, fmt.Errorf("s3 presign: client has no presigner")
+	}
+	expires := r.Expires
+	if expires <= 0 {
+		expires = DefaultPresignExpiry
+	}
+	withExpiry := s3.WithPresignExpires(expires)
+
+	var (
+		signed *v4.PresignedHTTPRequest
+		err    error
+	)
+	if r.Operation == S3OpPresignPut {
+		signed, err = c.presigner.PresignPutObject(ctx, r.PutInput, withExpiry)
+	} else {
+		signed, err = c.presigner.PresignGetObject(ctx, r.GetInput, withExpiry)
+	}
+	if err != nil {
+		return dto.Response{}, fmt.Errorf("s3 %s: %w", r.Operation, err)
+	}
+
+	body, err := json.Marshal(PresignResult{
+		URL:           signed.URL,
+		Method:        signed.Method,
+		SignedHeaders: signed.SignedHeader,
+		ExpiresAt:     time.Now().Add(expires).UTC(),
+	})
+	if err != nil {
+		return dto.Response{}, fmt.Errorf("encode presign result: %w", err)
+	}
+	return dto.Response{StatusCode: http.StatusOK, Body: body}, nil
+}
@@ -3,14 +3,24 @@ package s3client
 import (
 	"context"
 	"fmt"
+	"net/http"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/joy-dx/gonetic/dto"
 )
 
 func (c *S3Client) doPut(ctx context.Context, r *S3Request) (dto.Response, error) {
-	_, err := c.client.PutObject(ctx, r.PutInput)
+	out, err := c.client.PutObject(ctx, r.PutInput)
 	if err != nil {
 		return dto.Response{}, fmt.Errorf("s3 put object: %w", err)
 	}
-	return dto.Response{StatusCode: 200}, nil
+
+	headers := make(http.Header)
+	if out.ETag != nil {
+		headers.Set("ETag", aws.ToString(out.ETag))
+	}
+	if out.VersionId != nil {
+		headers.Set("X-Amz-Version-Id", aws.ToString(out.VersionId))
+	}
+	return dto.Response{StatusCode: 200, Headers: headers}, nil
 }
@@ -0,0 +1,53 @@
+package s3client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// TaggingResult is the JSON body returned by the tagging operations.
+type TaggingResult struct {
+	VersionId string            `json:"version_id,omitempty"`
+	Tags      map[string]string `json:"tags"`
+}
+
+func (c *S3Client) doGetTagging(ctx context.Context, r *S3Request) (dto.Response, error) {
+	out, err := c.client.GetObjectTagging(ctx, r.GetTaggingInput)
+	if err != nil {
+		return dto.Response{}, fmt.Errorf("s3 get object tagging: %w", err)
+	}
+
+	result := TaggingResult{
+		VersionId: aws.ToString(out.VersionId),
+		Tags:      make(map[string]string, len(out.TagSet)),
+	}
+	for _, tag := range out.TagSet {
+		result.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return taggingResponse(result)
+}
+
+// doPutTagging replaces the full tag set of the object (version).
+func (c *S3Client) doPutTagging(ctx context.Context, r *S3Request) (dto.Response, error) {
+	out, err := c.client.PutObjectTagging(ctx, r.PutTaggingInput)
+	if err != nil {
+		return dto.Response{}, fmt.Errorf("s3 put object tagging: %w", err)
+	}
+	return taggingResponse(TaggingResult{
+		VersionId: aws.ToString(out.VersionId),
+		Tags:      r.Tags,
+	})
+}
+
+func taggingResponse(result TaggingResult) (dto.Response, error) {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return dto.Response{}, fmt.Errorf("encode tagging result: %w", err)
+	}
+	return dto.Response{StatusCode: http.StatusOK, Body: body}, nil
+}
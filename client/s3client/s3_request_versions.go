@@ -0,0 +1,71 @@
+package s3client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// ListVersionsResult is the JSON body returned by the list_versions operation.
+type ListVersionsResult struct {
+	Versions []ObjectVersion `json:"versions"`
+}
+
+// ObjectVersion is one object version or delete marker.
+type ObjectVersion struct {
+	Key            string     `json:"key"`
+	VersionId      string     `json:"version_id"`
+	IsLatest       bool       `json:"is_latest"`
+	IsDeleteMarker bool       `json:"is_delete_marker,omitempty"`
+	Size           int64      `json:"size,omitempty"`
+	ETag           string     `json:"etag,omitempty"`
+	LastModified   *time.Time `json:"last_modified,omitempty"`
+}
+
+// doListVersions lists every version and delete marker under Prefix,
+// following pagination until the listing is complete.
+func (c *S3Client) doListVersions(ctx context.Context, r *S3Request) (dto.Response, error) {
+	result := ListVersionsResult{Versions: []ObjectVersion{}}
+	in := *r.ListVersionsInput
+
+	for {
+		out, err := c.client.ListObjectVersions(ctx, &in)
+		if err != nil {
+			return dto.Response{}, fmt.Errorf("s3 list object versions: %w", err)
+		}
+		for _, v := range out.Versions {
+			result.Versions = append(result.Versions, ObjectVersion{
+				Key:          aws.ToString(v.Key),
+				VersionId:    aws.ToString(v.VersionId),
+				IsLatest:     aws.ToBool(v.IsLatest),
+				Size:         aws.ToInt64(v.Size),
+				ETag:         aws.ToString(v.ETag),
+				LastModified: v.LastModified,
+			})
+		}
+		for _, m := range out.DeleteMarkers {
+			result.Versions = append(result.Versions, ObjectVersion{
+				Key:            aws.ToString(m.Key),
+				VersionId:      aws.ToString(m.VersionId),
+				IsLatest:       aws.ToBool(m.IsLatest),
+				IsDeleteMarker: true,
+				LastModified:   m.LastModified,
+			})
+		}
+		if !aws.ToBool(out.IsTruncated) {
+			break
+		}
+		in.KeyMarker, in.VersionIdMarker = out.NextKeyMarker, out.NextVersionIdMarker
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return dto.Response{}, fmt.Errorf("encode versions result: %w", err)
+	}
+	return dto.Response{StatusCode: http.StatusOK, Body: body}, nil
+}
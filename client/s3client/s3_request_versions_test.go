@@ -0,0 +1,229 @@
+package s3client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestS3Client_ListVersions_Golden(t *testing.T) {
+	c, f := newTestClient(t)
+	f.versionPages = []*s3.ListObjectVersionsOutput{
+		{
+			Versions: []s3types.ObjectVersion{
+				{Key: aws.String("a"), VersionId: aws.String("v2"), IsLatest: aws.Bool(true), Size: aws.Int64(3)},
+			},
+			IsTruncated:         aws.Bool(true),
+			NextKeyMarker:       aws.String("a"),
+			NextVersionIdMarker: aws.String("v2"),
+		},
+		{
+			Versions: []s3types.ObjectVersion{
+				{Key: aws.String("a"), VersionId: aws.String("v1"), Size: aws.Int64(2)},
+			},
+			DeleteMarkers: []s3types.DeleteMarkerEntry{
+				{Key: aws.String("b"), VersionId: aws.String("d1"), IsLatest: aws.Bool(true)},
+			},
+		},
+	}
+
+	resp, err := c.ProcessRequest(context.Background(), mustReq(t, &S3RequestConfig{
+		Operation: S3OpListVersions,
+		Bucket:    "b",
+		Prefix:    "p/",
+	}))
+	if err != nil {
+		t.Fatalf("ProcessRequest error: %v", err)
+	}
+
+	if len(f.gotVersions) != 2 {
+		t.Fatalf("ListObjectVersions calls=%d want 2", len(f.gotVersions))
+	}
+	second := f.gotVersions[1]
+	if aws.ToString(second.KeyMarker) != "a" || aws.ToString(second.VersionIdMarker) != "v2" || aws.ToString(second.Prefix) != "p/" {
+		t.Fatalf("pagination markers not forwarded: %+v", second)
+	}
+
+	var got ListVersionsResult
+	if err := json.Unmarshal(resp.Body, &got); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	want := []ObjectVersion{
+		{Key: "a", VersionId: "v2", IsLatest: true, Size: 3},
+		{Key: "a", VersionId: "v1", Size: 2},
+		{Key: "b", VersionId: "d1", IsLatest: true, IsDeleteMarker: true},
+	}
+	if !reflect.DeepEqual(got.Versions, want) {
+		t.Fatalf("versions mismatch:\n got=%+v\nwant=%+v", got.Versions, want)
+	}
+}
+
+func TestS3Client_Tagging_Golden(t *testing.T) {
+	t.Run("get returns tags as a map", func(t *testing.T) {
+		c, f := newTestClient(t)
+		f.taggingOut = &s3.GetObjectTaggingOutput{
+			VersionId: aws.String("v1"),
+			TagSet:    []s3types.Tag{{Key: aws.String("env"), Value: aws.String("prod")}},
+		}
+
+		resp, err := c.ProcessRequest(context.Background(), mustReq(t, &S3RequestConfig{
+			Operation: S3OpGetTagging,
+			Bucket:    "b",
+			Key:       "k",
+		}))
+		if err != nil {
+			t.Fatalf("ProcessRequest error: %v", err)
+		}
+		var got TaggingResult
+		if err := json.Unmarshal(resp.Body, &got); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		want := TaggingResult{VersionId: "v1", Tags: map[string]string{"env": "prod"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got=%+v want=%+v", got, want)
+		}
+	})
+
+	t.Run("put sends a sorted tag set for the version", func(t *testing.T) {
+		c, f := newTestClient(t)
+
+		_, err := c.ProcessRequest(context.Background(), mustReq(t, &S3RequestConfig{
+			Operation: S3OpPutTagging,
+			Bucket:    "b",
+			Key:       "k",
+			VersionId: "v1",
+			Tags:      map[string]string{"team": "core", "env": "prod"},
+		}))
+		if err != nil {
+			t.Fatalf("ProcessRequest error: %v", err)
+		}
+		in := f.gotTagging[0]
+		if aws.ToString(in.VersionId) != "v1" {
+			t.Fatalf("VersionId=%q", aws.ToString(in.VersionId))
+		}
+		var keys []string
+		for _, tag := range in.Tagging.TagSet {
+			keys = append(keys, aws.ToString(tag.Key))
+		}
+		if strings.Join(keys, ",") != "env,team" {
+			t.Fatalf("tag order=%v", keys)
+		}
+	})
+}
+
+func TestS3Request_Finalize_VersioningAndLock_Golden(t *testing.T) {
+	retain := time.Date(2030, 1, 1, 0, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	cases := []struct {
+		name    string
+		req     *S3Request
+		check   func(t *testing.T, r *S3Request)
+		wantErr string
+	}{
+		{
+			name: "put carries tags and object lock",
+			req: &S3Request{
+				Operation:             S3OpPut,
+				Bucket:                "b",
+				Key:                   "k",
+				Tags:                  map[string]string{"b": "2", "a": "1 x"},
+				ObjectLockMode:        s3types.ObjectLockModeCompliance,
+				ObjectLockRetainUntil: retain,
+				LegalHold:             true,
+			},
+			check: func(t *testing.T, r *S3Request) {
+				in := r.PutInput
+				if aws.ToString(in.Tagging) != "a=1+x&b=2" {
+					t.Fatalf("Tagging=%q", aws.ToString(in.Tagging))
+				}
+				if in.ObjectLockMode != s3types.ObjectLockModeCompliance ||
+					!in.ObjectLockRetainUntilDate.Equal(retain) ||
+					in.ObjectLockRetainUntilDate.Location() != time.UTC ||
+					in.ObjectLockLegalHoldStatus != s3types.ObjectLockLegalHoldStatusOn {
+					t.Fatalf("unexpected lock fields: %v %v %v", in.ObjectLockMode, in.ObjectLockRetainUntilDate, in.ObjectLockLegalHoldStatus)
+				}
+			},
+		},
+		{
+			name: "multipart carries object lock",
+			req: &S3Request{
+				Operation:             S3OpMultipartUpload,
+				Bucket:                "b",
+				Key:                   "k",
+				FilePath:              "/tmp/x",
+				ObjectLockMode:        s3types.ObjectLockModeGovernance,
+				ObjectLockRetainUntil: retain,
+			},
+			check: func(t *testing.T, r *S3Request) {
+				if r.CreateMultipartInput.ObjectLockMode != s3types.ObjectLockModeGovernance {
+					t.Fatalf("ObjectLockMode=%q", r.CreateMultipartInput.ObjectLockMode)
+				}
+			},
+		},
+		{
+			name: "lock mode without retain until",
+			req: &S3Request{
+				Operation:      S3OpPut,
+				ObjectLockMode: s3types.ObjectLockModeGovernance,
+			},
+			wantErr: "s3 put: ObjectLockMode and ObjectLockRetainUntil must be set together",
+		},
+		{
+			name: "delete addresses a version",
+			req:  &S3Request{Operation: S3OpDelete, Bucket: "b", Key: "k", VersionId: "v1"},
+			check: func(t *testing.T, r *S3Request) {
+				if aws.ToString(r.DeleteInput.VersionId) != "v1" {
+					t.Fatalf("VersionId=%q", aws.ToString(r.DeleteInput.VersionId))
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.req.Finalize()
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("err=%v want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Finalize error: %v", err)
+			}
+			tc.check(t, tc.req)
+		})
+	}
+}
+
+func TestS3Client_PutDelete_VersionHeaders_Golden(t *testing.T) {
+	c, f := newTestClient(t)
+	f.putOut = &s3.PutObjectOutput{ETag: aws.String(`"e"`), VersionId: aws.String("v3")}
+	f.delOut = &s3.DeleteObjectOutput{VersionId: aws.String("d1"), DeleteMarker: aws.Bool(true)}
+
+	put, err := c.ProcessRequest(context.Background(), mustReq(t, &S3RequestConfig{Operation: S3OpPut, Bucket: "b", Key: "k"}))
+	if err != nil {
+		t.Fatalf("put error: %v", err)
+	}
+	del, err := c.ProcessRequest(context.Background(), mustReq(t, &S3RequestConfig{Operation: S3OpDelete, Bucket: "b", Key: "k"}))
+	if err != nil {
+		t.Fatalf("delete error: %v", err)
+	}
+
+	want := map[string]http.Header{
+		"put":    {"Etag": {`"e"`}, "X-Amz-Version-Id": {"v3"}},
+		"delete": {"X-Amz-Version-Id": {"d1"}, "X-Amz-Delete-Marker": {"true"}},
+	}
+	got := map[string]http.Header{"put": put.Headers, "delete": del.Headers}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("headers mismatch:\n got=%v\nwant=%v", got, want)
+	}
+}
@@ -0,0 +1,559 @@
+package s3client
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
+)
+
+// SyncDirection selects which side of a sync is the source of truth.
+type SyncDirection string
+
+const (
+	SyncUpload   SyncDirection = "upload"
+	SyncDownload SyncDirection = "download"
+)
+
+// SyncAction is a single planned change.
+type SyncAction string
+
+const (
+	SyncActionUpload   SyncAction = "upload"
+	SyncActionDownload SyncAction = "download"
+	SyncActionDelete   SyncAction = "delete"
+)
+
+// S3SyncConfig describes a mirror between LocalDir and Bucket/Prefix.
+type S3SyncConfig struct {
+	Direction SyncDirection
+	LocalDir  string
+	Bucket    string
+	Prefix    string
+
+	// Delete removes files on the destination that do not exist on the source.
+	Delete bool
+	// Include and Exclude are path.Match globs evaluated against the slash
+	// separated path relative to LocalDir/Prefix. Patterns without a slash also
+	// match the base name. When Include is set only matching paths are synced;
+	// Exclude always wins.
+	Include []string
+	Exclude []string
+
+	// DryRun returns the plan without transferring or deleting anything.
+	DryRun bool
+	// Concurrency bounds the number of files transferred at once.
+	Concurrency int
+
+	// ChecksumMetaKey stores the hex SHA-256 of uploaded files in this object
+	// metadata key and compares against it instead of the ETag. Use it when
+	// ETags are not MD5 based, e.g. with SSE-KMS.
+	ChecksumMetaKey string
+	// PartSize is the multipart threshold and part size for uploads and the
+	// chunk size for downloads. It must match between runs for multipart ETags
+	// to compare equal.
+	PartSize int64
+}
+
+func DefaultS3SyncConfig() S3SyncConfig {
+	return S3SyncConfig{
+		Direction:   SyncUpload,
+		Concurrency: DefaultMultipartConcurrency,
+		PartSize:    DefaultMultipartPartSize,
+	}
+}
+
+// SyncItem is one planned action. Path is the local file, Key the object key.
+type SyncItem struct {
+	Action SyncAction `json:"action"`
+	Key    string     `json:"key"`
+	Path   string     `json:"path"`
+	Size   int64      `json:"size"`
+	Reason string     `json:"reason"`
+}
+
+// SyncPlan lists the actions a sync performs, sorted by key.
+type SyncPlan struct {
+	Items     []SyncItem `json:"items"`
+	Unchanged int        `json:"unchanged"`
+}
+
+type syncLocalFile struct {
+	path string
+	size int64
+}
+
+// Sync plans and, unless DryRun is set, applies a one-way mirror between a
+// local directory and a bucket prefix. Transfers go through ProcessRequest so
+// middleware and transfer notifications apply. Deletes run after all
+// transfers. Failed items do not stop the others; their errors are joined.
+func (c *S3Client) Sync(ctx context.Context, cfg *S3SyncConfig) (SyncPlan, error) {
+	if err := cfg.validate(); err != nil {
+		return SyncPlan{}, err
+	}
+
+	remote, err := c.syncRemoteObjects(ctx, cfg)
+	if err != nil {
+		return SyncPlan{}, err
+	}
+	local, err := cfg.localFiles()
+	if err != nil {
+		return SyncPlan{}, err
+	}
+
+	plan, err := c.planSync(ctx, cfg, local, remote)
+	if err != nil || cfg.DryRun {
+		return plan, err
+	}
+	return plan, c.applySync(ctx, cfg, plan)
+}
+
+func (cfg *S3SyncConfig) validate() error {
+	if cfg.Direction != SyncUpload && cfg.Direction != SyncDownload {
+		return fmt.Errorf("s3 sync: unknown direction %q", cfg.Direction)
+	}
+	if cfg.LocalDir == "" || cfg.Bucket == "" {
+		return fmt.Errorf("s3 sync: LocalDir and Bucket are required")
+	}
+	for _, p := range append(append([]string{}, cfg.Include...), cfg.Exclude...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("s3 sync: invalid pattern %q: %w", p, err)
+		}
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultMultipartConcurrency
+	}
+	if err := validatePartSize(cfg.PartSize, MinMultipartPartSize); err != nil {
+		return fmt.Errorf("s3 sync: %w", err)
+	}
+	if cfg.PartSize == 0 {
+		cfg.PartSize = DefaultMultipartPartSize
+	}
+	return nil
+}
+
+func (cfg *S3SyncConfig) selected(rel string) bool {
+	if len(cfg.Include) > 0 && !matchAny(cfg.Include, rel) {
+		return false
+	}
+	return !matchAny(cfg.Exclude, rel)
+}
+
+func matchAny(patterns []string, rel string) bool {
+	base := path.Base(rel)
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, rel); ok {
+			return true
+		}
+		if !strings.Contains(p, "/") {
+			if ok, _ := path.Match(p, base); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (cfg *S3SyncConfig) key(rel string) string {
+	prefix := strings.TrimSuffix(cfg.Prefix, "/")
+	if prefix == "" {
+		return rel
+	}
+	return prefix + "/" + rel
+}
+
+// syncRemoteObjects lists the prefix keyed by path relative to it.
+func (c *S3Client) syncRemoteObjects(ctx context.Context, cfg *S3SyncConfig) (map[string]s3types.Object, error) {
+	prefix := strings.TrimSuffix(cfg.Prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	objects, err := c.listObjects(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(cfg.Bucket),
+		Prefix: optionalString(prefix),
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	remote := make(map[string]s3types.Object, len(objects))
+	for _, obj := range objects {
+		rel := strings.TrimPrefix(aws.ToString(obj.Key), prefix)
+		// Skip "folder" placeholder objects.
+		if rel == "" || strings.HasSuffix(rel, "/") || !cfg.selected(rel) {
+			continue
+		}
+		if !cfg.contains(rel) {
+			return nil, fmt.Errorf("s3 sync: key %q escapes LocalDir", aws.ToString(obj.Key))
+		}
+		remote[rel] = obj
+	}
+	return remote, nil
+}
+
+// contains reports whether the slash separated rel resolves inside LocalDir.
+// Keys are remote input so "../" segments or absolute paths must not be
+// trusted when building local paths.
+func (cfg *S3SyncConfig) contains(rel string) bool {
+	native := filepath.FromSlash(rel)
+	if filepath.IsAbs(native) || filepath.VolumeName(native) != "" {
+		return false
+	}
+	inner, err := filepath.Rel(cfg.LocalDir, filepath.Join(cfg.LocalDir, native))
+	if err != nil {
+		return false
+	}
+	return inner != "." && inner != ".." && !strings.HasPrefix(inner, ".."+string(filepath.Separator))
+}
+
+// localFiles walks LocalDir keyed by slash separated relative path. A missing
+// directory is treated as empty so downloads can create it.
+func (cfg *S3SyncConfig) localFiles() (map[string]syncLocalFile, error) {
+	local := map[string]syncLocalFile{}
+	err := filepath.WalkDir(cfg.LocalDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == cfg.LocalDir && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(cfg.LocalDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !cfg.selected(rel) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		local[rel] = syncLocalFile{path: p, size: info.Size()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3 sync: walk %s: %w", cfg.LocalDir, err)
+	}
+	return local, nil
+}
+
+func (c *S3Client) planSync(
+	ctx context.Context,
+	cfg *S3SyncConfig,
+	local map[string]syncLocalFile,
+	remote map[string]s3types.Object,
+) (SyncPlan, error) {
+	plan := SyncPlan{Items: []SyncItem{}}
+
+	source, destination := slices.Sorted(maps.Keys(local)), slices.Sorted(maps.Keys(remote))
+	transfer, present := SyncActionUpload, func(rel string) bool { _, ok := remote[rel]; return ok }
+	if cfg.Direction == SyncDownload {
+		source, destination = destination, source
+		transfer, present = SyncActionDownload, func(rel string) bool { _, ok := local[rel]; return ok }
+	}
+
+	for _, rel := range source {
+		item := SyncItem{
+			Action: transfer,
+			Key:    cfg.key(rel),
+			Path:   filepath.Join(cfg.LocalDir, filepath.FromSlash(rel)),
+			Reason: "missing on destination",
+		}
+		if cfg.Direction == SyncUpload {
+			item.Size = local[rel].size
+		} else {
+			item.Size = aws.ToInt64(remote[rel].Size)
+		}
+
+		if present(rel) {
+			reason, err := c.syncDiff(ctx, cfg, local[rel], remote[rel])
+			if err != nil {
+				return plan, err
+			}
+			if reason == "" {
+				plan.Unchanged++
+				continue
+			}
+			item.Reason = reason
+		}
+		plan.Items = append(plan.Items, item)
+	}
+
+	if cfg.Delete {
+		for _, rel := range destination {
+			if _, found := slices.BinarySearch(source, rel); found {
+				continue
+			}
+			plan.Items = append(plan.Items, SyncItem{
+				Action: SyncActionDelete,
+				Key:    cfg.key(rel),
+				Path:   filepath.Join(cfg.LocalDir, filepath.FromSlash(rel)),
+				Reason: "missing on source",
+			})
+		}
+	}
+
+	sort.SliceStable(plan.Items, func(i, j int) bool { return plan.Items[i].Key < plan.Items[j].Key })
+	return plan, nil
+}
+
+// syncDiff returns why a local file and object differ, or "" when equal.
+func (c *S3Client) syncDiff(ctx context.Context, cfg *S3SyncConfig, file syncLocalFile, obj s3types.Object) (string, error) {
+	if file.size != aws.ToInt64(obj.Size) {
+		return "size differs", nil
+	}
+
+	if cfg.ChecksumMetaKey != "" {
+		head, err := c.client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(cfg.Bucket),
+			Key:    obj.Key,
+		})
+		if err != nil {
+			return "", fmt.Errorf("s3 sync: head %s: %w", aws.ToString(obj.Key), err)
+		}
+		if stored, ok := metadataValue(head.Metadata, cfg.ChecksumMetaKey); ok {
+			sum, err := utils.Sha256SumFile(file.path)
+			if err != nil {
+				return "", err
+			}
+			if !strings.EqualFold(sum, stored) {
+				return "checksum differs", nil
+			}
+			return "", nil
+		}
+	}
+
+	remoteETag := strings.Trim(aws.ToString(obj.ETag), `"`)
+	parts := 0
+	if i := strings.LastIndexByte(remoteETag, '-'); i >= 0 {
+		n, err := strconv.Atoi(remoteETag[i+1:])
+		if err != nil {
+			return "etag differs", nil
+		}
+		parts = n
+	}
+	localETag, err := fileETag(file.path, parts, cfg.PartSize)
+	if err != nil {
+		return "", err
+	}
+	if localETag != remoteETag {
+		return "etag differs", nil
+	}
+	return "", nil
+}
+
+// fileETag computes the S3 ETag of a file: the hex MD5 for single part
+// objects, or the MD5 of the part MD5s suffixed with the part count when the
+// object was uploaded in parts of partSize.
+func fileETag(filePath string, parts int, partSize int64) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", filePath, err)
+	}
+	defer f.Close()
+
+	if parts == 0 {
+		h := md5.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", fmt.Errorf("hash %s: %w", filePath, err)
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	sums := md5.New()
+	count := 0
+	for {
+		h := md5.New()
+		n, err := io.CopyN(h, f, partSize)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("hash %s: %w", filePath, err)
+		}
+		if n == 0 && count > 0 {
+			break
+		}
+		count++
+		sums.Write(h.Sum(nil))
+		if n < partSize {
+			break
+		}
+	}
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(sums.Sum(nil)), count), nil
+}
+
+// metadataValue looks up a user metadata key case-insensitively; S3 returns
+// keys lower-cased.
+func metadataValue(md map[string]string, key string) (string, bool) {
+	for k, v := range md {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// applySync performs the plan: transfers with bounded concurrency, then deletes.
+func (c *S3Client) applySync(ctx context.Context, cfg *S3SyncConfig, plan SyncPlan) error {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		errs    []error
+		deletes []SyncItem
+	)
+	addErr := func(item SyncItem, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, fmt.Errorf("%s %s: %w", item.Action, item.Key, err))
+	}
+
+	sem := make(chan struct{}, cfg.Concurrency)
+schedule:
+	for _, item := range plan.Items {
+		if item.Action == SyncActionDelete {
+			deletes = append(deletes, item)
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break schedule
+		}
+		wg.Add(1)
+		go func(item SyncItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			var err error
+			if item.Action == SyncActionUpload {
+				err = c.syncUpload(ctx, cfg, item)
+			} else {
+				err = c.syncDownload(ctx, cfg, item)
+			}
+			if err != nil {
+				addErr(item, err)
+			}
+		}(item)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	if len(deletes) > 0 {
+		if err := c.syncDelete(ctx, cfg, deletes); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *S3Client) syncUpload(ctx context.Context, cfg *S3SyncConfig, item SyncItem) error {
+	reqCfg := &S3RequestConfig{
+		Bucket: cfg.Bucket,
+		Key:    item.Key,
+	}
+	if cfg.ChecksumMetaKey != "" {
+		sum, err := utils.Sha256SumFile(item.Path)
+		if err != nil {
+			return err
+		}
+		reqCfg.ExtraOpts = map[string]any{"metadata": map[string]string{cfg.ChecksumMetaKey: sum}}
+	}
+
+	// Multipart uploads publish their own progress.
+	if item.Size > cfg.PartSize {
+		reqCfg.Operation = S3OpMultipartUpload
+		reqCfg.FilePath = item.Path
+		reqCfg.PartSize = cfg.PartSize
+		_, err := c.ProcessRequest(ctx, (&dto.RequestConfig{}).WithReqConfig(reqCfg))
+		return err
+	}
+
+	body, err := os.ReadFile(item.Path)
+	if err != nil {
+		return err
+	}
+	reqCfg.Operation = S3OpPut
+	reqCfg.Body = body
+	if _, err := c.ProcessRequest(ctx, (&dto.RequestConfig{}).WithReqConfig(reqCfg)); err != nil {
+		return err
+	}
+	c.publishTransfer(dto.TransferNotification{
+		Source:      item.Path,
+		Destination: s3URI(cfg.Bucket, item.Key),
+		Status:      dto.COMPLETE,
+		Downloaded:  item.Size,
+		TotalSize:   item.Size,
+		Percentage:  100,
+		Message:     "upload complete",
+	})
+	return nil
+}
+
+func (c *S3Client) syncDownload(ctx context.Context, cfg *S3SyncConfig, item SyncItem) error {
+	_, err := c.ProcessRequest(ctx, (&dto.RequestConfig{}).WithReqConfig(&S3RequestConfig{
+		Operation:       S3OpGet,
+		Bucket:          cfg.Bucket,
+		Key:             item.Key,
+		DestinationPath: item.Path,
+		PartSize:        cfg.PartSize,
+	}))
+	return err
+}
+
+// syncDelete removes extraneous objects in batches or local files.
+func (c *S3Client) syncDelete(ctx context.Context, cfg *S3SyncConfig, items []SyncItem) error {
+	if cfg.Direction == SyncDownload {
+		var errs []error
+		for _, item := range items {
+			if err := os.Remove(item.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, fmt.Errorf("%s %s: %w", item.Action, item.Key, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+	}
+	resp, err := c.ProcessRequest(ctx, (&dto.RequestConfig{}).WithReqConfig(&S3RequestConfig{
+		Operation: S3OpDeleteMany,
+		Bucket:    cfg.Bucket,
+		Keys:      keys,
+	}))
+	if err != nil {
+		return err
+	}
+
+	var result DeleteManyResult
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return fmt.Errorf("decode delete result: %w", err)
+	}
+	var errs []error
+	for _, e := range result.Errors {
+		errs = append(errs, fmt.Errorf("%s %s: %s: %s", SyncActionDelete, e.Key, e.Code, e.Message))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,326 @@
+package s3client
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func remoteObject(key, content string) s3types.Object {
+	sum := md5.Sum([]byte(content))
+	return s3types.Object{
+		Key:  aws.String(key),
+		Size: aws.Int64(int64(len(content))),
+		ETag: aws.String(`"` + hex.EncodeToString(sum[:]) + `"`),
+	}
+}
+
+func planSummary(plan SyncPlan) []string {
+	var out []string
+	for _, item := range plan.Items {
+		out = append(out, fmt.Sprintf("%s %s (%s)", item.Action, item.Key, item.Reason))
+	}
+	return out
+}
+
+func TestS3Client_SyncUpload_Golden(t *testing.T) {
+	local := map[string]string{
+		"same.txt":      "unchanged",
+		"changed.txt":   "new content",
+		"edited.txt":    "abc",
+		"new/added.txt": "added",
+		"skip.tmp":      "excluded",
+	}
+	remote := []s3types.Object{
+		remoteObject("site/same.txt", "unchanged"),
+		remoteObject("site/changed.txt", "old"),
+		remoteObject("site/edited.txt", "xyz"),
+		remoteObject("site/stale.txt", "gone locally"),
+		remoteObject("site/keep.tmp", "excluded remote"),
+		{Key: aws.String("site/dir/")},
+	}
+
+	cases := []struct {
+		name       string
+		dryRun     bool
+		delete     bool
+		wantPlan   []string
+		wantPuts   []string
+		wantDelete []string
+	}{
+		{
+			name:   "dry run only plans",
+			dryRun: true,
+			delete: true,
+			wantPlan: []string{
+				"upload site/changed.txt (size differs)",
+				"upload site/edited.txt (etag differs)",
+				"upload site/new/added.txt (missing on destination)",
+				"delete site/stale.txt (missing on source)",
+			},
+		},
+		{
+			name: "uploads changed files without delete",
+			wantPlan: []string{
+				"upload site/changed.txt (size differs)",
+				"upload site/edited.txt (etag differs)",
+				"upload site/new/added.txt (missing on destination)",
+			},
+			wantPuts: []string{"site/changed.txt", "site/edited.txt", "site/new/added.txt"},
+		},
+		{
+			name:   "deletes extraneous objects",
+			delete: true,
+			wantPlan: []string{
+				"upload site/changed.txt (size differs)",
+				"upload site/edited.txt (etag differs)",
+				"upload site/new/added.txt (missing on destination)",
+				"delete site/stale.txt (missing on source)",
+			},
+			wantPuts:   []string{"site/changed.txt", "site/edited.txt", "site/new/added.txt"},
+			wantDelete: []string{"site/stale.txt"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, local)
+
+			c, f := newTestClient(t)
+			f.listOut = &s3.ListObjectsV2Output{Contents: remote}
+			log := &transferLog{}
+			c.SetTransferPublisher(log.publish)
+
+			plan, err := c.Sync(context.Background(), &S3SyncConfig{
+				Direction:   SyncUpload,
+				LocalDir:    dir,
+				Bucket:      "b",
+				Prefix:      "site/",
+				Delete:      tc.delete,
+				Exclude:     []string{"*.tmp"},
+				DryRun:      tc.dryRun,
+				Concurrency: 2,
+			})
+			if err != nil {
+				t.Fatalf("Sync error: %v", err)
+			}
+			if got := planSummary(plan); !reflect.DeepEqual(got, tc.wantPlan) {
+				t.Fatalf("plan mismatch:\n got=%q\nwant=%q", got, tc.wantPlan)
+			}
+			if plan.Unchanged != 1 {
+				t.Fatalf("unchanged=%d want 1", plan.Unchanged)
+			}
+			if aws.ToString(f.gotList[0].Prefix) != "site/" {
+				t.Fatalf("list prefix=%q", aws.ToString(f.gotList[0].Prefix))
+			}
+
+			var puts []string
+			for _, in := range f.gotPut {
+				puts = append(puts, aws.ToString(in.Key))
+			}
+			sort.Strings(puts)
+			if !reflect.DeepEqual(puts, tc.wantPuts) {
+				t.Fatalf("puts=%v want %v", puts, tc.wantPuts)
+			}
+
+			var deleted []string
+			for _, in := range f.gotDeleteMany {
+				for _, obj := range in.Delete.Objects {
+					deleted = append(deleted, aws.ToString(obj.Key))
+				}
+			}
+			if !reflect.DeepEqual(deleted, tc.wantDelete) {
+				t.Fatalf("deleted=%v want %v", deleted, tc.wantDelete)
+			}
+			if len(tc.wantPuts) > 0 && log.last().Status != dto.COMPLETE {
+				t.Fatalf("expected transfer notifications, last=%+v", log.last())
+			}
+		})
+	}
+}
+
+func TestS3Client_SyncUpload_ChecksumMetaKey_Golden(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.txt": "same size"})
+	digest := sha256.Sum256([]byte("same size"))
+	sum := hex.EncodeToString(digest[:])
+
+	cases := []struct {
+		name       string
+		stored     string
+		wantUpload bool
+	}{
+		{name: "stored checksum differs", stored: "deadbeef", wantUpload: true},
+		{name: "missing checksum falls back to etag", stored: "", wantUpload: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, f := newTestClient(t)
+			f.listOut = &s3.ListObjectsV2Output{Contents: []s3types.Object{remoteObject("a.txt", "same size")}}
+			f.headOut = &s3.HeadObjectOutput{Metadata: map[string]string{}}
+			if tc.stored != "" {
+				f.headOut.Metadata["sha256"] = tc.stored
+			}
+
+			plan, err := c.Sync(context.Background(), &S3SyncConfig{
+				Direction:       SyncUpload,
+				LocalDir:        dir,
+				Bucket:          "b",
+				ChecksumMetaKey: "SHA256",
+			})
+			if err != nil {
+				t.Fatalf("Sync error: %v", err)
+			}
+			if (len(plan.Items) == 1) != tc.wantUpload {
+				t.Fatalf("plan=%v wantUpload=%v", planSummary(plan), tc.wantUpload)
+			}
+			if tc.wantUpload {
+				md := f.gotPut[0].Metadata
+				if md["SHA256"] != sum {
+					t.Fatalf("checksum metadata not stored: %v", md)
+				}
+			}
+		})
+	}
+}
+
+func TestS3Client_SyncDownload_Golden(t *testing.T) {
+	objects := map[string]string{
+		"docs/a.txt":     "alpha",
+		"docs/sub/b.txt": "bravo",
+	}
+
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"a.txt":     "alpha",
+		"extra.txt": "remove me",
+	})
+
+	c, f := newTestClient(t)
+	f.listOut = &s3.ListObjectsV2Output{Contents: []s3types.Object{
+		remoteObject("docs/a.txt", objects["docs/a.txt"]),
+		remoteObject("docs/sub/b.txt", objects["docs/sub/b.txt"]),
+	}}
+	f.getFn = func(in *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+		return rangedObject([]byte(objects[aws.ToString(in.Key)]), `"e"`)(in)
+	}
+
+	plan, err := c.Sync(context.Background(), &S3SyncConfig{
+		Direction: SyncDownload,
+		LocalDir:  dir,
+		Bucket:    "b",
+		Prefix:    "docs",
+		Delete:    true,
+	})
+	if err != nil {
+		t.Fatalf("Sync error: %v", err)
+	}
+
+	want := []string{
+		"delete docs/extra.txt (missing on source)",
+		"download docs/sub/b.txt (missing on destination)",
+	}
+	if got := planSummary(plan); !reflect.DeepEqual(got, want) {
+		t.Fatalf("plan mismatch:\n got=%q\nwant=%q", got, want)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "sub", "b.txt"))
+	if err != nil || string(got) != "bravo" {
+		t.Fatalf("downloaded file=%q err=%v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "extra.txt")); !os.IsNotExist(err) {
+		t.Fatalf("extra.txt should be removed, stat err=%v", err)
+	}
+}
+
+func TestFileETag_Golden(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "f")
+	if err := os.WriteFile(p, []byte(strings.Repeat("a", 10)), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	partMD5 := func(s string) []byte { sum := md5.Sum([]byte(s)); return sum[:] }
+	whole := md5.Sum([]byte(strings.Repeat("a", 10)))
+	multi := md5.Sum(append(append(partMD5("aaaa"), partMD5("aaaa")...), partMD5("aa")...))
+	exact := md5.Sum(append(partMD5("aaaaa"), partMD5("aaaaa")...))
+
+	cases := []struct {
+		name     string
+		parts    int
+		partSize int64
+		want     string
+	}{
+		{name: "single part is plain md5", partSize: 4, want: hex.EncodeToString(whole[:])},
+		{name: "multipart md5 of md5s", parts: 3, partSize: 4, want: hex.EncodeToString(multi[:]) + "-3"},
+		{name: "exact multiple of part size", parts: 2, partSize: 5, want: hex.EncodeToString(exact[:]) + "-2"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := fileETag(p, tc.parts, tc.partSize)
+			if err != nil {
+				t.Fatalf("fileETag error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("etag=%q want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestS3SyncConfig_Validate_Golden(t *testing.T) {
+	cases := []struct {
+		name     string
+		partSize int64
+		want     int64
+		wantErr  string
+	}{
+		{name: "zero uses default", want: DefaultMultipartPartSize},
+		{name: "explicit size kept", partSize: 2 * DefaultMultipartPartSize, want: 2 * DefaultMultipartPartSize},
+		{name: "below minimum", partSize: 5, wantErr: "s3 sync: multipart upload PartSize 5 is below the S3 minimum"},
+		{name: "negative", partSize: -1, wantErr: "s3 sync: multipart upload PartSize -1 is below the S3 minimum"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &S3SyncConfig{Direction: SyncUpload, LocalDir: t.TempDir(), Bucket: "b", PartSize: tc.partSize}
+			err := cfg.validate()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil || cfg.PartSize != tc.want {
+				t.Fatalf("PartSize=%d err=%v want %d", cfg.PartSize, err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,477 @@
+package s3test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key := splitPath(r.URL.Path)
+	q := r.URL.Query()
+
+	if bucket == "" {
+		writeError(w, http.StatusNotImplemented, "NotImplemented", "service level operations are not supported")
+		return
+	}
+
+	if key == "" {
+		switch {
+		case r.Method == http.MethodPut:
+			s.CreateBucket(bucket)
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodGet && q.Get("list-type") == "2":
+			s.listObjectsV2(w, r, bucket)
+		case r.Method == http.MethodPost && q.Has("delete"):
+			s.deleteObjects(w, r, bucket)
+		default:
+			writeError(w, http.StatusNotImplemented, "NotImplemented", r.Method+" on bucket is not supported")
+		}
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		s.createMultipartUpload(w, r, bucket, key)
+	case r.Method == http.MethodPut && q.Has("uploadId"):
+		s.uploadPart(w, r, q.Get("uploadId"), q.Get("partNumber"))
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		s.completeMultipartUpload(w, r, bucket, key, q.Get("uploadId"))
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		s.abortMultipartUpload(w, q.Get("uploadId"))
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		s.copyObject(w, r, bucket, key)
+	case r.Method == http.MethodPut:
+		s.putObject(w, r, bucket, key)
+	case r.Method == http.MethodGet, r.Method == http.MethodHead:
+		s.getObject(w, r, bucket, key)
+	case r.Method == http.MethodDelete:
+		s.deleteObject(w, bucket, key)
+	default:
+		writeError(w, http.StatusNotImplemented, "NotImplemented", r.Method+" on object is not supported")
+	}
+}
+
+// readBody returns the request payload, decoding aws-chunked framing used for
+// streaming signatures and trailing checksums.
+func readBody(r *http.Request) ([]byte, error) {
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") ||
+		strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return decodeAWSChunked(raw)
+	}
+	return raw, nil
+}
+
+// decodeAWSChunked strips "<hex-size>[;ext]\r\n<data>\r\n" framing up to the
+// zero-length chunk; trailers after it are ignored.
+func decodeAWSChunked(raw []byte) ([]byte, error) {
+	var out bytes.Buffer
+	for {
+		line, rest, ok := bytes.Cut(raw, []byte("\r\n"))
+		if !ok {
+			return nil, fmt.Errorf("aws-chunked: missing chunk header")
+		}
+		sizeHex, _, _ := bytes.Cut(line, []byte(";"))
+		size, err := strconv.ParseInt(string(bytes.TrimSpace(sizeHex)), 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("aws-chunked: bad chunk size %q", sizeHex)
+		}
+		if size == 0 {
+			return out.Bytes(), nil
+		}
+		if int64(len(rest)) < size+2 {
+			return nil, fmt.Errorf("aws-chunked: truncated chunk")
+		}
+		out.Write(rest[:size])
+		raw = rest[size+2:]
+	}
+}
+
+func userMetadata(h http.Header) map[string]string {
+	md := map[string]string{}
+	for k, v := range h {
+		if name, ok := strings.CutPrefix(strings.ToLower(k), "x-amz-meta-"); ok && len(v) > 0 {
+			md[name] = v[0]
+		}
+	}
+	return md
+}
+
+func (s *Server) putObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	data, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	}
+	obj := newObject(key, data, r.Header.Get("Content-Type"), userMetadata(r.Header))
+	objects[key] = obj
+	w.Header().Set("ETag", obj.ETag)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	s.mu.Lock()
+	objects, bucketOK := s.buckets[bucket]
+	obj, ok := objects[key]
+	s.mu.Unlock()
+
+	head := r.Method == http.MethodHead
+	if !bucketOK {
+		writeErrorMaybeHead(w, head, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	}
+	if !ok {
+		writeErrorMaybeHead(w, head, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+		return
+	}
+
+	if m := r.Header.Get("If-Match"); m != "" && m != obj.ETag {
+		writeErrorMaybeHead(w, head, http.StatusPreconditionFailed, "PreconditionFailed", "At least one of the pre-conditions you specified did not hold")
+		return
+	}
+	if m := r.Header.Get("If-None-Match"); m != "" && m == obj.ETag {
+		w.Header().Set("ETag", obj.ETag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	hdr := w.Header()
+	hdr.Set("ETag", obj.ETag)
+	hdr.Set("Last-Modified", obj.LastModified.Format(http.TimeFormat))
+	hdr.Set("Accept-Ranges", "bytes")
+	if obj.ContentType != "" {
+		hdr.Set("Content-Type", obj.ContentType)
+	}
+	for k, v := range obj.Metadata {
+		hdr.Set("X-Amz-Meta-"+k, v)
+	}
+
+	body, status := obj.Data, http.StatusOK
+	if rng := r.Header.Get("Range"); rng != "" && !head {
+		start, end, ok := parseRange(rng, int64(len(obj.Data)))
+		if !ok {
+			hdr.Set("Content-Range", fmt.Sprintf("bytes */%d", len(obj.Data)))
+			writeError(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange", "The requested range is not satisfiable")
+			return
+		}
+		body, status = obj.Data[start:end+1], http.StatusPartialContent
+		hdr.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(obj.Data)))
+	}
+
+	hdr.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	if !head {
+		_, _ = w.Write(body)
+	}
+}
+
+// parseRange supports a single "bytes=a-b", "bytes=a-" or "bytes=-n" range.
+func parseRange(header string, size int64) (int64, int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+	from, to, _ := strings.Cut(spec, "-")
+	if from == "" {
+		n, err := strconv.ParseInt(to, 10, 64)
+		if err != nil || n <= 0 || size == 0 {
+			return 0, 0, false
+		}
+		return max(size-n, 0), size - 1, true
+	}
+	start, err := strconv.ParseInt(from, 10, 64)
+	if err != nil || start >= size {
+		return 0, 0, false
+	}
+	end := size - 1
+	if to != "" {
+		if end, err = strconv.ParseInt(to, 10, 64); err != nil || end < start {
+			return 0, 0, false
+		}
+		end = min(end, size-1)
+	}
+	return start, end, true
+}
+
+func (s *Server) deleteObject(w http.ResponseWriter, bucket, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	}
+	delete(objects, key)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) copyObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	source, err := url.PathUnescape(strings.TrimPrefix(r.Header.Get("X-Amz-Copy-Source"), "/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidArgument", "bad copy source")
+		return
+	}
+	source, _, _ = strings.Cut(source, "?")
+	srcBucket, srcKey := splitPath(source)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src, ok := s.buckets[srcBucket][srcKey]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+		return
+	}
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	}
+
+	contentType, metadata := src.ContentType, src.Metadata
+	if r.Header.Get("X-Amz-Metadata-Directive") == "REPLACE" {
+		contentType, metadata = r.Header.Get("Content-Type"), userMetadata(r.Header)
+	}
+	obj := newObject(key, append([]byte(nil), src.Data...), contentType, metadata)
+	objects[key] = obj
+
+	writeXML(w, http.StatusOK, copyObjectResult{ETag: obj.ETag, LastModified: obj.LastModified.Format(time.RFC3339)})
+}
+
+func (s *Server) listObjectsV2(w http.ResponseWriter, r *http.Request, bucket string) {
+	q := r.URL.Query()
+	prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+	maxKeys := 1000
+	if v := q.Get("max-keys"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			maxKeys = n
+		}
+	}
+	// The continuation token is the last key returned, which keeps paging
+	// stable while objects are added or removed.
+	after := q.Get("start-after")
+	if token := q.Get("continuation-token"); token != "" {
+		after = token
+	}
+
+	s.mu.Lock()
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	}
+	result := listBucketResult{Name: bucket, Prefix: prefix, Delimiter: delimiter, MaxKeys: maxKeys}
+	seenPrefixes := map[string]bool{}
+	for _, k := range sortedKeys(objects) {
+		if !strings.HasPrefix(k, prefix) || k <= after {
+			continue
+		}
+		if len(result.Contents)+len(result.CommonPrefixes) == maxKeys {
+			result.IsTruncated = true
+			break
+		}
+		if delimiter != "" {
+			if i := strings.Index(k[len(prefix):], delimiter); i >= 0 {
+				cp := k[:len(prefix)+i+len(delimiter)]
+				if !seenPrefixes[cp] {
+					seenPrefixes[cp] = true
+					result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: cp})
+				}
+				result.NextContinuationToken = k
+				continue
+			}
+		}
+		obj := objects[k]
+		result.Contents = append(result.Contents, listEntry{
+			Key:          k,
+			LastModified: obj.LastModified.Format(time.RFC3339),
+			ETag:         obj.ETag,
+			Size:         int64(len(obj.Data)),
+			StorageClass: "STANDARD",
+		})
+		result.NextContinuationToken = k
+	}
+	s.mu.Unlock()
+
+	if !result.IsTruncated {
+		result.NextContinuationToken = ""
+	}
+	result.KeyCount = len(result.Contents) + len(result.CommonPrefixes)
+	result.ContinuationToken = q.Get("continuation-token")
+	writeXML(w, http.StatusOK, result)
+}
+
+func (s *Server) deleteObjects(w http.ResponseWriter, r *http.Request, bucket string) {
+	data, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+	var req deleteRequest
+	if err := xml.Unmarshal(data, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "MalformedXML", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	}
+	var result deleteResult
+	for _, obj := range req.Objects {
+		delete(objects, obj.Key)
+		if !req.Quiet {
+			result.Deleted = append(result.Deleted, deletedEntry{Key: obj.Key})
+		}
+	}
+	writeXML(w, http.StatusOK, result)
+}
+
+func (s *Server) createMultipartUpload(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.buckets[bucket]; !ok {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	}
+	s.nextID++
+	id := fmt.Sprintf("upload-%d", s.nextID)
+	s.uploads[id] = &multipartUpload{
+		bucket:      bucket,
+		key:         key,
+		contentType: r.Header.Get("Content-Type"),
+		metadata:    userMetadata(r.Header),
+		parts:       map[int][]byte{},
+	}
+	writeXML(w, http.StatusOK, initiateResult{Bucket: bucket, Key: key, UploadID: id})
+}
+
+func (s *Server) uploadPart(w http.ResponseWriter, r *http.Request, uploadID, partNumber string) {
+	n, err := strconv.Atoi(partNumber)
+	if err != nil || n < 1 || n > 10000 {
+		writeError(w, http.StatusBadRequest, "InvalidArgument", "Part number must be an integer between 1 and 10000")
+		return
+	}
+	data, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, ok := s.uploads[uploadID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist")
+		return
+	}
+	upload.parts[n] = data
+	sum := md5.Sum(data)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) completeMultipartUpload(w http.ResponseWriter, r *http.Request, bucket, key, uploadID string) {
+	data, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+	var req completeRequest
+	if err := xml.Unmarshal(data, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "MalformedXML", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, ok := s.uploads[uploadID]
+	if !ok || upload.bucket != bucket || upload.key != key {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist")
+		return
+	}
+	if !sort.SliceIsSorted(req.Parts, func(i, j int) bool { return req.Parts[i].PartNumber < req.Parts[j].PartNumber }) {
+		writeError(w, http.StatusBadRequest, "InvalidPartOrder", "The list of parts was not in ascending order")
+		return
+	}
+
+	var body bytes.Buffer
+	sums := md5.New()
+	for _, p := range req.Parts {
+		part, ok := upload.parts[p.PartNumber]
+		partSum := md5.Sum(part)
+		if !ok || strings.Trim(p.ETag, `"`) != hex.EncodeToString(partSum[:]) {
+			writeError(w, http.StatusBadRequest, "InvalidPart", fmt.Sprintf("part %d could not be found", p.PartNumber))
+			return
+		}
+		body.Write(part)
+		sums.Write(partSum[:])
+	}
+
+	obj := newObject(key, body.Bytes(), upload.contentType, upload.metadata)
+	obj.ETag = fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(sums.Sum(nil)), len(req.Parts))
+	s.buckets[bucket][key] = obj
+	delete(s.uploads, uploadID)
+
+	writeXML(w, http.StatusOK, completeResult{Bucket: bucket, Key: key, ETag: obj.ETag})
+}
+
+func (s *Server) abortMultipartUpload(w http.ResponseWriter, uploadID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.uploads[uploadID]; !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist")
+		return
+	}
+	delete(s.uploads, uploadID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeXML(w http.ResponseWriter, status int, v any) {
+	out, err := xml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(out)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeXML(w, status, errorResponse{Code: code, Message: message})
+}
+
+// writeErrorMaybeHead omits the body for HEAD requests, as S3 does.
+func writeErrorMaybeHead(w http.ResponseWriter, head bool, status int, code, message string) {
+	if head {
+		w.WriteHeader(status)
+		return
+	}
+	writeError(w, status, code, message)
+}
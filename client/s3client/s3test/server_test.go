@@ -0,0 +1,360 @@
+package s3test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/joy-dx/gonetic"
+	"github.com/joy-dx/gonetic/client/s3client"
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+	relayDTO "github.com/joy-dx/relay/dto"
+)
+
+type nopRelay struct{}
+
+func (nopRelay) Debug(relayDTO.RelayEventInterface) {}
+func (nopRelay) Info(relayDTO.RelayEventInterface)  {}
+func (nopRelay) Warn(relayDTO.RelayEventInterface)  {}
+func (nopRelay) Error(relayDTO.RelayEventInterface) {}
+func (nopRelay) Fatal(relayDTO.RelayEventInterface) {}
+func (nopRelay) Meta(relayDTO.RelayEventInterface)  {}
+
+func newClient(t *testing.T, srv *Server) *s3client.S3Client {
+	t.Helper()
+	cfg := srv.ClientConfig()
+	c, err := s3client.NewS3Client("s3", &cfg)
+	if err != nil {
+		t.Fatalf("NewS3Client: %v", err)
+	}
+	return c
+}
+
+func do(t *testing.T, c *s3client.S3Client, cfg *s3client.S3RequestConfig) dto.Response {
+	t.Helper()
+	resp, err := c.ProcessRequest(context.Background(), (&dto.RequestConfig{}).WithReqConfig(cfg))
+	if err != nil {
+		t.Fatalf("%s %s: %v", cfg.Operation, cfg.Key, err)
+	}
+	return resp
+}
+
+func TestServer_SDKRoundTrip_Golden(t *testing.T) {
+	srv := NewServer("bucket")
+	t.Cleanup(srv.Close)
+	c := newClient(t, srv)
+
+	do(t, c, &s3client.S3RequestConfig{
+		Operation:   s3client.S3OpPut,
+		Bucket:      "bucket",
+		Key:         "dir/hello world.txt",
+		Body:        []byte("hello, s3"),
+		ContentType: "text/plain",
+		ExtraOpts:   map[string]any{"metadata": map[string]string{"owner": "tests"}},
+	})
+
+	cases := []struct {
+		name       string
+		cfg        *s3client.S3RequestConfig
+		wantStatus int
+		wantBody   string
+		check      func(t *testing.T, resp dto.Response)
+	}{
+		{
+			name:       "get",
+			cfg:        &s3client.S3RequestConfig{Operation: s3client.S3OpGet, Bucket: "bucket", Key: "dir/hello world.txt"},
+			wantStatus: http.StatusOK,
+			wantBody:   "hello, s3",
+			check: func(t *testing.T, resp dto.Response) {
+				if resp.Headers.Get("Content-Type") != "text/plain" {
+					t.Fatalf("content type=%q", resp.Headers.Get("Content-Type"))
+				}
+			},
+		},
+		{
+			name:       "ranged get",
+			cfg:        &s3client.S3RequestConfig{Operation: s3client.S3OpGet, Bucket: "bucket", Key: "dir/hello world.txt", Range: "bytes=7-8"},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "s3",
+		},
+		{
+			name:       "head",
+			cfg:        &s3client.S3RequestConfig{Operation: s3client.S3OpHead, Bucket: "bucket", Key: "dir/hello world.txt"},
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, resp dto.Response) {
+				var head s3client.HeadResult
+				if err := json.Unmarshal(resp.Body, &head); err != nil {
+					t.Fatalf("decode: %v", err)
+				}
+				if !head.Exists || head.ContentLength != 9 || head.Metadata["owner"] != "tests" {
+					t.Fatalf("unexpected head: %+v", head)
+				}
+			},
+		},
+		{
+			name:       "head missing",
+			cfg:        &s3client.S3RequestConfig{Operation: s3client.S3OpHead, Bucket: "bucket", Key: "nope"},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "copy",
+			cfg: &s3client.S3RequestConfig{
+				Operation: s3client.S3OpCopy,
+				Bucket:    "bucket",
+				Key:       "copy.txt",
+				SourceKey: "dir/hello world.txt",
+			},
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, resp dto.Response) {
+				obj, ok := srv.GetObject("bucket", "copy.txt")
+				if !ok || string(obj.Data) != "hello, s3" || obj.Metadata["owner"] != "tests" {
+					t.Fatalf("copy not stored: %+v", obj)
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := do(t, c, tc.cfg)
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("status=%d want %d", resp.StatusCode, tc.wantStatus)
+			}
+			if tc.wantBody != "" && string(resp.Body) != tc.wantBody {
+				t.Fatalf("body=%q want %q", resp.Body, tc.wantBody)
+			}
+			if tc.check != nil {
+				tc.check(t, resp)
+			}
+		})
+	}
+}
+
+func TestServer_ListPaginationAndDelete_Golden(t *testing.T) {
+	srv := NewServer("bucket")
+	t.Cleanup(srv.Close)
+	c := newClient(t, srv)
+
+	const total = 1003
+	for i := 0; i < total; i++ {
+		srv.PutObject("bucket", fmt.Sprintf("logs/%04d.log", i), []byte("x"))
+	}
+	srv.PutObject("bucket", "other/keep.txt", []byte("x"))
+
+	// Sync follows continuation tokens across pages.
+	plan := s3client.DefaultS3SyncConfig()
+	plan.Direction, plan.LocalDir, plan.Bucket, plan.Prefix, plan.DryRun = s3client.SyncDownload, t.TempDir(), "bucket", "logs", true
+	if p, err := c.Sync(context.Background(), &plan); err != nil || len(p.Items) != total {
+		t.Fatalf("sync plan items=%d err=%v, want %d", len(p.Items), err, total)
+	}
+
+	// list follows continuation tokens too, stopping early at MaxKeys.
+	resp := do(t, c, &s3client.S3RequestConfig{Operation: s3client.S3OpList, Bucket: "bucket", Prefix: "logs/", MaxKeys: 2})
+	if keys := strings.Fields(string(resp.Body)); !reflect.DeepEqual(keys, []string{"logs/0000.log", "logs/0001.log"}) {
+		t.Fatalf("listed %q with MaxKeys 2", keys)
+	}
+	resp = do(t, c, &s3client.S3RequestConfig{Operation: s3client.S3OpList, Bucket: "bucket", Prefix: "logs/"})
+	keys := strings.Fields(string(resp.Body))
+	if len(keys) != total || keys[total-1] != "logs/1002.log" {
+		t.Fatalf("listed %d keys, want %d", len(keys), total)
+	}
+	resp = do(t, c, &s3client.S3RequestConfig{Operation: s3client.S3OpDeleteMany, Bucket: "bucket", Keys: keys})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete_many status=%d body=%s", resp.StatusCode, resp.Body)
+	}
+	do(t, c, &s3client.S3RequestConfig{Operation: s3client.S3OpDelete, Bucket: "bucket", Key: "other/keep.txt"})
+
+	if left := srv.Keys("bucket"); len(left) != 0 {
+		t.Fatalf("objects left after delete: %v", left)
+	}
+}
+
+func TestServer_MultipartAndRangedDownload_Golden(t *testing.T) {
+	srv := NewServer("bucket")
+	t.Cleanup(srv.Close)
+	c := newClient(t, srv)
+
+	payload := bytes.Repeat([]byte("0123456789abcdef"), 704*1024) // 11 MiB
+	do(t, c, &s3client.S3RequestConfig{
+		Operation:   s3client.S3OpMultipartUpload,
+		Bucket:      "bucket",
+		Key:         "big.bin",
+		Reader:      bytes.NewReader(payload),
+		PartSize:    s3client.MinMultipartPartSize,
+		Concurrency: 3,
+	})
+
+	obj, ok := srv.GetObject("bucket", "big.bin")
+	if !ok || !bytes.Equal(obj.Data, payload) {
+		t.Fatalf("multipart object mismatch: ok=%v len=%d", ok, len(obj.Data))
+	}
+	if !strings.HasSuffix(obj.ETag, `-3"`) {
+		t.Fatalf("multipart etag=%s", obj.ETag)
+	}
+	if srv.PendingUploads() != 0 {
+		t.Fatalf("pending uploads=%d", srv.PendingUploads())
+	}
+
+	dest := filepath.Join(t.TempDir(), "big.bin")
+	do(t, c, &s3client.S3RequestConfig{
+		Operation:       s3client.S3OpGet,
+		Bucket:          "bucket",
+		Key:             "big.bin",
+		DestinationPath: dest,
+		PartSize:        4 * 1024 * 1024,
+	})
+	got, err := os.ReadFile(dest)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Fatalf("downloaded file mismatch: err=%v len=%d", err, len(got))
+	}
+
+	srv.PutObject("bucket", "empty", nil)
+	emptyDest := filepath.Join(t.TempDir(), "empty")
+	do(t, c, &s3client.S3RequestConfig{Operation: s3client.S3OpGet, Bucket: "bucket", Key: "empty", DestinationPath: emptyDest})
+	if info, err := os.Stat(emptyDest); err != nil || info.Size() != 0 {
+		t.Fatalf("empty download: info=%v err=%v", info, err)
+	}
+}
+
+func TestServer_SyncRoundTrip_Golden(t *testing.T) {
+	srv := NewServer("bucket")
+	t.Cleanup(srv.Close)
+	c := newClient(t, srv)
+
+	src := t.TempDir()
+	for rel, content := range map[string]string{"a.txt": "alpha", "sub/b.txt": "bravo"} {
+		p := filepath.Join(src, filepath.FromSlash(rel))
+		_ = os.MkdirAll(filepath.Dir(p), 0o755)
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	up := s3client.DefaultS3SyncConfig()
+	up.LocalDir, up.Bucket, up.Prefix = src, "bucket", "site"
+	if plan, err := c.Sync(context.Background(), &up); err != nil || len(plan.Items) != 2 {
+		t.Fatalf("first upload plan=%+v err=%v", plan, err)
+	}
+	if plan, err := c.Sync(context.Background(), &up); err != nil || len(plan.Items) != 0 || plan.Unchanged != 2 {
+		t.Fatalf("second upload should be a no-op: plan=%+v err=%v", plan, err)
+	}
+
+	dst := t.TempDir()
+	down := s3client.DefaultS3SyncConfig()
+	down.Direction, down.LocalDir, down.Bucket, down.Prefix = s3client.SyncDownload, dst, "bucket", "site"
+	if _, err := c.Sync(context.Background(), &down); err != nil {
+		t.Fatalf("download sync: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dst, "sub", "b.txt"))
+	if err != nil || string(got) != "bravo" {
+		t.Fatalf("downloaded=%q err=%v", got, err)
+	}
+}
+
+func TestServer_SyncHostileKey_Golden(t *testing.T) {
+	cases := []struct {
+		name string
+		key  string
+	}{
+		{name: "parent traversal", key: "site/../../evil.txt"},
+		{name: "nested traversal", key: "site/sub/../../../evil.txt"},
+		{name: "absolute", key: "site//evil.txt"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := NewServer("bucket")
+			t.Cleanup(srv.Close)
+			c := newClient(t, srv)
+			srv.PutObject("bucket", "site/ok.txt", []byte("ok"))
+			srv.PutObject("bucket", tc.key, []byte("pwned"))
+
+			root := t.TempDir()
+			dst := filepath.Join(root, "a", "b")
+			for _, dir := range []s3client.SyncDirection{s3client.SyncDownload, s3client.SyncUpload} {
+				cfg := s3client.DefaultS3SyncConfig()
+				cfg.Direction, cfg.LocalDir, cfg.Bucket, cfg.Prefix, cfg.Delete = dir, dst, "bucket", "site", true
+				if _, err := c.Sync(context.Background(), &cfg); err == nil || !strings.Contains(err.Error(), "escapes LocalDir") {
+					t.Fatalf("%s: expected escape error, got %v", dir, err)
+				}
+			}
+			if _, err := os.Stat(filepath.Join(root, "evil.txt")); !errors.Is(err, fs.ErrNotExist) {
+				t.Fatalf("file written outside LocalDir: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dst, "ok.txt")); !errors.Is(err, fs.ErrNotExist) {
+				t.Fatalf("sync should fail before transferring anything: %v", err)
+			}
+		})
+	}
+}
+
+func TestServer_ThroughNetSvc_Golden(t *testing.T) {
+	srv := NewServer("bucket")
+	t.Cleanup(srv.Close)
+	srv.PutObject("bucket", "report.json", []byte(`{"ok":true}`))
+
+	netCfg := config.DefaultNetSvcConfig()
+	netCfg.WithRelay(nopRelay{})
+	svc, err := gonetic.New(&netCfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = svc.Close() })
+	svc.RegisterClient("s3", newClient(t, srv))
+
+	var out struct {
+		OK bool `json:"ok"`
+	}
+	req := dto.DefaultRequestConfig()
+	req.WithClientRef("s3").
+		WithResponseObject(&out).
+		WithReqConfig(&s3client.S3RequestConfig{Operation: s3client.S3OpGet, Bucket: "bucket", Key: "report.json"})
+
+	if _, err := svc.RequestOnce(context.Background(), &req); err != nil {
+		t.Fatalf("RequestOnce: %v", err)
+	}
+	if !out.OK {
+		t.Fatalf("response object not decoded")
+	}
+}
+
+func TestDecodeAWSChunked_Golden(t *testing.T) {
+	cases := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "signed chunks with trailer",
+			raw:  "5;chunk-signature=abc\r\nhello\r\n6;chunk-signature=def\r\n world\r\n0;chunk-signature=ghi\r\nx-amz-checksum-crc32:AAAA\r\n\r\n",
+			want: "hello world",
+		},
+		{
+			name: "unsigned chunks",
+			raw:  "3\r\nabc\r\n0\r\n\r\n",
+			want: "abc",
+		},
+		{name: "truncated", raw: "a\r\nabc", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := decodeAWSChunked([]byte(tc.raw))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tc.wantErr)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("got=%q want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package vcrclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
+)
+
+const cassetteVersion = 1
+
+// Cassette is the on-disk recording, stored as indented JSON.
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its outcome.
+type Interaction struct {
+	Request    CassetteRequest  `json:"request"`
+	Response   CassetteResponse `json:"response"`
+	RecordedAt time.Time        `json:"recorded_at"`
+}
+
+// CassetteRequest is a redacted dto.RequestDescription plus the body hash
+// used for matching.
+type CassetteRequest struct {
+	ClientType dto.NetClientType `json:"client_type"`
+	Method     string            `json:"method"`
+	URL        string            `json:"url"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       []byte            `json:"body,omitempty"`
+	BodySHA256 string            `json:"body_sha256,omitempty"`
+}
+
+// CassetteResponse is the recorded dto.Response, or the error returned
+// instead. Stream records that the body was delivered as dto.Response.Stream,
+// File that it was written to the request's DestinationPath, and Permanent
+// that the error was not temporary according to utils.IsTemporaryErr.
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+	Stream     bool        `json:"stream,omitempty"`
+	File       bool        `json:"file,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Permanent  bool        `json:"permanent,omitempty"`
+}
+
+// LoadCassette reads a cassette file.
+func LoadCassette(path string) (*Cassette, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("decode cassette %s: %w", path, err)
+	}
+	if c.Version != cassetteVersion {
+		return nil, fmt.Errorf("cassette %s: unsupported version %d", path, c.Version)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette atomically, creating parent directories.
+func (c *Cassette) Save(path string) error {
+	c.Version = cassetteVersion
+	raw, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create cassette dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(raw, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Join(fmt.Errorf("write cassette: %w", err), os.Remove(tmp))
+	}
+	return nil
+}
+
+// cassetteTrailer closes the interactions array of a non-empty cassette
+// written by Save.
+const cassetteTrailer = "\n  ]\n}\n"
+
+// appendLast writes the last interaction to the cassette at path, which must
+// hold every earlier one, without rewriting them. A file that does not end
+// the way Save leaves it, e.g. one edited by hand, is saved in full instead.
+func (c *Cassette) appendLast(path string) error {
+	if len(c.Interactions) < 2 {
+		return c.Save(path)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return c.Save(path)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+	end := info.Size() - int64(len(cassetteTrailer))
+	tail := make([]byte, len(cassetteTrailer))
+	if end <= 0 {
+		f.Close()
+		return c.Save(path)
+	}
+	if _, err := f.ReadAt(tail, end); err != nil || string(tail) != cassetteTrailer {
+		f.Close()
+		return c.Save(path)
+	}
+
+	raw, err := json.MarshalIndent(c.Interactions[len(c.Interactions)-1], "    ", "  ")
+	if err != nil {
+		return fmt.Errorf("encode cassette: %w", err)
+	}
+	entry := append(append([]byte(",\n    "), raw...), cassetteTrailer...)
+	if _, err := f.WriteAt(entry, end); err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+	return nil
+}
+
+func newCassetteRequest(desc dto.RequestDescription, redactor utils.Redactor) CassetteRequest {
+	req := CassetteRequest{
+		ClientType: desc.ClientType,
+		Method:     desc.Method,
+		URL:        redactor.URL(desc.URL),
+		Headers:    redactor.HeaderMap(desc.Headers),
+		Body:       redactor.Body(desc.Body),
+	}
+	if len(req.Body) > 0 {
+		sum := sha256.Sum256(req.Body)
+		req.BodySHA256 = hex.EncodeToString(sum[:])
+	}
+	return req
+}
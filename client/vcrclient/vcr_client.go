@@ -0,0 +1,289 @@
+// Package vcrclient records interactions of any NetSvc client to a cassette
+// file and replays them deterministically, without network access.
+package vcrclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
+)
+
+// ErrInteractionNotFound is returned in replay mode when no recorded
+// interaction matches a request.
+var ErrInteractionNotFound = errors.New("vcr: no recorded interaction")
+
+// VCRClient wraps a registered client. Register it under the wrapped client's
+// ref so existing RequestConfigs are served from the cassette:
+//
+//	vcr, err := vcrclient.NewVCRClient(s3Client, &vcrCfg)
+//	svc.RegisterClient("s3", vcr)
+//
+// The ReqConfig of each request must implement dto.RequestDescriber.
+type VCRClient struct {
+	inner    dto.NetClientInterface
+	cfg      *VCRClientConfig
+	redactor utils.Redactor
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+	// saved is set once the cassette file holds every interaction in
+	// cassette, so new ones can be appended
+	saved bool
+}
+
+func NewVCRClient(inner dto.NetClientInterface, cfg *VCRClientConfig) (*VCRClient, error) {
+	if inner == nil {
+		return nil, errors.New("vcr: nil client")
+	}
+	if cfg == nil {
+		return nil, errors.New("vcr: nil config")
+	}
+	if cfg.CassettePath == "" {
+		return nil, errors.New("vcr: cassette path required")
+	}
+
+	cassette := &Cassette{Version: cassetteVersion}
+	saved := false
+	switch cfg.Mode {
+	case VCRModeRecord:
+	case VCRModeReplay, VCRModeReplayOrRecord:
+		loaded, err := LoadCassette(cfg.CassettePath)
+		switch {
+		case err == nil:
+			cassette, saved = loaded, true
+		case errors.Is(err, os.ErrNotExist) && cfg.Mode == VCRModeReplayOrRecord:
+		default:
+			return nil, fmt.Errorf("vcr: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("vcr: unknown mode %q", cfg.Mode)
+	}
+
+	redactor := cfg.Redactor
+	if redactor.IsZero() {
+		redactor = utils.DefaultRedactor()
+	}
+	return &VCRClient{
+		inner:    inner,
+		cfg:      cfg,
+		redactor: redactor,
+		cassette: cassette,
+		used:     make([]bool, len(cassette.Interactions)),
+		saved:    saved,
+	}, nil
+}
+
+func (c *VCRClient) Ref() string {
+	return c.inner.Ref()
+}
+
+func (c *VCRClient) Type() dto.NetClientType {
+	return c.inner.Type()
+}
+
+// Descriptor describes the wrapped client.
+func (c *VCRClient) Descriptor() dto.NetClient {
+	desc := dto.NetClient{Ref: c.inner.Ref(), ClientType: c.inner.Type()}
+	if descriptor, ok := c.inner.(dto.NetClientDescriptor); ok {
+		desc = descriptor.Descriptor()
+	}
+	desc.Name = "VCR: " + desc.Name
+	return desc
+}
+
+// SetTransferPublisher forwards the publisher to the wrapped client.
+func (c *VCRClient) SetTransferPublisher(publish func(dto.TransferNotification)) {
+	if publisher, ok := c.inner.(dto.TransferPublisher); ok {
+		publisher.SetTransferPublisher(publish)
+	}
+}
+
+// DownloadFile forwards to the wrapped client. File downloads are not recorded.
+func (c *VCRClient) DownloadFile(ctx context.Context, cfg *dto.DownloadFileConfig, destination string) error {
+	downloader, ok := c.inner.(dto.FileDownloader)
+	if !ok {
+		return fmt.Errorf("client %s does not support file downloads", c.inner.Ref())
+	}
+	return downloader.DownloadFile(ctx, cfg, destination)
+}
+
+// Cassette returns a copy of the interactions recorded or loaded so far.
+func (c *VCRClient) Cassette() Cassette {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Cassette{
+		Version:      c.cassette.Version,
+		Interactions: append([]Interaction(nil), c.cassette.Interactions...),
+	}
+}
+
+func (c *VCRClient) ProcessRequest(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+	if cfg == nil || cfg.ReqConfig == nil {
+		return dto.Response{}, dto.ErrNilReqConfig
+	}
+	describer, ok := cfg.ReqConfig.(dto.RequestDescriber)
+	if !ok {
+		return dto.Response{}, fmt.Errorf("vcr: %T does not implement dto.RequestDescriber", cfg.ReqConfig)
+	}
+	desc, err := describer.Describe()
+	if err != nil {
+		return dto.Response{}, fmt.Errorf("vcr: %w", err)
+	}
+	live := newCassetteRequest(desc, c.redactor)
+
+	if c.cfg.Mode != VCRModeRecord {
+		if interaction, found := c.lookup(live); found {
+			return replay(interaction.Response, desc.DestinationPath)
+		}
+		if c.cfg.Mode == VCRModeReplay {
+			return dto.Response{}, fmt.Errorf("%w for %s %s", ErrInteractionNotFound, live.Method, live.URL)
+		}
+	}
+
+	resp, reqErr := c.inner.ProcessRequest(ctx, cfg)
+	recorded, resp, err := c.capture(resp, reqErr, desc.DestinationPath)
+	if err != nil {
+		return resp, err
+	}
+	if err := c.record(Interaction{Request: live, Response: recorded, RecordedAt: time.Now().UTC()}); err != nil {
+		return resp, err
+	}
+	return resp, reqErr
+}
+
+// lookup returns the first unused matching interaction. Once every match has
+// been used the last one keeps answering, so repeated idempotent calls replay.
+func (c *VCRClient) lookup(live CassetteRequest) (Interaction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last := -1
+	for i, interaction := range c.cassette.Interactions {
+		if !c.matches(live, interaction.Request) {
+			continue
+		}
+		if !c.used[i] {
+			c.used[i] = true
+			return interaction, true
+		}
+		last = i
+	}
+	if last >= 0 {
+		return c.cassette.Interactions[last], true
+	}
+	return Interaction{}, false
+}
+
+func (c *VCRClient) matches(live, recorded CassetteRequest) bool {
+	for _, m := range c.cfg.Matchers {
+		if !m(live, recorded) {
+			return false
+		}
+	}
+	return true
+}
+
+// capture builds the redacted recording of a response. A streamed body is
+// read fully and handed back to the caller as a fresh stream. A body written
+// to destination is read back from the file.
+func (c *VCRClient) capture(resp dto.Response, reqErr error, destination string) (CassetteResponse, dto.Response, error) {
+	body := resp.Body
+	file := destination != "" && reqErr == nil
+	if file {
+		written, err := os.ReadFile(destination)
+		if err != nil {
+			return CassetteResponse{}, resp, fmt.Errorf("vcr: read destination: %w", err)
+		}
+		body = written
+	}
+	if resp.Stream != nil {
+		streamed, err := io.ReadAll(resp.Stream)
+		_ = resp.Stream.Close()
+		if err != nil {
+			return CassetteResponse{}, dto.Response{}, fmt.Errorf("vcr: read stream: %w", err)
+		}
+		body = streamed
+		resp.Stream = io.NopCloser(bytes.NewReader(streamed))
+	}
+
+	recorded := CassetteResponse{
+		StatusCode: resp.StatusCode,
+		Headers:    c.redactor.Header(resp.Headers),
+		Body:       c.redactor.Body(body),
+		Stream:     resp.Stream != nil,
+		File:       file,
+	}
+	if reqErr != nil {
+		recorded.Error = reqErr.Error()
+		recorded.Permanent = !utils.IsTemporaryErr(reqErr)
+	}
+	return recorded, resp, nil
+}
+
+func (c *VCRClient) record(interaction Interaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cassette.Interactions = append(c.cassette.Interactions, interaction)
+	c.used = append(c.used, true)
+	save := c.cassette.Save
+	if c.saved {
+		save = c.cassette.appendLast
+	}
+	// A failed write may leave the file partial; rewrite it in full next time.
+	if err := save(c.cfg.CassettePath); err != nil {
+		c.saved = false
+		return fmt.Errorf("vcr: %w", err)
+	}
+	c.saved = true
+	return nil
+}
+
+// replay rebuilds a recorded response. A body recorded from a file is written
+// to the live request's destination rather than returned.
+func replay(recorded CassetteResponse, destination string) (dto.Response, error) {
+	resp := dto.Response{
+		StatusCode: recorded.StatusCode,
+		Headers:    recorded.Headers.Clone(),
+	}
+	switch {
+	case recorded.File:
+		if destination == "" {
+			return dto.Response{}, errors.New("vcr: recorded file response needs a destination path")
+		}
+		if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+			return dto.Response{}, fmt.Errorf("vcr: create destination folder: %w", err)
+		}
+		if err := os.WriteFile(destination, recorded.Body, 0o644); err != nil {
+			return dto.Response{}, fmt.Errorf("vcr: write destination: %w", err)
+		}
+	case recorded.Stream:
+		resp.Stream = io.NopCloser(bytes.NewReader(recorded.Body))
+	default:
+		resp.Body = append([]byte(nil), recorded.Body...)
+	}
+	if recorded.Error != "" {
+		return resp, &ReplayedError{Message: recorded.Error, Permanent: recorded.Permanent}
+	}
+	return resp, nil
+}
+
+// ReplayedError is returned for interactions recorded with an error. It keeps
+// the recorded temporary/permanent classification for retry decisions.
+type ReplayedError struct {
+	Message   string
+	Permanent bool
+}
+
+func (e *ReplayedError) Error() string { return e.Message }
+
+func (e *ReplayedError) Temporary() bool { return !e.Permanent }
@@ -0,0 +1,54 @@
+package vcrclient
+
+import (
+	"github.com/joy-dx/gonetic/utils"
+)
+
+// VCRMode selects whether interactions are recorded, replayed or both.
+type VCRMode string
+
+const (
+	// VCRModeRecord sends every request to the wrapped client and rewrites the
+	// cassette from scratch.
+	VCRModeRecord VCRMode = "record"
+	// VCRModeReplay answers only from the cassette and fails on a miss.
+	VCRModeReplay VCRMode = "replay"
+	// VCRModeReplayOrRecord answers from the cassette and records misses.
+	VCRModeReplayOrRecord VCRMode = "replay_or_record"
+)
+
+// VCRClientConfig defines how a VCRClient records and replays.
+type VCRClientConfig struct {
+	Mode         VCRMode
+	CassettePath string
+	// Matchers must all agree for a recorded interaction to answer a request.
+	Matchers []Matcher
+	// Redactor is applied to requests and responses before they are matched
+	// or written, so recordings made with real credentials replay with fake ones.
+	// A zero Redactor means utils.DefaultRedactor.
+	Redactor utils.Redactor
+}
+
+func DefaultVCRClientConfig(cassettePath string) VCRClientConfig {
+	return VCRClientConfig{
+		Mode:         VCRModeReplay,
+		CassettePath: cassettePath,
+		Matchers:     DefaultMatchers(),
+		Redactor:     utils.DefaultRedactor(),
+	}
+}
+
+func (c *VCRClientConfig) WithMode(mode VCRMode) *VCRClientConfig {
+	c.Mode = mode
+	return c
+}
+
+func (c *VCRClientConfig) WithMatchers(m ...Matcher) *VCRClientConfig {
+	c.Matchers = m
+	return c
+}
+
+func (c *VCRClientConfig) WithRedactor(r utils.Redactor) *VCRClientConfig {
+	c.Redactor = r
+	return c
+}
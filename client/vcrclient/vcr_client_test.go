@@ -0,0 +1,400 @@
+package vcrclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/joy-dx/gonetic/client/httpclient"
+	"github.com/joy-dx/gonetic/client/s3client"
+	"github.com/joy-dx/gonetic/client/s3client/s3test"
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
+)
+
+func newHTTPClient(t *testing.T) *httpclient.HTTPClient {
+	t.Helper()
+	netCfg := config.DefaultNetSvcConfig()
+	clientCfg := httpclient.DefaultHTTPClientConfig()
+	return httpclient.NewHTTPClient("api", &netCfg, &clientCfg)
+}
+
+func newVCR(t *testing.T, inner dto.NetClientInterface, path string, mode VCRMode) *VCRClient {
+	t.Helper()
+	cfg := DefaultVCRClientConfig(path)
+	cfg.WithMode(mode)
+	vcr, err := NewVCRClient(inner, &cfg)
+	if err != nil {
+		t.Fatalf("NewVCRClient: %v", err)
+	}
+	return vcr
+}
+
+func httpRequest(method, url string, body map[string]any, headers map[string]string) *dto.RequestConfig {
+	httpCfg := httpclient.DefaultHTTPRequestConfig()
+	httpCfg.WithMethod(method).WithURL(url)
+	if body != nil {
+		httpCfg.WithBody(body)
+	}
+	if headers != nil {
+		httpCfg.WithHeaders(headers)
+	}
+	cfg := dto.DefaultRequestConfig()
+	cfg.WithClientRef("api").WithReqConfig(&httpCfg)
+	return &cfg
+}
+
+func TestVCRClient_RecordThenReplay_Golden(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "live-session"})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `","echo":` + string(orNull(body)) + `,"access_token":"live-token"}`))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "api.json")
+	secretHeaders := map[string]string{"Authorization": "Bearer real", "X-Trace": "1"}
+	requests := []*dto.RequestConfig{
+		httpRequest(http.MethodGet, srv.URL+"/items?api_key=real", nil, secretHeaders),
+		httpRequest(http.MethodPost, srv.URL+"/items", map[string]any{"name": "a", "password": "real"}, nil),
+		httpRequest(http.MethodPost, srv.URL+"/items", map[string]any{"name": "b"}, nil),
+	}
+
+	recorder := newVCR(t, newHTTPClient(t), path, VCRModeRecord)
+	var recorded []dto.Response
+	for _, req := range requests {
+		resp, err := recorder.ProcessRequest(context.Background(), req)
+		if err != nil {
+			t.Fatalf("record: %v", err)
+		}
+		recorded = append(recorded, resp)
+	}
+	if hits.Load() != 3 {
+		t.Fatalf("hits=%d want 3", hits.Load())
+	}
+	if !strings.Contains(string(recorded[0].Body), "live-token") {
+		t.Fatalf("caller should see the live response, got %s", recorded[0].Body)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cassette: %v", err)
+	}
+	for _, secret := range []string{"Bearer real", "api_key=real", "live-session", "live-token", `"password":"real"`} {
+		if strings.Contains(string(raw), secret) {
+			t.Fatalf("cassette contains secret %q", secret)
+		}
+	}
+
+	srv.Close()
+	player := newVCR(t, newHTTPClient(t), path, VCRModeReplay)
+
+	// Replayed with a different secret: redaction makes it match anyway.
+	requests[0] = httpRequest(http.MethodGet, srv.URL+"/items?api_key=ci", nil, map[string]string{"Authorization": "Bearer ci", "X-Trace": "1"})
+	requests[1] = httpRequest(http.MethodPost, srv.URL+"/items", map[string]any{"name": "a", "password": "ci"}, nil)
+
+	cases := []struct {
+		name     string
+		req      *dto.RequestConfig
+		wantBody string
+		wantErr  error
+	}{
+		{name: "get", req: requests[0], wantBody: `"path":"/items"`},
+		{name: "post body a", req: requests[1], wantBody: `"name":"a"`},
+		{name: "post body b", req: requests[2], wantBody: `"name":"b"`},
+		{name: "repeat replays last match", req: requests[2], wantBody: `"name":"b"`},
+		{
+			name:    "miss",
+			req:     httpRequest(http.MethodDelete, srv.URL+"/items", nil, nil),
+			wantErr: ErrInteractionNotFound,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := player.ProcessRequest(context.Background(), tc.req)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("err=%v want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("replay: %v", err)
+			}
+			if resp.StatusCode != http.StatusOK || !strings.Contains(string(resp.Body), tc.wantBody) {
+				t.Fatalf("status=%d body=%s", resp.StatusCode, resp.Body)
+			}
+		})
+	}
+}
+
+func orNull(b []byte) []byte {
+	if len(b) == 0 {
+		return []byte("null")
+	}
+	return b
+}
+
+func TestVCRClient_ReplayOrRecord_Golden(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	vcr := newVCR(t, newHTTPClient(t), path, VCRModeReplayOrRecord)
+	for _, p := range []string{"/a", "/b", "/a"} {
+		if _, err := vcr.ProcessRequest(context.Background(), httpRequest(http.MethodGet, srv.URL+p, nil, nil)); err != nil {
+			t.Fatalf("%s: %v", p, err)
+		}
+	}
+	if hits.Load() != 2 {
+		t.Fatalf("hits=%d want 2", hits.Load())
+	}
+
+	// A new client loads the cassette and only records the missing request.
+	vcr = newVCR(t, newHTTPClient(t), path, VCRModeReplayOrRecord)
+	for _, p := range []string{"/a", "/c"} {
+		if _, err := vcr.ProcessRequest(context.Background(), httpRequest(http.MethodGet, srv.URL+p, nil, nil)); err != nil {
+			t.Fatalf("%s: %v", p, err)
+		}
+	}
+	if hits.Load() != 3 {
+		t.Fatalf("hits=%d want 3", hits.Load())
+	}
+	cassette, err := LoadCassette(path)
+	if err != nil || len(cassette.Interactions) != 3 {
+		t.Fatalf("cassette=%+v err=%v", cassette, err)
+	}
+}
+
+func TestVCRClient_S3_Golden(t *testing.T) {
+	srv := s3test.NewServer("bucket")
+	defer srv.Close()
+	s3Cfg := srv.ClientConfig()
+	s3Client, err := s3client.NewS3Client("s3", &s3Cfg)
+	if err != nil {
+		t.Fatalf("NewS3Client: %v", err)
+	}
+
+	do := func(vcr *VCRClient, cfg *s3client.S3RequestConfig) dto.Response {
+		t.Helper()
+		resp, err := vcr.ProcessRequest(context.Background(), (&dto.RequestConfig{}).WithReqConfig(cfg))
+		if err != nil {
+			t.Fatalf("%s: %v", cfg.Operation, err)
+		}
+		return resp
+	}
+	put := &s3client.S3RequestConfig{Operation: s3client.S3OpPut, Bucket: "bucket", Key: "k", Body: []byte("payload")}
+	get := &s3client.S3RequestConfig{Operation: s3client.S3OpGet, Bucket: "bucket", Key: "k", Stream: true}
+
+	path := filepath.Join(t.TempDir(), "s3.json")
+	recorder := newVCR(t, s3Client, path, VCRModeRecord)
+	do(recorder, put)
+	resp := do(recorder, get)
+	if got, _ := io.ReadAll(resp.Stream); string(got) != "payload" {
+		t.Fatalf("recorded stream=%q", got)
+	}
+
+	srv.Close()
+	player := newVCR(t, s3Client, path, VCRModeReplay)
+	do(player, put)
+	resp = do(player, get)
+	if resp.Stream == nil {
+		t.Fatalf("replayed response should stream")
+	}
+	if got, _ := io.ReadAll(resp.Stream); string(got) != "payload" {
+		t.Fatalf("replayed stream=%q", got)
+	}
+}
+
+func TestVCRClient_S3_DestinationPath_Golden(t *testing.T) {
+	srv := s3test.NewServer("bucket")
+	defer srv.Close()
+	srv.PutObject("bucket", "k", []byte("payload"))
+	s3Cfg := srv.ClientConfig()
+	s3Client, err := s3client.NewS3Client("s3", &s3Cfg)
+	if err != nil {
+		t.Fatalf("NewS3Client: %v", err)
+	}
+	get := func(vcr *VCRClient, dest string) {
+		t.Helper()
+		cfg := &s3client.S3RequestConfig{Operation: s3client.S3OpGet, Bucket: "bucket", Key: "k", DestinationPath: dest}
+		if _, err := vcr.ProcessRequest(context.Background(), (&dto.RequestConfig{}).WithReqConfig(cfg)); err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if got, err := os.ReadFile(dest); err != nil || string(got) != "payload" {
+			t.Fatalf("destination=%q err=%v", got, err)
+		}
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "s3.json")
+	get(newVCR(t, s3Client, path, VCRModeRecord), filepath.Join(dir, "recorded", "k"))
+
+	// Replay writes the recorded body to the live destination.
+	srv.Close()
+	get(newVCR(t, s3Client, path, VCRModeReplay), filepath.Join(dir, "replayed", "k"))
+}
+
+type errClient struct{ err error }
+
+func (c errClient) Ref() string             { return "api" }
+func (c errClient) Type() dto.NetClientType { return "http" }
+func (c errClient) ProcessRequest(context.Context, *dto.RequestConfig) (dto.Response, error) {
+	return dto.Response{}, c.err
+}
+
+func TestVCRClient_ReplayError_Golden(t *testing.T) {
+	cases := []struct {
+		name          string
+		err           error
+		wantTemporary bool
+	}{
+		{name: "temporary", err: errors.New("connection reset"), wantTemporary: true},
+		{name: "permanent", err: &httpclient.PinMismatchError{Host: "example.com"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cassette.json")
+			req := httpRequest(http.MethodGet, "https://example.com/", nil, nil)
+			if _, err := newVCR(t, errClient{err: tc.err}, path, VCRModeRecord).ProcessRequest(context.Background(), req); err != tc.err {
+				t.Fatalf("record err=%v", err)
+			}
+
+			_, err := newVCR(t, errClient{}, path, VCRModeReplay).ProcessRequest(context.Background(), req)
+			if err == nil || err.Error() != tc.err.Error() {
+				t.Fatalf("replay err=%v want %q", err, tc.err)
+			}
+			if got := utils.IsTemporaryErr(err); got != tc.wantTemporary {
+				t.Fatalf("temporary=%v want %v", got, tc.wantTemporary)
+			}
+		})
+	}
+}
+
+func TestNewVCRClient_Errors_Golden(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	cases := []struct {
+		name string
+		mode VCRMode
+		path string
+	}{
+		{name: "replay without cassette", mode: VCRModeReplay, path: missing},
+		{name: "unknown mode", mode: "rewind", path: missing},
+		{name: "no path", mode: VCRModeRecord},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := DefaultVCRClientConfig(tc.path)
+			cfg.WithMode(tc.mode)
+			if _, err := NewVCRClient(newHTTPClient(t), &cfg); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+	t.Run("nil config", func(t *testing.T) {
+		if _, err := NewVCRClient(newHTTPClient(t), nil); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestVCRClient_ZeroRedactorUsesDefault_Golden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	vcr, err := NewVCRClient(errClient{err: errors.New("connection reset")}, &VCRClientConfig{
+		Mode:         VCRModeRecord,
+		CassettePath: path,
+		Matchers:     DefaultMatchers(),
+	})
+	if err != nil {
+		t.Fatalf("NewVCRClient: %v", err)
+	}
+	req := httpRequest(http.MethodPost, "https://example.com/?api_key=real", map[string]any{"password": "real"},
+		map[string]string{"Authorization": "Bearer real"})
+	if _, err := vcr.ProcessRequest(context.Background(), req); err == nil {
+		t.Fatalf("expected recorded error")
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("load cassette: %v", err)
+	}
+	recorded := cassette.Interactions[0].Request
+	written := recorded.URL + recorded.Headers["Authorization"] + string(recorded.Body)
+	for _, secret := range []string{"Bearer real", "api_key=real", `"password":"real"`} {
+		if strings.Contains(written, secret) {
+			t.Fatalf("cassette contains secret %q", secret)
+		}
+	}
+}
+
+func TestVCRClient_AppendMatchesSave_Golden(t *testing.T) {
+	compact := func(t *testing.T, path string) {
+		raw, err := json.Marshal(Cassette{Version: cassetteVersion, Interactions: []Interaction{{
+			Request: CassetteRequest{ClientType: "http", Method: http.MethodGet, URL: "https://example.com/0"},
+		}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, raw, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cases := []struct {
+		name  string
+		mode  VCRMode
+		setup func(t *testing.T, path string)
+		want  int
+	}{
+		{name: "record over a stale cassette", mode: VCRModeRecord, setup: compact, want: 3},
+		{name: "record into a new cassette", mode: VCRModeReplayOrRecord, want: 3},
+		{name: "append to a hand edited cassette", mode: VCRModeReplayOrRecord, setup: compact, want: 4},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "cassette.json")
+			if tc.setup != nil {
+				tc.setup(t, path)
+			}
+			vcr := newVCR(t, errClient{err: errors.New("connection reset")}, path, tc.mode)
+			for i := 1; i <= 3; i++ {
+				req := httpRequest(http.MethodGet, fmt.Sprintf("https://example.com/%d", i), nil, nil)
+				if _, err := vcr.ProcessRequest(context.Background(), req); err == nil {
+					t.Fatalf("expected recorded error")
+				}
+			}
+
+			want := vcr.Cassette()
+			if len(want.Interactions) != tc.want {
+				t.Fatalf("interactions=%d want %d", len(want.Interactions), tc.want)
+			}
+			wantPath := filepath.Join(dir, "saved.json")
+			if err := want.Save(wantPath); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := os.ReadFile(path)
+			saved, _ := os.ReadFile(wantPath)
+			if !bytes.Equal(got, saved) {
+				t.Fatalf("appended cassette differs from Save:\n%s\nwant\n%s", got, saved)
+			}
+		})
+	}
+}
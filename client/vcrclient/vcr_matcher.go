@@ -0,0 +1,49 @@
+package vcrclient
+
+import "net/http"
+
+// Matcher reports whether a recorded request may answer a live one. Both are
+// redacted the same way before comparison.
+type Matcher func(live, recorded CassetteRequest) bool
+
+// DefaultMatchers match on client type, method, URL and body hash.
+func DefaultMatchers() []Matcher {
+	return []Matcher{MatchClientType, MatchMethod, MatchURL, MatchBodyHash}
+}
+
+func MatchClientType(live, recorded CassetteRequest) bool {
+	return live.ClientType == recorded.ClientType
+}
+
+func MatchMethod(live, recorded CassetteRequest) bool {
+	return live.Method == recorded.Method
+}
+
+func MatchURL(live, recorded CassetteRequest) bool {
+	return live.URL == recorded.URL
+}
+
+func MatchBodyHash(live, recorded CassetteRequest) bool {
+	return live.BodySHA256 == recorded.BodySHA256
+}
+
+// MatchHeaders compares the named request headers.
+func MatchHeaders(names ...string) Matcher {
+	return func(live, recorded CassetteRequest) bool {
+		for _, name := range names {
+			if headerValue(live.Headers, name) != headerValue(recorded.Headers, name) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func headerValue(h map[string]string, name string) string {
+	for k, v := range h {
+		if http.CanonicalHeaderKey(k) == http.CanonicalHeaderKey(name) {
+			return v
+		}
+	}
+	return ""
+}
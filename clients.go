@@ -0,0 +1,82 @@
+package gonetic
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+
+	// Register the HTTP client type. Other built-in types, such as S3, are
+	// opt-in through gonetic/clients/all so their SDKs stay out of the build.
+	_ "github.com/joy-dx/gonetic/client/httpclient"
+)
+
+// RequestConfigFor returns dto.DefaultRequestConfig aimed at ref, with the
+// timeout and retry defaults of the client declared under ref, if any.
+func (s *NetSvc) RequestConfigFor(ref string) dto.RequestConfig {
+	cfg := dto.DefaultRequestConfig()
+	cfg.WithClientRef(ref)
+	if declared, ok := s.declaredClient(ref); ok {
+		if declared.Timeout > 0 {
+			cfg.WithTimeout(declared.Timeout)
+		}
+		if declared.MaxRetries != nil {
+			cfg.WithMaxRetries(*declared.MaxRetries)
+		}
+	}
+	return cfg
+}
+
+// declaredClient returns the NetSvcConfig.Clients entry for ref, if any.
+func (s *NetSvc) declaredClient(ref string) (*config.ClientConfig, bool) {
+	for i := range s.cfg.Clients {
+		if s.cfg.Clients[i].Ref == ref {
+			return &s.cfg.Clients[i], true
+		}
+	}
+	return nil, false
+}
+
+// requestTimeout is cfg.Timeout, or the declared client's timeout when the
+// request left it unset (see dto.RequestConfig.TimeoutSet).
+func (s *NetSvc) requestTimeout(cfg *dto.RequestConfig) time.Duration {
+	if !cfg.TimeoutSet() {
+		if declared, ok := s.declaredClient(cfg.ClientRef); ok && declared.Timeout > 0 {
+			return declared.Timeout
+		}
+	}
+	return cfg.Timeout
+}
+
+// maxRetries is cfg.MaxRetries, or the declared client's max_retries when the
+// request left it unset. A negative MaxRetries disables retries.
+func (s *NetSvc) maxRetries(cfg *dto.RequestConfig) int {
+	if !cfg.MaxRetriesSet() {
+		if declared, ok := s.declaredClient(cfg.ClientRef); ok && declared.MaxRetries != nil {
+			return *declared.MaxRetries
+		}
+	}
+	return max(cfg.MaxRetries, 0)
+}
+
+// registerDeclaredClients builds every client in NetSvcConfig.Clients with
+// the factory registered for its type and registers it.
+func (s *NetSvc) registerDeclaredClients() error {
+	if err := s.cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid net config: %w", err)
+	}
+	for i := range s.cfg.Clients {
+		declared := &s.cfg.Clients[i]
+		factory, ok := config.LookupClientType(declared.Type)
+		if !ok {
+			return fmt.Errorf("client %s: unknown client type %q (import its client package or gonetic/clients/all)", declared.Ref, declared.Type)
+		}
+		client, err := factory.New(s.cfg, declared)
+		if err != nil {
+			return fmt.Errorf("client %s: %w", declared.Ref, err)
+		}
+		s.RegisterClient(declared.Ref, client)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+// Package all registers every built-in client type with
+// config.RegisterClientType. Import it for its side effect when declarative
+// config uses the S3 client type without importing client/s3client:
+//
+//	import _ "github.com/joy-dx/gonetic/clients/all"
+//
+// The HTTP client type is always registered by the gonetic package itself.
+package all
+
+import (
+	_ "github.com/joy-dx/gonetic/client/httpclient"
+	_ "github.com/joy-dx/gonetic/client/s3client"
+)
@@ -0,0 +1,304 @@
+package gonetic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joy-dx/gonetic/client/httpclient"
+	"github.com/joy-dx/gonetic/client/s3client"
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+func TestNetSvc_HydrateDeclaredClients_Golden(t *testing.T) {
+	var gotAuth, gotPath, gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Get("X-Team")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	t.Setenv("GONETIC_TEST_API_TOKEN", "s3cret")
+
+	retries := 0
+	cfg := config.DefaultNetSvcConfig()
+	cfg.WithRelay(&fakeRelay{}).
+		WithClient(config.ClientConfig{
+			Ref:        "api",
+			Type:       config.ClientTypeHTTP,
+			BaseURL:    srv.URL + "/v1/",
+			Headers:    map[string]string{"X-Team": "net"},
+			Timeout:    5 * time.Second,
+			MaxRetries: &retries,
+			Auth:       config.AuthConfig{Mode: config.AuthModeBearer, Token: "env:GONETIC_TEST_API_TOKEN"},
+		}).
+		WithClient(config.ClientConfig{
+			Ref:         "store",
+			Type:        config.ClientTypeS3,
+			Region:      "eu-west-1",
+			Endpoint:    "http://127.0.0.1:9000",
+			Credentials: config.CredentialsConfig{Source: config.CredentialsSourceStatic, AccessKeyID: "id", SecretAccessKey: "secret"},
+		})
+	s, err := New(&cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer s.Close()
+	if err := s.Hydrate(context.Background()); err != nil {
+		t.Fatalf("Hydrate: %v", err)
+	}
+
+	if c, ok := s.Client("api"); !ok || c.Type() != httpclient.NetClientHTTPRef {
+		t.Fatalf("api client not registered")
+	}
+	if c, ok := s.Client("store"); !ok || c.Type() != s3client.NetClientS3Ref {
+		t.Fatalf("store client not registered")
+	}
+
+	reqCfg := s.RequestConfigFor("api")
+	if reqCfg.Timeout != 5*time.Second || reqCfg.MaxRetries != 0 {
+		t.Fatalf("request defaults not applied: %+v", reqCfg)
+	}
+	httpCfg := httpclient.DefaultHTTPRequestConfig()
+	httpCfg.WithURL("users")
+	reqCfg.WithReqConfig(&httpCfg)
+	if _, err := s.RequestOnce(context.Background(), &reqCfg); err != nil {
+		t.Fatalf("RequestOnce: %v", err)
+	}
+	if gotAuth != "Bearer s3cret" || gotPath != "/v1/users" || gotHeader != "net" {
+		t.Fatalf("auth=%q path=%q header=%q", gotAuth, gotPath, gotHeader)
+	}
+}
+
+func TestNetSvc_DeclaredClientDefaults_Golden(t *testing.T) {
+	t.Parallel()
+
+	noRetries := 0
+	tests := []struct {
+		name      string
+		send      func(s *NetSvc) error
+		slow      bool
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name: "get takes declared max_retries",
+			send: func(s *NetSvc) error {
+				_, err := s.Get(context.Background(), "https://api.example.com/x", true)
+				return err
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "get takes declared timeout",
+			send: func(s *NetSvc) error {
+				_, err := s.Get(context.Background(), "https://api.example.com/x", true)
+				return err
+			},
+			slow:      true,
+			wantCalls: 1,
+			wantErr:   context.DeadlineExceeded,
+		},
+		{
+			name: "explicit retries win even at the default value",
+			send: func(s *NetSvc) error {
+				req := testRequest(dto.NET_DEFAULT_CLIENT_REF)
+				req.WithMaxRetries(dto.DefaultMaxRetries)
+				_, err := s.RequestWithRetry(context.Background(), req)
+				return err
+			},
+			wantCalls: dto.DefaultMaxRetries + 1,
+		},
+		{
+			name: "non-default retries win",
+			send: func(s *NetSvc) error {
+				req := testRequest(dto.NET_DEFAULT_CLIENT_REF)
+				req.MaxRetries = 1
+				_, err := s.RequestWithRetry(context.Background(), req)
+				return err
+			},
+			wantCalls: 2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.DefaultNetSvcConfig()
+			cfg.WithRelay(&fakeRelay{})
+			cfg.WithClient(config.ClientConfig{Ref: dto.NET_DEFAULT_CLIENT_REF, Type: config.ClientTypeHTTP, Timeout: 20 * time.Millisecond, MaxRetries: &noRetries})
+			s, err := New(&cfg)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			defer s.Close()
+			client := &fakeNetClient{ref: dto.NET_DEFAULT_CLIENT_REF, typ: httpclient.NetClientHTTPRef, fn: func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+				if tt.slow {
+					<-ctx.Done()
+					return dto.Response{}, ctx.Err()
+				}
+				return dto.Response{StatusCode: http.StatusBadGateway}, nil
+			}}
+			s.RegisterClient(dto.NET_DEFAULT_CLIENT_REF, client)
+
+			err = tt.send(s)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err=%v want %v", err, tt.wantErr)
+			}
+			if client.call != tt.wantCalls {
+				t.Fatalf("calls=%d want %d", client.call, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestNetSvc_HydrateDeclaredClients_Errors_Golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		client  config.ClientConfig
+		wantErr string
+	}{
+		{name: "unknown type", client: config.ClientConfig{Ref: "x", Type: "ftp"}, wantErr: `unknown client type "ftp"`},
+		{
+			name:    "unresolvable secret",
+			client:  config.ClientConfig{Ref: "x", Type: config.ClientTypeHTTP, Auth: config.AuthConfig{Mode: config.AuthModeBearer, Token: "env:GONETIC_TEST_UNSET_TOKEN"}},
+			wantErr: "GONETIC_TEST_UNSET_TOKEN not set",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := config.DefaultNetSvcConfig()
+			cfg.WithRelay(&fakeRelay{}).WithClient(tt.client)
+			s, err := New(&cfg)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			err = s.Hydrate(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err=%v want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+const echoClientType dto.NetClientType = "test.client.echo"
+
+type echoReqConfig struct {
+	Message string `json:"message"`
+}
+
+func (c *echoReqConfig) Ref() dto.NetClientType                      { return echoClientType }
+func (c *echoReqConfig) NewRequest(ctx context.Context) (any, error) { return c, nil }
+
+var registerEchoOnce sync.Once
+
+// registerEcho plugs a third-party style client into the factory registry.
+func registerEcho() {
+	registerEchoOnce.Do(func() {
+		config.RegisterClientType(config.ClientFactory{
+			Type: echoClientType,
+			Name: "echo",
+			New: func(netCfg *config.NetSvcConfig, declared *config.ClientConfig) (dto.NetClientInterface, error) {
+				prefix, _ := declared.Options["prefix"].(string)
+				return &fakeNetClient{ref: declared.Ref, typ: echoClientType, fn: func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+					return dto.Response{StatusCode: 200, Body: []byte(prefix + cfg.ReqConfig.(*echoReqConfig).Message)}, nil
+				}}, nil
+			},
+			Validate: func(declared *config.ClientConfig) error {
+				if _, ok := declared.Options["prefix"]; !ok {
+					return errors.New("prefix option required")
+				}
+				return nil
+			},
+			NewReqConfig: func() dto.ReqConfigInterface { return &echoReqConfig{} },
+		})
+	})
+}
+
+func TestNetSvc_ClientFactoryRegistry_Golden(t *testing.T) {
+	t.Parallel()
+	registerEcho()
+
+	tests := []struct {
+		name     string
+		client   config.ClientConfig
+		wantErr  string
+		wantBody string
+	}{
+		{name: "by name", client: config.ClientConfig{Ref: "e", Type: "echo", Options: map[string]any{"prefix": "> "}}, wantBody: "> hi"},
+		{name: "by full type", client: config.ClientConfig{Ref: "e", Type: string(echoClientType), Options: map[string]any{"prefix": ""}}, wantBody: "hi"},
+		{name: "factory validation", client: config.ClientConfig{Ref: "e", Type: "echo"}, wantErr: "prefix option required"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := config.DefaultNetSvcConfig()
+			cfg.WithRelay(&fakeRelay{}).WithClient(tt.client)
+			s, err := New(&cfg)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			err = s.Hydrate(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err=%v want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Hydrate: %v", err)
+			}
+
+			reqConfig, err := dto.DecodeReqConfig(echoClientType, []byte(`{"message":"hi"}`))
+			if err != nil {
+				t.Fatalf("DecodeReqConfig: %v", err)
+			}
+			reqCfg := s.RequestConfigFor("e")
+			reqCfg.WithReqConfig(reqConfig)
+			resp, err := s.RequestOnce(context.Background(), &reqCfg)
+			if err != nil || string(resp.Body) != tt.wantBody {
+				t.Fatalf("body=%q err=%v want %q", resp.Body, err, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestClientTypes_BuiltIn_Golden(t *testing.T) {
+	t.Parallel()
+
+	for _, clientType := range []dto.NetClientType{httpclient.NetClientHTTPRef, s3client.NetClientS3Ref} {
+		if _, ok := config.LookupClientType(string(clientType)); !ok {
+			t.Fatalf("%s not registered", clientType)
+		}
+		if _, err := dto.NewReqConfig(clientType); err != nil {
+			t.Fatalf("NewReqConfig(%s): %v", clientType, err)
+		}
+	}
+	if _, err := dto.NewReqConfig("test.client.unknown"); err == nil {
+		t.Fatalf("expected error for unregistered type")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("duplicate registration did not panic")
+		}
+	}()
+	config.RegisterClientType(config.ClientFactory{Type: "test.client.dup", Name: config.ClientTypeHTTP, New: func(*config.NetSvcConfig, *config.ClientConfig) (dto.NetClientInterface, error) {
+		return nil, nil
+	}})
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Names of the built-in client types accepted in ClientConfig.Type. Other
+// types become available when their package registers a ClientFactory.
+const (
+	ClientTypeHTTP = "http"
+	ClientTypeS3   = "s3"
+)
+
+// Auth modes accepted in AuthConfig.Mode. An empty mode means none.
+const (
+	AuthModeNone              = "none"
+	AuthModeBearer            = "bearer"
+	AuthModeBasic             = "basic"
+	AuthModeClientCredentials = "oauth2_client_credentials"
+)
+
+// Credential sources accepted in CredentialsConfig.Source. An empty source
+// uses the default AWS credential chain.
+const (
+	CredentialsSourceDefault = "default"
+	CredentialsSourceStatic  = "static"
+)
+
+// Secret is a config value that may be given literally or as a reference:
+// "env:NAME" reads an environment variable and "file:/path" reads a file,
+// trimming trailing newlines. It formats as REDACTED so it stays out of logs.
+type Secret string
+
+// Resolve returns the secret value.
+func (s Secret) Resolve() (string, error) {
+	raw := string(s)
+	switch {
+	case strings.HasPrefix(raw, "env:"):
+		name := strings.TrimPrefix(raw, "env:")
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("secret env var %s not set", name)
+		}
+		return value, nil
+	case strings.HasPrefix(raw, "file:"):
+		path := strings.TrimPrefix(raw, "file:")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("read secret file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	default:
+		return raw, nil
+	}
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return "REDACTED"
+}
+
+func (s Secret) validate() error {
+	raw := string(s)
+	if raw == "env:" || raw == "file:" {
+		return fmt.Errorf("secret reference %q has no name", raw)
+	}
+	return nil
+}
+
+// AuthConfig selects how an HTTP client authenticates.
+type AuthConfig struct {
+	Mode     string `json:"mode,omitempty" yaml:"mode,omitempty" mapstructure:"mode"`
+	Token    Secret `json:"token,omitempty" yaml:"token,omitempty" mapstructure:"token"`
+	Username string `json:"username,omitempty" yaml:"username,omitempty" mapstructure:"username"`
+	Password Secret `json:"password,omitempty" yaml:"password,omitempty" mapstructure:"password"`
+	// TokenURL, ClientID, ClientSecret and Scopes configure oauth2_client_credentials.
+	TokenURL     string   `json:"token_url,omitempty" yaml:"token_url,omitempty" mapstructure:"token_url"`
+	ClientID     string   `json:"client_id,omitempty" yaml:"client_id,omitempty" mapstructure:"client_id"`
+	ClientSecret Secret   `json:"client_secret,omitempty" yaml:"client_secret,omitempty" mapstructure:"client_secret"`
+	Scopes       []string `json:"scopes,omitempty" yaml:"scopes,omitempty" mapstructure:"scopes"`
+}
+
+// CredentialsConfig selects where an S3 client gets AWS credentials from.
+type CredentialsConfig struct {
+	Source          string `json:"source,omitempty" yaml:"source,omitempty" mapstructure:"source"`
+	AccessKeyID     Secret `json:"access_key_id,omitempty" yaml:"access_key_id,omitempty" mapstructure:"access_key_id"`
+	SecretAccessKey Secret `json:"secret_access_key,omitempty" yaml:"secret_access_key,omitempty" mapstructure:"secret_access_key"`
+	SessionToken    Secret `json:"session_token,omitempty" yaml:"session_token,omitempty" mapstructure:"session_token"`
+}
+
+// ClientConfig declares a named client that NetSvc.Hydrate registers under Ref.
+type ClientConfig struct {
+	Ref  string `json:"ref" yaml:"ref" mapstructure:"ref"`
+	Type string `json:"type" yaml:"type" mapstructure:"type"`
+	// Timeout and MaxRetries are the request defaults returned by
+	// NetSvc.RequestConfigFor, and apply to requests to Ref that leave them
+	// unset (dto.RequestConfig.TimeoutSet). Unset values keep
+	// dto.DefaultRequestConfig.
+	Timeout    time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty" mapstructure:"timeout"`
+	MaxRetries *int          `json:"max_retries,omitempty" yaml:"max_retries,omitempty" mapstructure:"max_retries"`
+	// HTTP
+	BaseURL string            `json:"base_url,omitempty" yaml:"base_url,omitempty" mapstructure:"base_url"`
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty" mapstructure:"headers"`
+	Auth    AuthConfig        `json:"auth,omitempty" yaml:"auth,omitempty" mapstructure:"auth"`
+	TLS     TLSConfig         `json:"tls,omitempty" yaml:"tls,omitempty" mapstructure:"tls"`
+	// Proxy overrides NetSvcConfig.Proxy for this client
+	Proxy    *ProxyConfig   `json:"proxy,omitempty" yaml:"proxy,omitempty" mapstructure:"proxy"`
+	Resolver ResolverConfig `json:"resolver,omitempty" yaml:"resolver,omitempty" mapstructure:"resolver"`
+	// SocketPath sends every request over a Unix domain socket
+	SocketPath string          `json:"socket_path,omitempty" yaml:"socket_path,omitempty" mapstructure:"socket_path"`
+	Transport  TransportConfig `json:"transport,omitempty" yaml:"transport,omitempty" mapstructure:"transport"`
+	// Timing collects dto.ResponseTiming for every response
+	Timing bool `json:"timing,omitempty" yaml:"timing,omitempty" mapstructure:"timing"`
+	// S3
+	Region         string            `json:"region,omitempty" yaml:"region,omitempty" mapstructure:"region"`
+	Endpoint       string            `json:"endpoint,omitempty" yaml:"endpoint,omitempty" mapstructure:"endpoint"`
+	ForcePathStyle bool              `json:"force_path_style,omitempty" yaml:"force_path_style,omitempty" mapstructure:"force_path_style"`
+	Credentials    CredentialsConfig `json:"credentials,omitempty" yaml:"credentials,omitempty" mapstructure:"credentials"`
+	// Options carries settings for client types registered outside gonetic
+	Options map[string]any `json:"options,omitempty" yaml:"options,omitempty" mapstructure:"options"`
+}
+
+// Validate checks that the type is registered and runs its factory's checks.
+func (c *ClientConfig) Validate() error {
+	if c.Ref == "" {
+		return errors.New("client ref required")
+	}
+	var errs []error
+	factory, ok := LookupClientType(c.Type)
+	switch {
+	case !ok:
+		errs = append(errs, fmt.Errorf("unknown client type %q", c.Type))
+	case factory.Validate != nil:
+		errs = append(errs, factory.Validate(c))
+	}
+	if c.MaxRetries != nil && *c.MaxRetries < 0 {
+		errs = append(errs, errors.New("max_retries must not be negative"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("client %s: %w", c.Ref, err)
+	}
+	return nil
+}
+
+// Validate checks the mode and the fields it requires.
+func (a *AuthConfig) Validate() error {
+	switch a.Mode {
+	case "", AuthModeNone:
+		return nil
+	case AuthModeBearer:
+		if a.Token == "" {
+			return errors.New("bearer auth requires token")
+		}
+		return a.Token.validate()
+	case AuthModeBasic:
+		if a.Username == "" {
+			return errors.New("basic auth requires username")
+		}
+		return a.Password.validate()
+	case AuthModeClientCredentials:
+		if a.TokenURL == "" || a.ClientID == "" {
+			return errors.New("oauth2_client_credentials requires token_url and client_id")
+		}
+		return a.ClientSecret.validate()
+	default:
+		return fmt.Errorf("unknown auth mode %q", a.Mode)
+	}
+}
+
+// Validate checks the source and the fields it requires.
+func (c *CredentialsConfig) Validate() error {
+	switch c.Source {
+	case "", CredentialsSourceDefault:
+		return nil
+	case CredentialsSourceStatic:
+		if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+			return errors.New("static credentials require access_key_id and secret_access_key")
+		}
+		return errors.Join(c.AccessKeyID.validate(), c.SecretAccessKey.validate(), c.SessionToken.validate())
+	default:
+		return fmt.Errorf("unknown credentials source %q", c.Source)
+	}
+}
@@ -0,0 +1,232 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/joy-dx/gonetic/client/httpclient"
+	_ "github.com/joy-dx/gonetic/client/s3client"
+	"github.com/joy-dx/gonetic/config"
+)
+
+func TestNetSvcConfig_Validate_Golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		clients []config.ClientConfig
+		wantErr string
+	}{
+		{name: "no clients"},
+		{
+			name: "http and s3",
+			clients: []config.ClientConfig{
+				{Ref: "api", Type: config.ClientTypeHTTP, Auth: config.AuthConfig{Mode: config.AuthModeBearer, Token: "env:API_TOKEN"}},
+				{Ref: "store", Type: config.ClientTypeS3, Region: "eu-west-1", Credentials: config.CredentialsConfig{Source: config.CredentialsSourceStatic, AccessKeyID: "id", SecretAccessKey: "file:/run/secret"}},
+			},
+		},
+		{name: "unknown type", clients: []config.ClientConfig{{Ref: "x", Type: "ftp"}}, wantErr: `client x: unknown client type "ftp"`},
+		{name: "missing ref", clients: []config.ClientConfig{{Type: config.ClientTypeHTTP}}, wantErr: "client ref required"},
+		{name: "duplicate ref", clients: []config.ClientConfig{{Ref: "a", Type: config.ClientTypeHTTP}, {Ref: "a", Type: config.ClientTypeHTTP}}, wantErr: "client a: duplicate ref"},
+		{name: "unknown auth mode", clients: []config.ClientConfig{{Ref: "a", Type: config.ClientTypeHTTP, Auth: config.AuthConfig{Mode: "digest"}}}, wantErr: `unknown auth mode "digest"`},
+		{name: "bearer without token", clients: []config.ClientConfig{{Ref: "a", Type: config.ClientTypeHTTP, Auth: config.AuthConfig{Mode: config.AuthModeBearer}}}, wantErr: "bearer auth requires token"},
+		{name: "empty env reference", clients: []config.ClientConfig{{Ref: "a", Type: config.ClientTypeHTTP, Auth: config.AuthConfig{Mode: config.AuthModeBearer, Token: "env:"}}}, wantErr: "has no name"},
+		{name: "s3 without region", clients: []config.ClientConfig{{Ref: "s", Type: config.ClientTypeS3}}, wantErr: "region required"},
+		{name: "unknown credentials source", clients: []config.ClientConfig{{Ref: "s", Type: config.ClientTypeS3, Region: "r", Credentials: config.CredentialsConfig{Source: "vault"}}}, wantErr: `unknown credentials source "vault"`},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := config.DefaultNetSvcConfig()
+			for _, c := range tt.clients {
+				cfg.WithClient(c)
+			}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err=%v want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSecret_Resolve_Golden(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(secretFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GONETIC_TEST_SECRET", "from-env")
+
+	tests := []struct {
+		name    string
+		secret  config.Secret
+		want    string
+		wantErr bool
+	}{
+		{name: "literal", secret: "plain", want: "plain"},
+		{name: "env", secret: "env:GONETIC_TEST_SECRET", want: "from-env"},
+		{name: "missing env", secret: "env:GONETIC_TEST_UNSET", wantErr: true},
+		{name: "file", secret: config.Secret("file:" + secretFile), want: "from-file"},
+		{name: "missing file", secret: config.Secret("file:" + filepath.Join(dir, "nope")), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.secret.Resolve()
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Fatalf("got=%q err=%v want %q wantErr=%v", got, err, tt.want, tt.wantErr)
+			}
+			if tt.secret.String() != "REDACTED" {
+				t.Fatalf("secret not redacted when formatted")
+			}
+		})
+	}
+}
+
+func TestLoadNetSvcConfig_Golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr bool
+		check   func(t *testing.T, cfg config.NetSvcConfig)
+	}{
+		{
+			name: "yaml",
+			file: "net.yaml",
+			content: `
+user_agent: svc/1.0
+request_timeout: 15s
+clients:
+  - ref: api
+    type: http
+    base_url: https://api.example.com/v1/
+    timeout: 5s
+    max_retries: 1
+    auth:
+      mode: bearer
+      token: env:API_TOKEN
+  - ref: store
+    type: s3
+    region: eu-west-1
+    endpoint: http://localhost:9000
+    force_path_style: true
+`,
+			check: func(t *testing.T, cfg config.NetSvcConfig) {
+				if cfg.UserAgent != "svc/1.0" || cfg.RequestTimeout != 15*time.Second {
+					t.Fatalf("service fields not decoded: %+v", cfg)
+				}
+				if len(cfg.Clients) != 2 {
+					t.Fatalf("clients=%d want 2", len(cfg.Clients))
+				}
+				api := cfg.Clients[0]
+				if api.Timeout != 5*time.Second || api.MaxRetries == nil || *api.MaxRetries != 1 || api.Auth.Token != "env:API_TOKEN" {
+					t.Fatalf("api client=%+v", api)
+				}
+				if !cfg.Clients[1].ForcePathStyle || cfg.DownloadCallbackInterval != 2*time.Second {
+					t.Fatalf("store client or defaults not kept: %+v", cfg)
+				}
+			},
+		},
+		{
+			name:    "json",
+			file:    "net.json",
+			content: `{"clients":[{"ref":"api","type":"http","base_url":"https://api.example.com/"}]}`,
+			check: func(t *testing.T, cfg config.NetSvcConfig) {
+				if len(cfg.Clients) != 1 || cfg.Clients[0].BaseURL != "https://api.example.com/" {
+					t.Fatalf("clients=%+v", cfg.Clients)
+				}
+			},
+		},
+		{name: "unknown type", file: "net.yaml", content: "clients:\n  - ref: x\n    type: ftp\n", wantErr: true},
+		{name: "unknown field", file: "net.json", content: `{"clientz":[]}`, wantErr: true},
+		{name: "unsupported extension", file: "net.toml", content: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := config.LoadNetSvcConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
+
+func TestNetSvcConfig_ApplyEnv_Golden(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg config.NetSvcConfig)
+	}{
+		{
+			name: "overlays file values",
+			env: map[string]string{
+				"GONETIC_USER_AGENT":        "env/2.0",
+				"GONETIC_REQUEST_TIMEOUT":   "45s",
+				"GONETIC_LOG_BODIES":        "true",
+				"GONETIC_WHITELIST_DOMAINS": "a.example.com, b.example.com",
+			},
+			check: func(t *testing.T, cfg config.NetSvcConfig) {
+				if cfg.UserAgent != "env/2.0" || cfg.RequestTimeout != 45*time.Second || !cfg.LogBodies {
+					t.Fatalf("env not applied: %+v", cfg)
+				}
+				if len(cfg.WhitelistDomains) != 2 || cfg.WhitelistDomains[1] != "b.example.com" {
+					t.Fatalf("whitelist=%v", cfg.WhitelistDomains)
+				}
+			},
+		},
+		{
+			name: "unset keeps file values",
+			check: func(t *testing.T, cfg config.NetSvcConfig) {
+				if cfg.UserAgent != "file/1.0" || cfg.RequestTimeout != 15*time.Second {
+					t.Fatalf("file values lost: %+v", cfg)
+				}
+			},
+		},
+		{name: "bad duration", env: map[string]string{"GONETIC_REQUEST_TIMEOUT": "soon"}, wantErr: true},
+		{name: "bad bool", env: map[string]string{"GONETIC_PREFER_CURL_DOWNLOADS": "maybe"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			path := filepath.Join(t.TempDir(), "net.yaml")
+			if err := os.WriteFile(path, []byte("user_agent: file/1.0\nrequest_timeout: 15s\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := config.LoadNetSvcConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
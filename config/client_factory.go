@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// ClientFactory lets a client package plug into declarative configuration.
+// Client packages register one from init; importing the package is enough to
+// make its type available to NetSvcConfig.Clients.
+type ClientFactory struct {
+	// Type is the NetClientType the built clients report
+	Type dto.NetClientType
+	// Name is the short form accepted in ClientConfig.Type, e.g. "http".
+	// ClientConfig.Type may also use the full Type.
+	Name string
+	// New builds a client from its declaration. Secrets are resolved here.
+	New func(netCfg *NetSvcConfig, client *ClientConfig) (dto.NetClientInterface, error)
+	// Validate checks type specific fields. Optional.
+	Validate func(client *ClientConfig) error
+	// NewReqConfig returns an empty ReqConfig for decoding requests aimed at
+	// this type. Optional; registered with dto.RegisterReqConfig.
+	NewReqConfig func() dto.ReqConfigInterface
+}
+
+var clientFactories = struct {
+	sync.RWMutex
+	byKey map[string]ClientFactory
+	all   []ClientFactory
+}{byKey: map[string]ClientFactory{}}
+
+// RegisterClientType makes a client type available to declarative config. It
+// panics if New is nil or the type or name is already registered.
+func RegisterClientType(factory ClientFactory) {
+	if factory.Type == "" || factory.New == nil {
+		panic("config: RegisterClientType requires Type and New")
+	}
+	clientFactories.Lock()
+	defer clientFactories.Unlock()
+
+	keys := []string{string(factory.Type)}
+	if factory.Name != "" {
+		keys = append(keys, factory.Name)
+	}
+	for _, key := range keys {
+		if _, dup := clientFactories.byKey[key]; dup {
+			panic(fmt.Sprintf("config: client type %s registered twice", key))
+		}
+	}
+	for _, key := range keys {
+		clientFactories.byKey[key] = factory
+	}
+	clientFactories.all = append(clientFactories.all, factory)
+
+	if factory.NewReqConfig != nil {
+		dto.RegisterReqConfig(factory.Type, factory.NewReqConfig)
+	}
+}
+
+// LookupClientType returns the factory registered under a short name or a
+// full NetClientType.
+func LookupClientType(nameOrType string) (ClientFactory, bool) {
+	clientFactories.RLock()
+	defer clientFactories.RUnlock()
+	factory, ok := clientFactories.byKey[nameOrType]
+	return factory, ok
+}
+
+// ClientTypes lists the registered factories sorted by Type.
+func ClientTypes() []ClientFactory {
+	clientFactories.RLock()
+	out := append([]ClientFactory(nil), clientFactories.all...)
+	clientFactories.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Type < out[j].Type })
+	return out
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes the environment variables read by ApplyEnv.
+const EnvPrefix = "GONETIC_"
+
+// LoadNetSvcConfig reads a JSON or YAML file, chosen by extension, over
+// DefaultNetSvcConfig, overlays the environment with ApplyEnv and validates
+// it. YAML durations may be written as "30s"; JSON durations are nanoseconds.
+// Attach a relay with WithRelay before passing the result to gonetic.New.
+func LoadNetSvcConfig(path string) (NetSvcConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return NetSvcConfig{}, fmt.Errorf("read net config: %w", err)
+	}
+
+	cfg := DefaultNetSvcConfig()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&cfg)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&cfg)
+	default:
+		return NetSvcConfig{}, fmt.Errorf("unsupported net config format %q", filepath.Ext(path))
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return NetSvcConfig{}, fmt.Errorf("decode net config: %w", err)
+	}
+	if err := cfg.ApplyEnv(); err != nil {
+		return NetSvcConfig{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return NetSvcConfig{}, fmt.Errorf("invalid net config: %w", err)
+	}
+	return cfg, nil
+}
+
+// ApplyEnv overlays the service-level settings set in the environment:
+//
+//	GONETIC_USER_AGENT
+//	GONETIC_REQUEST_TIMEOUT             Go duration, e.g. "30s"
+//	GONETIC_DOWNLOAD_CALLBACK_INTERVAL  Go duration
+//	GONETIC_PREFER_CURL_DOWNLOADS       bool
+//	GONETIC_LOG_BODIES                  bool
+//	GONETIC_WHITELIST_DOMAINS           comma separated
+//	GONETIC_BLACKLIST_DOMAINS           comma separated
+//
+// Clients are not overlaid; their secret fields take env:NAME references.
+func (c *NetSvcConfig) ApplyEnv() error {
+	lookup := func(name string) (string, bool) {
+		return os.LookupEnv(EnvPrefix + name)
+	}
+	if v, ok := lookup("USER_AGENT"); ok {
+		c.UserAgent = v
+	}
+	for name, dst := range map[string]*time.Duration{
+		"REQUEST_TIMEOUT":            &c.RequestTimeout,
+		"DOWNLOAD_CALLBACK_INTERVAL": &c.DownloadCallbackInterval,
+	} {
+		if v, ok := lookup(name); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("env %s%s: %w", EnvPrefix, name, err)
+			}
+			*dst = d
+		}
+	}
+	for name, dst := range map[string]*bool{
+		"PREFER_CURL_DOWNLOADS": &c.PreferCurlDownloads,
+		"LOG_BODIES":            &c.LogBodies,
+	} {
+		if v, ok := lookup(name); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("env %s%s: %w", EnvPrefix, name, err)
+			}
+			*dst = b
+		}
+	}
+	for name, dst := range map[string]*[]string{
+		"WHITELIST_DOMAINS": &c.WhitelistDomains,
+		"BLACKLIST_DOMAINS": &c.BlacklistDomains,
+	} {
+		if v, ok := lookup(name); ok {
+			domains := make([]string, 0)
+			for _, d := range strings.Split(v, ",") {
+				if d = strings.TrimSpace(d); d != "" {
+					domains = append(domains, d)
+				}
+			}
+			*dst = domains
+		}
+	}
+	return nil
+}
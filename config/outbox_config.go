@@ -0,0 +1,48 @@
+package config
+
+import "time"
+
+// OutboxConfig enables the durable outbound request queue. The outbox is
+// disabled while Path is empty.
+type OutboxConfig struct {
+	// Path is the journal file holding queued requests
+	Path string `json:"path,omitempty" yaml:"path,omitempty" mapstructure:"path"`
+	// MaxAttempts is the number of failed deliveries before an entry is dead-lettered
+	MaxAttempts int `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty" mapstructure:"max_attempts"`
+	// RetryInterval is the wait after the first failed delivery, doubling per
+	// attempt up to MaxRetryInterval
+	RetryInterval    time.Duration `json:"retry_interval,omitempty" yaml:"retry_interval,omitempty" mapstructure:"retry_interval"`
+	MaxRetryInterval time.Duration `json:"max_retry_interval,omitempty" yaml:"max_retry_interval,omitempty" mapstructure:"max_retry_interval"`
+	// DedupeWindow is how long idempotency keys of delivered entries are kept
+	DedupeWindow time.Duration `json:"dedupe_window,omitempty" yaml:"dedupe_window,omitempty" mapstructure:"dedupe_window"`
+}
+
+func DefaultOutboxConfig() OutboxConfig {
+	return OutboxConfig{
+		MaxAttempts:      10,
+		RetryInterval:    5 * time.Second,
+		MaxRetryInterval: 5 * time.Minute,
+		DedupeWindow:     24 * time.Hour,
+	}
+}
+
+func (c *OutboxConfig) WithPath(path string) *OutboxConfig {
+	c.Path = path
+	return c
+}
+
+func (c *OutboxConfig) WithMaxAttempts(attempts int) *OutboxConfig {
+	c.MaxAttempts = attempts
+	return c
+}
+
+func (c *OutboxConfig) WithRetryInterval(interval time.Duration, max time.Duration) *OutboxConfig {
+	c.RetryInterval = interval
+	c.MaxRetryInterval = max
+	return c
+}
+
+func (c *OutboxConfig) WithDedupeWindow(window time.Duration) *OutboxConfig {
+	c.DedupeWindow = window
+	return c
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyConfig selects the proxy HTTP clients and downloads go through. The
+// zero value uses HTTP_PROXY, HTTPS_PROXY and NO_PROXY from the environment.
+type ProxyConfig struct {
+	// URL is an http, https, socks5 or socks5h proxy. socks5h resolves host
+	// names on the proxy.
+	URL      string `json:"url,omitempty" yaml:"url,omitempty" mapstructure:"url"`
+	Username string `json:"username,omitempty" yaml:"username,omitempty" mapstructure:"username"`
+	Password Secret `json:"password,omitempty" yaml:"password,omitempty" mapstructure:"password"`
+	// NoProxy lists hosts reached directly: "*" for all, an IP or CIDR, a
+	// host name matching itself and its subdomains, or ".example.com" for
+	// subdomains only. It also applies when URL is taken from the environment.
+	NoProxy []string `json:"no_proxy,omitempty" yaml:"no_proxy,omitempty" mapstructure:"no_proxy"`
+	// Disabled connects directly, ignoring the environment
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty" mapstructure:"disabled"`
+}
+
+func (c *ProxyConfig) WithURL(proxyURL string) *ProxyConfig {
+	c.URL = proxyURL
+	return c
+}
+
+func (c *ProxyConfig) WithAuth(username string, password Secret) *ProxyConfig {
+	c.Username = username
+	c.Password = password
+	return c
+}
+
+func (c *ProxyConfig) WithNoProxy(hosts ...string) *ProxyConfig {
+	c.NoProxy = append(c.NoProxy, hosts...)
+	return c
+}
+
+func (c *ProxyConfig) WithDisabled(disabled bool) *ProxyConfig {
+	c.Disabled = disabled
+	return c
+}
+
+// Validate checks the URL scheme, the bypass rules and the password reference.
+func (c *ProxyConfig) Validate() error {
+	var errs []error
+	if c.URL != "" {
+		if _, err := c.parseURL(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, rule := range c.NoProxy {
+		if strings.Contains(rule, "/") {
+			if _, _, err := net.ParseCIDR(rule); err != nil {
+				errs = append(errs, fmt.Errorf("proxy no_proxy %q: %w", rule, err))
+			}
+		}
+	}
+	if c.Password != "" && c.Username == "" {
+		errs = append(errs, errors.New("proxy password requires username"))
+	}
+	errs = append(errs, c.Password.validate())
+	return errors.Join(errs...)
+}
+
+func (c *ProxyConfig) parseURL() (*url.URL, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, fmt.Errorf("proxy url: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("proxy url %q: unsupported scheme %q", u.Redacted(), u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy url %q: missing host", u.Redacted())
+	}
+	return u, nil
+}
+
+// proxyURL returns the URL with credentials resolved.
+func (c *ProxyConfig) proxyURL() (*url.URL, error) {
+	u, err := c.parseURL()
+	if err != nil {
+		return nil, err
+	}
+	if c.Username != "" {
+		password, err := c.Password.Resolve()
+		if err != nil {
+			return nil, fmt.Errorf("proxy password: %w", err)
+		}
+		u.User = url.UserPassword(c.Username, password)
+	}
+	return u, nil
+}
+
+// ProxyFunc returns a function for http.Transport.Proxy. It is nil when the
+// proxy is disabled.
+func (c *ProxyConfig) ProxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if c.Disabled {
+		return nil, nil
+	}
+	next := http.ProxyFromEnvironment
+	if c.URL != "" {
+		u, err := c.proxyURL()
+		if err != nil {
+			return nil, err
+		}
+		next = http.ProxyURL(u)
+	}
+	if len(c.NoProxy) == 0 {
+		return next, nil
+	}
+	rules := append([]string(nil), c.NoProxy...)
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(rules, req.URL.Hostname()) {
+			return nil, nil
+		}
+		return next(req)
+	}, nil
+}
+
+func bypassProxy(rules []string, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ip := net.ParseIP(host)
+	for _, rule := range rules {
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		switch {
+		case rule == "*":
+			return true
+		case strings.Contains(rule, "/"):
+			if _, network, err := net.ParseCIDR(rule); err == nil && ip != nil && network.Contains(ip) {
+				return true
+			}
+		case ip != nil:
+			if ruleIP := net.ParseIP(rule); ruleIP != nil && ruleIP.Equal(ip) {
+				return true
+			}
+		case strings.HasPrefix(rule, "*."), strings.HasPrefix(rule, "."):
+			if strings.HasSuffix(host, strings.TrimPrefix(rule, "*")) {
+				return true
+			}
+		case host == rule || strings.HasSuffix(host, "."+rule):
+			return true
+		}
+	}
+	return false
+}
+
+// CurlArgs returns the curl flags applying c. With no URL curl reads the same
+// environment variables as Go. Credentials are kept off the command line,
+// where other users can see them: they are returned as a curl config to feed
+// on stdin, and args then include "--config -".
+func (c *ProxyConfig) CurlArgs() (args []string, stdin string, err error) {
+	if c.Disabled {
+		return []string{"--noproxy", "*"}, "", nil
+	}
+	if c.URL != "" {
+		u, err := c.parseURL()
+		if err != nil {
+			return nil, "", err
+		}
+		args = append(args, "--proxy", u.String())
+		if c.Username != "" {
+			password, err := c.Password.Resolve()
+			if err != nil {
+				return nil, "", fmt.Errorf("proxy password: %w", err)
+			}
+			args = append(args, "--config", "-")
+			stdin = "proxy-user = " + curlQuote(c.Username+":"+password) + "\n"
+		}
+	}
+	if len(c.NoProxy) > 0 {
+		args = append(args, "--noproxy", strings.Join(c.NoProxy, ","))
+	}
+	return args, stdin, nil
+}
+
+// curlQuote quotes s as a curl config file value.
+func curlQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`).Replace(s) + `"`
+}
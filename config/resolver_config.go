@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IP families accepted in ResolverConfig.IPFamily. Empty uses both, IPv6
+// first as the system returns them.
+const (
+	IPFamilyV4Only     = "ipv4"
+	IPFamilyV6Only     = "ipv6"
+	IPFamilyPreferIPv4 = "prefer_ipv4"
+	IPFamilyPreferIPv6 = "prefer_ipv6"
+)
+
+// ResolverConfig controls how HTTP clients and their downloads turn host
+// names into addresses. The zero value uses the system resolver.
+type ResolverConfig struct {
+	// Hosts pins "host" or "host:port" to addresses, like curl --resolve.
+	// They are used before any DNS lookup and never cached.
+	Hosts map[string][]string `json:"hosts,omitempty" yaml:"hosts,omitempty" mapstructure:"hosts"`
+	// Server is a DNS server, "ip" or "ip:port", queried instead of the
+	// system configuration
+	Server string `json:"server,omitempty" yaml:"server,omitempty" mapstructure:"server"`
+	// CacheTTL keeps successful lookups for this long; zero disables caching
+	CacheTTL time.Duration `json:"cache_ttl,omitempty" yaml:"cache_ttl,omitempty" mapstructure:"cache_ttl"`
+	// IPFamily restricts or orders addresses; see IPFamilyV4Only and friends
+	IPFamily string `json:"ip_family,omitempty" yaml:"ip_family,omitempty" mapstructure:"ip_family"`
+	// FallbackDelay is how long to wait for the first address family before
+	// racing the other (Happy Eyeballs). Zero uses 300ms, negative dials the
+	// addresses one after another.
+	FallbackDelay time.Duration `json:"fallback_delay,omitempty" yaml:"fallback_delay,omitempty" mapstructure:"fallback_delay"`
+}
+
+// IsZero reports whether c leaves resolution to the system.
+func (c *ResolverConfig) IsZero() bool {
+	return len(c.Hosts) == 0 && c.Server == "" && c.CacheTTL == 0 && c.IPFamily == "" && c.FallbackDelay == 0
+}
+
+// WithHost pins host, optionally "host:port", to addrs.
+func (c *ResolverConfig) WithHost(host string, addrs ...string) *ResolverConfig {
+	if c.Hosts == nil {
+		c.Hosts = make(map[string][]string)
+	}
+	c.Hosts[host] = append(c.Hosts[host], addrs...)
+	return c
+}
+
+func (c *ResolverConfig) WithServer(server string) *ResolverConfig {
+	c.Server = server
+	return c
+}
+
+func (c *ResolverConfig) WithCacheTTL(ttl time.Duration) *ResolverConfig {
+	c.CacheTTL = ttl
+	return c
+}
+
+func (c *ResolverConfig) WithIPFamily(family string) *ResolverConfig {
+	c.IPFamily = family
+	return c
+}
+
+func (c *ResolverConfig) WithFallbackDelay(delay time.Duration) *ResolverConfig {
+	c.FallbackDelay = delay
+	return c
+}
+
+// ServerAddr returns Server with the default DNS port added.
+func (c *ResolverConfig) ServerAddr() string {
+	if _, _, err := net.SplitHostPort(c.Server); err == nil {
+		return c.Server
+	}
+	return net.JoinHostPort(strings.Trim(c.Server, "[]"), "53")
+}
+
+// Validate checks host overrides, the server address and the IP family.
+func (c *ResolverConfig) Validate() error {
+	var errs []error
+	for host, addrs := range c.Hosts {
+		if host == "" || len(addrs) == 0 {
+			errs = append(errs, fmt.Errorf("resolver host %q: needs a name and addresses", host))
+		}
+		for _, addr := range addrs {
+			if net.ParseIP(addr) == nil {
+				errs = append(errs, fmt.Errorf("resolver host %q: %q is not an IP address", host, addr))
+			}
+		}
+	}
+	if c.Server != "" {
+		host, _, err := net.SplitHostPort(c.ServerAddr())
+		if err != nil || net.ParseIP(host) == nil {
+			errs = append(errs, fmt.Errorf("resolver server %q: want ip or ip:port", c.Server))
+		}
+	}
+	switch c.IPFamily {
+	case "", IPFamilyV4Only, IPFamilyV6Only, IPFamilyPreferIPv4, IPFamilyPreferIPv6:
+	default:
+		errs = append(errs, fmt.Errorf("unknown resolver ip_family %q", c.IPFamily))
+	}
+	if c.CacheTTL < 0 {
+		errs = append(errs, errors.New("resolver cache_ttl must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// CurlArgs returns the curl flags applying c, or false when curl cannot
+// express it: a custom DNS server needs a c-ares build of curl. Host
+// overrides without a port are given for ports 80 and 443.
+func (c *ResolverConfig) CurlArgs() ([]string, bool) {
+	if c.Server != "" {
+		return nil, false
+	}
+	var args []string
+	hosts := make([]string, 0, len(c.Hosts))
+	for host := range c.Hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		addrs := make([]string, 0, len(c.Hosts[host]))
+		for _, addr := range c.Hosts[host] {
+			if strings.Contains(addr, ":") {
+				addr = "[" + addr + "]"
+			}
+			addrs = append(addrs, addr)
+		}
+		if name, port, err := net.SplitHostPort(host); err == nil {
+			args = append(args, "--resolve", name+":"+port+":"+strings.Join(addrs, ","))
+			continue
+		}
+		for _, port := range []string{"80", "443"} {
+			args = append(args, "--resolve", host+":"+port+":"+strings.Join(addrs, ","))
+		}
+	}
+	switch c.IPFamily {
+	case IPFamilyV4Only:
+		args = append(args, "-4")
+	case IPFamilyV6Only:
+		args = append(args, "-6")
+	}
+	if c.FallbackDelay > 0 {
+		args = append(args, "--happy-eyeballs-timeout-ms", strconv.FormatInt(c.FallbackDelay.Milliseconds(), 10))
+	}
+	return args, true
+}
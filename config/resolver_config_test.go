@@ -0,0 +1,65 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joy-dx/gonetic/config"
+)
+
+func TestResolverConfig_Golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cfg      config.ResolverConfig
+		wantErr  string
+		wantCurl string
+		noCurl   bool
+	}{
+		{name: "zero"},
+		{
+			name: "overrides, family and happy eyeballs",
+			cfg: config.ResolverConfig{
+				Hosts: map[string][]string{
+					"api.staging.test":      {"10.0.0.5", "10.0.0.6"},
+					"files.staging.test:81": {"2001:db8::1"},
+				},
+				IPFamily:      config.IPFamilyV4Only,
+				FallbackDelay: 150 * time.Millisecond,
+			},
+			wantCurl: "--resolve api.staging.test:80:10.0.0.5,10.0.0.6 --resolve api.staging.test:443:10.0.0.5,10.0.0.6 " +
+				"--resolve files.staging.test:81:[2001:db8::1] -4 --happy-eyeballs-timeout-ms 150",
+		},
+		{name: "prefer family is not a curl flag", cfg: config.ResolverConfig{IPFamily: config.IPFamilyPreferIPv4}},
+		{name: "dns server only in go", cfg: config.ResolverConfig{Server: "10.0.0.53", CacheTTL: time.Minute}, noCurl: true},
+		{name: "dns server with port", cfg: config.ResolverConfig{Server: "[2001:db8::53]:5353"}, noCurl: true},
+		{name: "override to a name", cfg: config.ResolverConfig{Hosts: map[string][]string{"a.test": {"b.test"}}}, wantErr: "not an IP"},
+		{name: "override without addresses", cfg: config.ResolverConfig{Hosts: map[string][]string{"a.test": nil}}, wantErr: "needs a name"},
+		{name: "server name", cfg: config.ResolverConfig{Server: "dns.example"}, wantErr: "want ip"},
+		{name: "unknown family", cfg: config.ResolverConfig{IPFamily: "ipv5"}, wantErr: "ip_family"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err=%v want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			args, ok := tt.cfg.CurlArgs()
+			if ok == tt.noCurl {
+				t.Fatalf("curl ok=%v want %v", ok, !tt.noCurl)
+			}
+			if got := strings.Join(args, " "); got != tt.wantCurl {
+				t.Fatalf("curl=%q want %q", got, tt.wantCurl)
+			}
+		})
+	}
+}
@@ -1,14 +1,19 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/telemetry"
+	"github.com/joy-dx/gonetic/utils"
 	relayDTO "github.com/joy-dx/relay/dto"
 )
 
 type NetSvcConfig struct {
 	relay                    relayDTO.RelayInterface
+	instrumentation          telemetry.Provider
 	ExtraHeaders             dto.ExtraHeaders `json:"extra_headers,omitempty" yaml:"extra_headers,omitempty" mapstructure:"extra_headers"`
 	RequestTimeout           time.Duration    `json:"request_timeout,omitempty" yaml:"request_timeout,omitempty" mapstructure:"request_timeout"`
 	UserAgent                string           `json:"user_agent,omitempty" yaml:"user_agent,omitempty" mapstructure:"user_agent"`
@@ -17,6 +22,16 @@ type NetSvcConfig struct {
 	DownloadCallbackInterval time.Duration    `json:"download_callback_interval,omitempty" yaml:"download_callback_interval,omitempty" mapstructure:"download_callback_interval"`
 	// PreferCurlDownloads Instead of using imroc/req for downloads, prefer to use curl found on $PATH if available
 	PreferCurlDownloads bool `json:"prefer_curl_downloads,omitempty" yaml:"prefer_curl_downloads,omitempty" mapstructure:"prefer_curl_downloads"`
+	// Clients are instantiated and registered by NetSvc.Hydrate
+	Clients []ClientConfig `json:"clients,omitempty" yaml:"clients,omitempty" mapstructure:"clients"`
+	// Outbox is started by NetSvc.Hydrate when Outbox.Path is set
+	Outbox OutboxConfig `json:"outbox,omitempty" yaml:"outbox,omitempty" mapstructure:"outbox"`
+	// Redactor masks secrets in request relay events
+	Redactor utils.Redactor `json:"redactor,omitempty" yaml:"redactor,omitempty" mapstructure:"redactor"`
+	// Proxy is used by HTTP clients that do not set their own
+	Proxy ProxyConfig `json:"proxy,omitempty" yaml:"proxy,omitempty" mapstructure:"proxy"`
+	// LogBodies adds redacted request and response bodies, truncated, to relay events
+	LogBodies bool `json:"log_bodies,omitempty" yaml:"log_bodies,omitempty" mapstructure:"log_bodies"`
 }
 
 func DefaultNetSvcConfig() NetSvcConfig {
@@ -25,6 +40,8 @@ func DefaultNetSvcConfig() NetSvcConfig {
 		ExtraHeaders:             make(dto.ExtraHeaders),
 		BlacklistDomains:         make([]string, 0),
 		WhitelistDomains:         []string{"github.com"},
+		Outbox:                   DefaultOutboxConfig(),
+		Redactor:                 utils.DefaultRedactor(),
 	}
 }
 
@@ -67,6 +84,57 @@ func (c *NetSvcConfig) WithPreferCurl(preference bool) *NetSvcConfig {
 	return c
 }
 
+// WithClient declares a client for Hydrate to instantiate.
+func (c *NetSvcConfig) WithClient(client ClientConfig) *NetSvcConfig {
+	c.Clients = append(c.Clients, client)
+	return c
+}
+
+// WithOutbox enables the outbox with its journal at path.
+func (c *NetSvcConfig) WithOutbox(path string) *NetSvcConfig {
+	c.Outbox.Path = path
+	return c
+}
+
+// WithProxy sets the proxy for HTTP clients without their own.
+func (c *NetSvcConfig) WithProxy(proxy ProxyConfig) *NetSvcConfig {
+	c.Proxy = proxy
+	return c
+}
+
+// WithRedactor replaces the redactor applied to request relay events.
+func (c *NetSvcConfig) WithRedactor(redactor utils.Redactor) *NetSvcConfig {
+	c.Redactor = redactor
+	return c
+}
+
+// WithLogBodies includes redacted bodies in request relay events.
+func (c *NetSvcConfig) WithLogBodies(enabled bool) *NetSvcConfig {
+	c.LogBodies = enabled
+	return c
+}
+
+// Validate checks the proxy, every declared client and that refs are unique.
+func (c *NetSvcConfig) Validate() error {
+	var errs []error
+	if err := c.Proxy.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("proxy: %w", err))
+	}
+	seen := make(map[string]bool, len(c.Clients))
+	for i := range c.Clients {
+		client := &c.Clients[i]
+		if err := client.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if seen[client.Ref] {
+			errs = append(errs, fmt.Errorf("client %s: duplicate ref", client.Ref))
+		}
+		seen[client.Ref] = true
+	}
+	return errors.Join(errs...)
+}
+
 func (c *NetSvcConfig) WithRelay(relay relayDTO.RelayInterface) *NetSvcConfig {
 	c.relay = relay
 	return c
@@ -74,3 +142,17 @@ func (c *NetSvcConfig) WithRelay(relay relayDTO.RelayInterface) *NetSvcConfig {
 func (c *NetSvcConfig) Relay() relayDTO.RelayInterface {
 	return c.relay
 }
+
+// WithInstrumentation sets where request spans and metrics are reported.
+func (c *NetSvcConfig) WithInstrumentation(provider telemetry.Provider) *NetSvcConfig {
+	c.instrumentation = provider
+	return c
+}
+
+// Instrumentation returns the configured provider, telemetry.Noop when unset.
+func (c *NetSvcConfig) Instrumentation() telemetry.Provider {
+	if c.instrumentation == nil {
+		return telemetry.Noop
+	}
+	return c.instrumentation
+}
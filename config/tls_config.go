@@ -0,0 +1,170 @@
+package config
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Prefixes accepted in TLSConfig pins.
+const (
+	// PinPrefixSPKI pins the base64 SHA-256 of the leaf certificate's
+	// SubjectPublicKeyInfo, the format used by curl --pinnedpubkey.
+	PinPrefixSPKI = "sha256/"
+	// PinPrefixCert pins the hex SHA-256 of the whole leaf certificate.
+	PinPrefixCert = "cert-sha256/"
+)
+
+// TLSConfig customises how an HTTP client verifies servers and authenticates
+// itself. The zero value uses Go's defaults.
+type TLSConfig struct {
+	// CAFiles are PEM bundles of trusted roots. They replace the system roots
+	// unless IncludeSystemRoots is set.
+	CAFiles            []string `json:"ca_files,omitempty" yaml:"ca_files,omitempty" mapstructure:"ca_files"`
+	IncludeSystemRoots bool     `json:"include_system_roots,omitempty" yaml:"include_system_roots,omitempty" mapstructure:"include_system_roots"`
+	// CertFile and KeyFile are a PEM client certificate and key for mutual TLS
+	CertFile string `json:"cert_file,omitempty" yaml:"cert_file,omitempty" mapstructure:"cert_file"`
+	KeyFile  string `json:"key_file,omitempty" yaml:"key_file,omitempty" mapstructure:"key_file"`
+	// MinVersion is one of 1.0, 1.1, 1.2 or 1.3
+	MinVersion string `json:"min_version,omitempty" yaml:"min_version,omitempty" mapstructure:"min_version"`
+	// ServerName overrides the SNI name and the name the certificate is
+	// verified against
+	ServerName string `json:"server_name,omitempty" yaml:"server_name,omitempty" mapstructure:"server_name"`
+	// Pins restrict the accepted leaf certificates; see PinPrefixSPKI and
+	// PinPrefixCert. Any match is accepted.
+	Pins []string `json:"pins,omitempty" yaml:"pins,omitempty" mapstructure:"pins"`
+	// ReloadInterval is how often the CA, certificate and key files are checked
+	// for changes. Zero loads them once.
+	ReloadInterval time.Duration `json:"reload_interval,omitempty" yaml:"reload_interval,omitempty" mapstructure:"reload_interval"`
+}
+
+// IsZero reports whether c leaves every setting at Go's default.
+func (c *TLSConfig) IsZero() bool {
+	return len(c.CAFiles) == 0 && c.CertFile == "" && c.KeyFile == "" &&
+		c.MinVersion == "" && c.ServerName == "" && len(c.Pins) == 0
+}
+
+func (c *TLSConfig) WithCAFile(path string) *TLSConfig {
+	c.CAFiles = append(c.CAFiles, path)
+	return c
+}
+
+func (c *TLSConfig) WithClientCertificate(certFile string, keyFile string) *TLSConfig {
+	c.CertFile = certFile
+	c.KeyFile = keyFile
+	return c
+}
+
+func (c *TLSConfig) WithMinVersion(version string) *TLSConfig {
+	c.MinVersion = version
+	return c
+}
+
+func (c *TLSConfig) WithServerName(name string) *TLSConfig {
+	c.ServerName = name
+	return c
+}
+
+func (c *TLSConfig) WithPin(pin string) *TLSConfig {
+	c.Pins = append(c.Pins, pin)
+	return c
+}
+
+func (c *TLSConfig) WithReloadInterval(interval time.Duration) *TLSConfig {
+	c.ReloadInterval = interval
+	return c
+}
+
+// TLSVersion returns MinVersion as a crypto/tls constant, 0 when unset.
+func (c *TLSConfig) TLSVersion() (uint16, error) {
+	switch c.MinVersion {
+	case "":
+		return 0, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unknown tls min_version %q", c.MinVersion)
+	}
+}
+
+// Validate checks the version, the pin formats and that certificate and key
+// are set together. It does not read the files.
+func (c *TLSConfig) Validate() error {
+	var errs []error
+	if _, err := c.TLSVersion(); err != nil {
+		errs = append(errs, err)
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		errs = append(errs, errors.New("tls cert_file and key_file must be set together"))
+	}
+	for _, pin := range c.Pins {
+		if err := validatePin(pin); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.ReloadInterval < 0 {
+		errs = append(errs, errors.New("tls reload_interval must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+func validatePin(pin string) error {
+	switch {
+	case strings.HasPrefix(pin, PinPrefixSPKI):
+		sum, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(pin, PinPrefixSPKI))
+		if err != nil || len(sum) != 32 {
+			return fmt.Errorf("tls pin %q: want base64 sha256", pin)
+		}
+	case strings.HasPrefix(pin, PinPrefixCert):
+		sum, err := hex.DecodeString(strings.ReplaceAll(strings.TrimPrefix(pin, PinPrefixCert), ":", ""))
+		if err != nil || len(sum) != 32 {
+			return fmt.Errorf("tls pin %q: want hex sha256", pin)
+		}
+	default:
+		return fmt.Errorf("tls pin %q: want %s or %s prefix", pin, PinPrefixSPKI, PinPrefixCert)
+	}
+	return nil
+}
+
+// CurlArgs returns the curl flags applying c, or false when curl cannot
+// express it: several CA files, system roots plus custom CAs, a ServerName
+// override or certificate pins.
+func (c *TLSConfig) CurlArgs() ([]string, bool) {
+	var args []string
+	switch {
+	case len(c.CAFiles) > 1, len(c.CAFiles) == 1 && c.IncludeSystemRoots:
+		return nil, false
+	case len(c.CAFiles) == 1:
+		args = append(args, "--cacert", c.CAFiles[0])
+	}
+	if c.ServerName != "" {
+		return nil, false
+	}
+	if c.CertFile != "" {
+		args = append(args, "--cert", c.CertFile, "--key", c.KeyFile)
+	}
+	if c.MinVersion != "" {
+		args = append(args, "--tlsv"+c.MinVersion)
+	}
+	var spki []string
+	for _, pin := range c.Pins {
+		if !strings.HasPrefix(pin, PinPrefixSPKI) {
+			return nil, false
+		}
+		spki = append(spki, "sha256//"+strings.TrimPrefix(pin, PinPrefixSPKI))
+	}
+	if len(spki) > 0 {
+		args = append(args, "--pinnedpubkey", strings.Join(spki, ";"))
+	}
+	return args, true
+}
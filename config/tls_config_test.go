@@ -0,0 +1,63 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/joy-dx/gonetic/config"
+)
+
+const testSPKIPin = "sha256/47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+
+func TestTLSConfig_Golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cfg      config.TLSConfig
+		wantErr  string
+		wantCurl string
+		noCurl   bool
+	}{
+		{name: "zero"},
+		{
+			name:     "ca, client cert, version and spki pin",
+			cfg:      config.TLSConfig{CAFiles: []string{"/ca.pem"}, CertFile: "/c.pem", KeyFile: "/k.pem", MinVersion: "1.3", Pins: []string{testSPKIPin}},
+			wantCurl: "--cacert /ca.pem --cert /c.pem --key /k.pem --tlsv1.3 --pinnedpubkey sha256//47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=",
+		},
+		{
+			name:   "cert pin only in go",
+			cfg:    config.TLSConfig{Pins: []string{"cert-sha256/" + strings.Repeat("ab", 32)}},
+			noCurl: true,
+		},
+		{name: "server name only in go", cfg: config.TLSConfig{ServerName: "api.internal"}, noCurl: true},
+		{name: "several ca files only in go", cfg: config.TLSConfig{CAFiles: []string{"/a.pem", "/b.pem"}}, noCurl: true},
+		{name: "ca plus system roots only in go", cfg: config.TLSConfig{CAFiles: []string{"/a.pem"}, IncludeSystemRoots: true}, noCurl: true},
+		{name: "bad version", cfg: config.TLSConfig{MinVersion: "1.4"}, wantErr: "min_version"},
+		{name: "cert without key", cfg: config.TLSConfig{CertFile: "/c.pem"}, wantErr: "set together"},
+		{name: "pin without prefix", cfg: config.TLSConfig{Pins: []string{"abc"}}, wantErr: "prefix"},
+		{name: "short spki pin", cfg: config.TLSConfig{Pins: []string{"sha256/YWJj"}}, wantErr: "base64 sha256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err=%v want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			args, ok := tt.cfg.CurlArgs()
+			if ok == tt.noCurl {
+				t.Fatalf("curl ok=%v want %v", ok, !tt.noCurl)
+			}
+			if got := strings.Join(args, " "); got != tt.wantCurl {
+				t.Fatalf("curl args=%q want %q", got, tt.wantCurl)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TransportConfig tunes the connection pool and protocols of an HTTP client.
+// Zero values keep the defaults noted on each field.
+type TransportConfig struct {
+	// MaxIdleConns caps idle connections across all hosts; zero uses 50
+	MaxIdleConns int `json:"max_idle_conns,omitempty" yaml:"max_idle_conns,omitempty" mapstructure:"max_idle_conns"`
+	// MaxIdleConnsPerHost zero uses net/http's default of 2
+	MaxIdleConnsPerHost int `json:"max_idle_conns_per_host,omitempty" yaml:"max_idle_conns_per_host,omitempty" mapstructure:"max_idle_conns_per_host"`
+	// MaxConnsPerHost limits dialing, in-use and idle connections; zero is unlimited
+	MaxConnsPerHost int `json:"max_conns_per_host,omitempty" yaml:"max_conns_per_host,omitempty" mapstructure:"max_conns_per_host"`
+	// IdleConnTimeout zero uses 90s
+	IdleConnTimeout time.Duration `json:"idle_conn_timeout,omitempty" yaml:"idle_conn_timeout,omitempty" mapstructure:"idle_conn_timeout"`
+	// TLSHandshakeTimeout zero uses 10s
+	TLSHandshakeTimeout time.Duration `json:"tls_handshake_timeout,omitempty" yaml:"tls_handshake_timeout,omitempty" mapstructure:"tls_handshake_timeout"`
+	// ResponseHeaderTimeout bounds the wait for headers after the request is
+	// written; zero waits as long as the request timeout allows
+	ResponseHeaderTimeout time.Duration `json:"response_header_timeout,omitempty" yaml:"response_header_timeout,omitempty" mapstructure:"response_header_timeout"`
+	// ExpectContinueTimeout is how long to wait for 100-continue when the
+	// request sends "Expect: 100-continue"; zero sends the body at once
+	ExpectContinueTimeout time.Duration `json:"expect_continue_timeout,omitempty" yaml:"expect_continue_timeout,omitempty" mapstructure:"expect_continue_timeout"`
+	// ForceAttemptHTTP2 negotiates HTTP/2 over TLS; nil keeps HTTP/1.1
+	ForceAttemptHTTP2 *bool `json:"force_attempt_http2,omitempty" yaml:"force_attempt_http2,omitempty" mapstructure:"force_attempt_http2"`
+	// H2C speaks cleartext HTTP/2 with prior knowledge to http:// URLs.
+	// HTTPS URLs then require HTTP/2 as well.
+	H2C bool `json:"h2c,omitempty" yaml:"h2c,omitempty" mapstructure:"h2c"`
+}
+
+func (c *TransportConfig) WithMaxIdleConns(n int) *TransportConfig {
+	c.MaxIdleConns = n
+	return c
+}
+
+func (c *TransportConfig) WithMaxIdleConnsPerHost(n int) *TransportConfig {
+	c.MaxIdleConnsPerHost = n
+	return c
+}
+
+func (c *TransportConfig) WithMaxConnsPerHost(n int) *TransportConfig {
+	c.MaxConnsPerHost = n
+	return c
+}
+
+func (c *TransportConfig) WithIdleConnTimeout(d time.Duration) *TransportConfig {
+	c.IdleConnTimeout = d
+	return c
+}
+
+func (c *TransportConfig) WithTLSHandshakeTimeout(d time.Duration) *TransportConfig {
+	c.TLSHandshakeTimeout = d
+	return c
+}
+
+func (c *TransportConfig) WithResponseHeaderTimeout(d time.Duration) *TransportConfig {
+	c.ResponseHeaderTimeout = d
+	return c
+}
+
+func (c *TransportConfig) WithExpectContinueTimeout(d time.Duration) *TransportConfig {
+	c.ExpectContinueTimeout = d
+	return c
+}
+
+func (c *TransportConfig) WithForceAttemptHTTP2(enabled bool) *TransportConfig {
+	c.ForceAttemptHTTP2 = &enabled
+	return c
+}
+
+func (c *TransportConfig) WithH2C(enabled bool) *TransportConfig {
+	c.H2C = enabled
+	return c
+}
+
+// HTTP2 reports whether HTTP/2 is attempted over TLS.
+func (c *TransportConfig) HTTP2() bool {
+	return c.ForceAttemptHTTP2 != nil && *c.ForceAttemptHTTP2
+}
+
+// Validate rejects negative limits and timeouts, and h2c with HTTP/2
+// explicitly disabled.
+func (c *TransportConfig) Validate() error {
+	var errs []error
+	limits := []struct {
+		name  string
+		value int64
+	}{
+		{"max_idle_conns", int64(c.MaxIdleConns)},
+		{"max_idle_conns_per_host", int64(c.MaxIdleConnsPerHost)},
+		{"max_conns_per_host", int64(c.MaxConnsPerHost)},
+		{"idle_conn_timeout", int64(c.IdleConnTimeout)},
+		{"tls_handshake_timeout", int64(c.TLSHandshakeTimeout)},
+		{"response_header_timeout", int64(c.ResponseHeaderTimeout)},
+		{"expect_continue_timeout", int64(c.ExpectContinueTimeout)},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative", limit.name))
+		}
+	}
+	if c.H2C && c.ForceAttemptHTTP2 != nil && !*c.ForceAttemptHTTP2 {
+		errs = append(errs, errors.New("h2c conflicts with force_attempt_http2: false"))
+	}
+	return errors.Join(errs...)
+}
+
+// CurlArgs returns the protocol flags curl downloads need.
+func (c *TransportConfig) CurlArgs() []string {
+	switch {
+	case c.H2C:
+		return []string{"--http2-prior-knowledge"}
+	case c.ForceAttemptHTTP2 != nil && !*c.ForceAttemptHTTP2:
+		return []string{"--http1.1"}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joy-dx/gonetic/config"
+)
+
+func TestTransportConfig_Golden(t *testing.T) {
+	t.Parallel()
+
+	noHTTP2 := false
+	tests := []struct {
+		name     string
+		cfg      config.TransportConfig
+		wantErr  string
+		wantCurl string
+	}{
+		{name: "zero"},
+		{
+			name: "pool tuning",
+			cfg: config.TransportConfig{
+				MaxIdleConns:          200,
+				MaxIdleConnsPerHost:   20,
+				MaxConnsPerHost:       40,
+				ResponseHeaderTimeout: 5 * time.Second,
+				ExpectContinueTimeout: time.Second,
+			},
+		},
+		{name: "h2c", cfg: config.TransportConfig{H2C: true}, wantCurl: "--http2-prior-knowledge"},
+		{name: "http/1.1 only", cfg: config.TransportConfig{ForceAttemptHTTP2: &noHTTP2}, wantCurl: "--http1.1"},
+		{name: "h2c without http2", cfg: config.TransportConfig{H2C: true, ForceAttemptHTTP2: &noHTTP2}, wantErr: "h2c conflicts with force_attempt_http2"},
+		{name: "negative limit", cfg: config.TransportConfig{MaxConnsPerHost: -1}, wantErr: "max_conns_per_host must not be negative"},
+		{name: "negative timeout", cfg: config.TransportConfig{ResponseHeaderTimeout: -time.Second}, wantErr: "response_header_timeout must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err=%v want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if got := strings.Join(tt.cfg.CurlArgs(), " "); got != tt.wantCurl {
+				t.Fatalf("curl=%q want %q", got, tt.wantCurl)
+			}
+		})
+	}
+}
@@ -10,8 +10,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/joy-dx/gonetic/client/httpclient"
 	"github.com/joy-dx/gonetic/dto"
 	"github.com/joy-dx/gonetic/relays"
 	"github.com/joy-dx/gonetic/utils"
@@ -22,6 +24,7 @@ func (s *NetSvc) downloadFileWithHTTP(
 	ctx context.Context,
 	cfg *dto.DownloadFileConfig,
 	destination string,
+	transport http.RoundTripper,
 ) error {
 	s.relay.Debug(relays.RlyNetDownload{
 		Source:      cfg.URL,
@@ -51,7 +54,8 @@ func (s *NetSvc) downloadFileWithHTTP(
 		return fmt.Errorf("failed to build request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	// No client timeout: downloads run as long as ctx allows.
+	resp, err := (&http.Client{Transport: transport}).Do(req)
 	if err != nil {
 		// If ctx was canceled, prefer STOPPED (so listeners close consistently)
 		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
@@ -182,6 +186,7 @@ func (s *NetSvc) downloadFileWithCurl(
 	ctx context.Context,
 	cfg *dto.DownloadFileConfig,
 	destination string,
+	curl curlOptions,
 ) error {
 	s.relay.Debug(relays.RlyNetDownload{
 		Source:      cfg.URL,
@@ -194,11 +199,20 @@ func (s *NetSvc) downloadFileWithCurl(
 		return fmt.Errorf("could not create destination folder %q: %w", destination, err)
 	}
 
-	curlCmd := exec.CommandContext(ctx, "curl", "-L", "--progress-bar", "-o", destination, cfg.URL)
+	args := append([]string{"-L", "--progress-bar"}, curl.Args...)
+	target := cfg.URL
+	if socket, httpURL, isUnix := httpclient.SplitUnixURL(cfg.URL); isUnix {
+		args = append(args, "--unix-socket", socket)
+		target = httpURL
+	}
+	curlCmd := exec.CommandContext(ctx, "curl", append(args, "-o", destination, target)...)
 	stdoutBuf := new(bytes.Buffer)
 	stderrBuf := new(bytes.Buffer)
 	curlCmd.Stdout = stdoutBuf
 	curlCmd.Stderr = stderrBuf
+	if curl.Stdin != "" {
+		curlCmd.Stdin = strings.NewReader(curl.Stdin)
+	}
 
 	if err := curlCmd.Start(); err != nil {
 		return fmt.Errorf("failed to start curl: %w", err)
@@ -285,7 +299,25 @@ func (s *NetSvc) downloadFileWithCurl(
 }
 
 func (s *NetSvc) DownloadFile(ctx context.Context, cfg *dto.DownloadFileConfig) (string, error) {
+	ctx, done, err := s.track(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer done()
+
+	ctx, finish := s.instrumentDownload(ctx, cfg)
+	destination, err := s.downloadFile(ctx, cfg)
+	var size int64
+	if err == nil {
+		if info, statErr := os.Stat(destination); statErr == nil {
+			size = info.Size()
+		}
+	}
+	finish(size, err)
+	return destination, err
+}
 
+func (s *NetSvc) downloadFile(ctx context.Context, cfg *dto.DownloadFileConfig) (string, error) {
 	if cfg.OutputFileName == "" {
 		// Try and get the filename from the URL and use the destination folder instead
 		filename, err := utils.FilenameFromUrl(cfg.URL)
@@ -305,9 +337,67 @@ func (s *NetSvc) DownloadFile(ctx context.Context, cfg *dto.DownloadFileConfig)
 		Msg:         fmt.Sprintf("starting download: %s", cfg.URL),
 	})
 
+	if cfg.ClientRef != "" {
+		netClient, isOK := s.Client(cfg.ClientRef)
+		if !isOK {
+			return destination, fmt.Errorf("client not found: %s", cfg.ClientRef)
+		}
+		if downloader, isOK := netClient.(dto.FileDownloader); isOK {
+			return destination, downloader.DownloadFile(ctx, cfg, destination)
+		}
+		if _, isOK := netClient.(transportProvider); !isOK {
+			return destination, fmt.Errorf("client %s does not support file downloads", cfg.ClientRef)
+		}
+	}
+
+	transport, curl, curlOK := s.downloadTransport(cfg.ClientRef)
 	if s.cfg.PreferCurlDownloads {
-		return destination, s.downloadFileWithCurl(ctx, cfg, destination)
+		if curlOK {
+			return destination, s.downloadFileWithCurl(ctx, cfg, destination, curl)
+		}
+		s.relay.Debug(relays.RlyNetLog{Msg: "curl cannot apply the client transport settings, downloading via net/http"})
 	}
 
-	return destination, s.downloadFileWithHTTP(ctx, cfg, destination)
+	return destination, s.downloadFileWithHTTP(ctx, cfg, destination, transport)
+}
+
+// transportProvider is implemented by clients whose transport downloads can
+// reuse, such as httpclient.HTTPClient.
+type transportProvider interface {
+	Transport() http.RoundTripper
+}
+
+// curlArgsProvider is implemented by clients that can express their transport
+// settings as curl flags, plus a curl config to pass on stdin.
+type curlArgsProvider interface {
+	CurlArgs() (args []string, stdin string, ok bool)
+}
+
+// curlOptions are the client specific flags of a curl download. Stdin, when
+// set, is a curl config read through "--config -" so secrets stay off argv.
+type curlOptions struct {
+	Args  []string
+	Stdin string
+}
+
+// downloadTransport returns the transport and curl options of client ref, or
+// of the default client when ref is empty. A nil transport means
+// http.DefaultTransport.
+func (s *NetSvc) downloadTransport(ref string) (http.RoundTripper, curlOptions, bool) {
+	if ref == "" {
+		ref = dto.NET_DEFAULT_CLIENT_REF
+	}
+	netClient, isOK := s.Client(ref)
+	if !isOK {
+		return nil, curlOptions{}, true
+	}
+	var transport http.RoundTripper
+	if provider, isOK := netClient.(transportProvider); isOK {
+		transport = provider.Transport()
+	}
+	if provider, isOK := netClient.(curlArgsProvider); isOK {
+		args, stdin, curlOK := provider.CurlArgs()
+		return transport, curlOptions{Args: args, Stdin: stdin}, curlOK
+	}
+	return transport, curlOptions{}, true
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/pem"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -17,7 +19,6 @@ import (
 
 	"github.com/joy-dx/gonetic/config"
 	"github.com/joy-dx/gonetic/dto"
-	"github.com/joy-dx/lockablemap"
 )
 
 func TestDownloadFile_HTTP_Golden(t *testing.T) {
@@ -111,12 +112,10 @@ func TestDownloadFile_HTTP_Golden(t *testing.T) {
 			cfg.PreferCurlDownloads = false
 			cfg.DownloadCallbackInterval = 5 * time.Millisecond
 
-			s := &NetSvc{
-				cfg:            &cfg,
-				relay:          &fakeRelay{},
-				clients:        map[string]dto.NetClientInterface{},
-				transferState:  *lockablemap.NewLockableMap[string, dto.TransferNotification](),
-				listenersByURL: map[string][]chan dto.TransferNotification{},
+			cfg.WithRelay(&fakeRelay{})
+			s, err := New(&cfg)
+			if err != nil {
+				t.Fatalf("New: %v", err)
 			}
 
 			ctx, cancel := context.WithCancel(context.Background())
@@ -127,7 +126,7 @@ func TestDownloadFile_HTTP_Golden(t *testing.T) {
 
 			ch, _ := s.TransferListener(tt.cfg.URL)
 
-			_, err := s.DownloadFile(ctx, &tt.cfg)
+			_, err = s.DownloadFile(ctx, &tt.cfg)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("err=%v wantErr=%v", err, tt.wantErr)
 			}
@@ -180,12 +179,10 @@ func TestDownloadFile_HTTP_BadStatus_Golden(t *testing.T) {
 	cfg := config.DefaultNetSvcConfig()
 	cfg.PreferCurlDownloads = false
 
-	s := &NetSvc{
-		cfg:            &cfg,
-		relay:          &fakeRelay{},
-		clients:        map[string]dto.NetClientInterface{},
-		transferState:  *lockablemap.NewLockableMap[string, dto.TransferNotification](),
-		listenersByURL: map[string][]chan dto.TransferNotification{},
+	cfg.WithRelay(&fakeRelay{})
+	s, err := New(&cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
 	}
 
 	dl := dto.DownloadFileConfig{
@@ -195,7 +192,7 @@ func TestDownloadFile_HTTP_BadStatus_Golden(t *testing.T) {
 	}
 
 	ch, _ := s.TransferListener(dl.URL)
-	_, err := s.DownloadFile(context.Background(), &dl)
+	_, err = s.DownloadFile(context.Background(), &dl)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -237,12 +234,10 @@ func TestDownloadFile_Curl_Golden(t *testing.T) {
 	cfg.PreferCurlDownloads = true
 	cfg.DownloadCallbackInterval = 50 * time.Millisecond
 
-	s := &NetSvc{
-		cfg:            &cfg,
-		relay:          &fakeRelay{},
-		clients:        map[string]dto.NetClientInterface{},
-		transferState:  *lockablemap.NewLockableMap[string, dto.TransferNotification](),
-		listenersByURL: map[string][]chan dto.TransferNotification{},
+	cfg.WithRelay(&fakeRelay{})
+	s, err := New(&cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
 	}
 
 	dl := dto.DownloadFileConfig{
@@ -252,7 +247,7 @@ func TestDownloadFile_Curl_Golden(t *testing.T) {
 	}
 
 	ch, _ := s.TransferListener(dl.URL)
-	_, err := s.DownloadFile(context.Background(), &dl)
+	_, err = s.DownloadFile(context.Background(), &dl)
 	if err != nil {
 		t.Fatalf("DownloadFile err: %v", err)
 	}
@@ -275,3 +270,214 @@ func TestDownloadFile_Curl_Golden(t *testing.T) {
 		}
 	}
 }
+
+type fakeDownloaderClient struct {
+	fakeNetClient
+	gotDest string
+}
+
+func (c *fakeDownloaderClient) DownloadFile(ctx context.Context, cfg *dto.DownloadFileConfig, destination string) error {
+	c.gotDest = destination
+	return os.WriteFile(destination, []byte(cfg.URL), 0o644)
+}
+
+func TestDownloadFile_ClientRef_Golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		clientRef string
+		wantErr   string
+	}{
+		{name: "delegates to registered downloader", clientRef: "s3"},
+		{name: "unknown client", clientRef: "missing", wantErr: "client not found: missing"},
+		{name: "client without download support", clientRef: "plain", wantErr: "client plain does not support file downloads"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newTestSvc(t)
+			downloader := &fakeDownloaderClient{fakeNetClient: fakeNetClient{ref: "s3", typ: "fake"}}
+			s.RegisterClient("s3", downloader)
+			s.RegisterClient("plain", &fakeNetClient{ref: "plain", typ: "fake"})
+
+			cfg := dto.DownloadFileConfig{
+				URL:               "s3://bucket/dir/object.bin",
+				DestinationFolder: t.TempDir(),
+				ClientRef:         tt.clientRef,
+			}
+			dest, err := s.DownloadFile(context.Background(), &cfg)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadFile err: %v", err)
+			}
+			if filepath.Base(dest) != "object.bin" || downloader.gotDest != dest {
+				t.Fatalf("dest=%q downloader got %q", dest, downloader.gotDest)
+			}
+		})
+	}
+}
+
+func TestNetSvc_DownloadFile_ClientTLS_Golden(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("secure"))
+	}))
+	t.Cleanup(srv.Close)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	trusted := config.TLSConfig{CAFiles: []string{caFile}}
+
+	tests := []struct {
+		name      string
+		clients   []config.ClientConfig
+		clientRef string
+		wantErr   bool
+	}{
+		{name: "default client without ca", wantErr: true},
+		{
+			name:    "declared default client with ca",
+			clients: []config.ClientConfig{{Ref: dto.NET_DEFAULT_CLIENT_REF, Type: config.ClientTypeHTTP, TLS: trusted}},
+		},
+		{
+			name:      "named http client with ca",
+			clients:   []config.ClientConfig{{Ref: "internal", Type: config.ClientTypeHTTP, TLS: trusted}},
+			clientRef: "internal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.DefaultNetSvcConfig()
+			cfg.WithRelay(&fakeRelay{})
+			for _, client := range tt.clients {
+				cfg.WithClient(client)
+			}
+			s, err := New(&cfg)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if err := s.Hydrate(context.Background()); err != nil {
+				t.Fatalf("Hydrate: %v", err)
+			}
+
+			download := dto.DownloadFileConfig{URL: srv.URL + "/file.bin", DestinationFolder: t.TempDir(), ClientRef: tt.clientRef}
+			dest, err := s.DownloadFile(context.Background(), &download)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DownloadFile err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if data, err := os.ReadFile(dest); err != nil || string(data) != "secure" {
+				t.Fatalf("downloaded %q err=%v", data, err)
+			}
+		})
+	}
+}
+
+func TestNetSvc_DownloadFile_UnixSocket_Golden(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "daemon.sock")
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("from " + r.URL.Path))
+	})}
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() { srv.Close() })
+
+	tests := []struct {
+		name string
+		curl bool
+	}{
+		{name: "net/http"},
+		{name: "curl", curl: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := exec.LookPath("curl"); tt.curl && err != nil {
+				t.Skip("curl not found on PATH")
+			}
+
+			cfg := config.DefaultNetSvcConfig()
+			cfg.WithRelay(&fakeRelay{})
+			s, err := New(&cfg)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if err := s.Hydrate(context.Background()); err != nil {
+				t.Fatalf("Hydrate: %v", err)
+			}
+			s.cfg.PreferCurlDownloads = tt.curl
+
+			download := dto.DownloadFileConfig{URL: "http+unix://" + socket + ":/artifacts/build.tar", DestinationFolder: t.TempDir()}
+			dest, err := s.DownloadFile(context.Background(), &download)
+			if err != nil {
+				t.Fatalf("DownloadFile: %v", err)
+			}
+			if data, err := os.ReadFile(dest); err != nil || string(data) != "from /artifacts/build.tar" || filepath.Base(dest) != "build.tar" {
+				t.Fatalf("downloaded %q to %s err=%v", data, dest, err)
+			}
+		})
+	}
+}
+
+func TestNetSvc_DownloadFile_CurlProxyAuth_Golden(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("curl"); err != nil {
+		t.Skip("curl not found on PATH")
+	}
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := (&http.Request{Header: http.Header{"Authorization": r.Header["Proxy-Authorization"]}}).BasicAuth(); !ok || user != "svc" || pass != `s3"cret` {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		if !strings.HasPrefix(r.UserAgent(), "curl/") {
+			w.WriteHeader(http.StatusBadRequest) // must not fall back to net/http
+			return
+		}
+		_, _ = w.Write([]byte("via proxy " + r.URL.String()))
+	}))
+	t.Cleanup(proxy.Close)
+
+	cfg := config.DefaultNetSvcConfig()
+	cfg.WithRelay(&fakeRelay{})
+	cfg.PreferCurlDownloads = true
+	cfg.Proxy = config.ProxyConfig{URL: proxy.URL, Username: "svc", Password: `s3"cret`}
+	s, err := New(&cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := s.Hydrate(context.Background()); err != nil {
+		t.Fatalf("Hydrate: %v", err)
+	}
+
+	download := dto.DownloadFileConfig{URL: "http://artifacts.test/build.tar", DestinationFolder: t.TempDir()}
+	dest, err := s.DownloadFile(context.Background(), &download)
+	if err != nil {
+		t.Fatalf("DownloadFile: %v", err)
+	}
+	if data, err := os.ReadFile(dest); err != nil || string(data) != "via proxy http://artifacts.test/build.tar" {
+		t.Fatalf("downloaded %q err=%v", data, err)
+	}
+}
@@ -15,6 +15,14 @@ type NetInterface interface {
 	RequestWithRetry(ctx context.Context, cfg *RequestConfig) (Response, error)
 }
 
+// NetRegistry is implemented by services whose client registry can be
+// changed and listed at runtime, such as *gonetic.NetSvc.
+type NetRegistry interface {
+	UnregisterClient(ref string) bool
+	ReplaceClient(ref string, client NetClientInterface) (NetClientInterface, error)
+	Clients() []NetClient
+}
+
 // AuthProvider defines methods for non-OAuth authentication schemes.
 // Returned dto.TokenInfo may include cookies or access tokens.
 // The *http.dto.Response allows cookie extraction.
@@ -36,3 +44,28 @@ type NetClientInterface interface {
 	Type() NetClientType
 	ProcessRequest(ctx context.Context, cfg *RequestConfig) (Response, error)
 }
+
+// NetClientDescriptor is implemented by clients that describe themselves for
+// NetSvc.Clients and NetState. Clients without it are listed by ref and type.
+type NetClientDescriptor interface {
+	Descriptor() NetClient
+}
+
+// TransferPublisher is implemented by clients that report transfer progress.
+// NetSvc hands its publisher to the client on RegisterClient so that updates
+// reach the same TransferListener channels as file downloads.
+type TransferPublisher interface {
+	SetTransferPublisher(publish func(TransferNotification))
+}
+
+// FileDownloader is implemented by clients that fetch DownloadFileConfig.URL
+// themselves, such as the S3 client for s3:// URLs.
+type FileDownloader interface {
+	DownloadFile(ctx context.Context, cfg *DownloadFileConfig, destination string) error
+}
+
+// RequestDescriber is implemented by ReqConfigs that can describe the request
+// they build in client-neutral terms, for recording and matching.
+type RequestDescriber interface {
+	Describe() (RequestDescription, error)
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"io"
 	"net/http"
 	"time"
 )
@@ -29,6 +30,20 @@ type NetState struct {
 	// PreferCurlDownloads Instead of using imroc/req for downloads, prefer to use curl found on $PATH if available
 	PreferCurlDownloads bool                            `json:"prefer_curl_downloads,omitempty" yaml:"net_prefer_curl_downloads,omitempty"`
 	TransfersStatus     map[string]TransferNotification `json:"net_transfers_status,omitempty" yaml:"net_transfers_status,omitempty"`
+	// Clients lists registered clients sorted by ref
+	Clients []NetClient `json:"net_clients,omitempty" yaml:"net_clients,omitempty"`
+	// Outbox is nil when the outbox is disabled
+	Outbox *OutboxState `json:"net_outbox,omitempty" yaml:"net_outbox,omitempty"`
+	// ConnectionPools maps client refs to their open connections per host
+	ConnectionPools map[string][]HostPoolStats `json:"net_connection_pools,omitempty" yaml:"net_connection_pools,omitempty"`
+}
+
+// HostPoolStats counts the open connections a client holds to one host, the
+// dialed "host:port" or Unix socket path.
+type HostPoolStats struct {
+	Host  string `json:"host" yaml:"host"`
+	InUse int    `json:"in_use" yaml:"in_use"`
+	Idle  int    `json:"idle" yaml:"idle"`
 }
 
 // Download File
@@ -41,6 +56,9 @@ type DownloadFileConfig struct {
 	DestinationFolder string
 	OutputFileName    string
 	SkipAllowedPaths  bool
+	// ClientRef Delegates the download to a registered client implementing
+	// FileDownloader, e.g. an S3 client for s3://bucket/key URLs
+	ClientRef string
 }
 
 type Response struct {
@@ -48,4 +66,38 @@ type Response struct {
 	Headers    http.Header
 	// As well as casting to ResponseObject if set, return as byes
 	Body []byte
+	// Stream is set instead of Body when the request asked for a streaming
+	// response. The caller owns it and must Close it.
+	Stream io.ReadCloser
+	// Timing is set by clients that collect connection timing, e.g. an
+	// HTTPClient with timing enabled
+	Timing *ResponseTiming
+}
+
+// ResponseTiming breaks down where the time of one attempt went. Phases that
+// did not happen, such as DNS and connect on a reused connection, are zero.
+type ResponseTiming struct {
+	DNSLookup    time.Duration `json:"dns_lookup,omitempty" yaml:"dns_lookup,omitempty"`
+	TCPConnect   time.Duration `json:"tcp_connect,omitempty" yaml:"tcp_connect,omitempty"`
+	TLSHandshake time.Duration `json:"tls_handshake,omitempty" yaml:"tls_handshake,omitempty"`
+	// TimeToFirstByte is measured from the start of the request
+	TimeToFirstByte time.Duration `json:"time_to_first_byte" yaml:"time_to_first_byte"`
+	// Total includes reading the body
+	Total      time.Duration `json:"total" yaml:"total"`
+	ConnReused bool          `json:"conn_reused" yaml:"conn_reused"`
+	RemoteAddr string        `json:"remote_addr,omitempty" yaml:"remote_addr,omitempty"`
+	// Protocol is the response protocol, e.g. HTTP/1.1 or HTTP/2.0
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+}
+
+// OutboxState reports the durable request queue in NetState.
+type OutboxState struct {
+	Path      string `json:"path" yaml:"path"`
+	Pending   int    `json:"pending" yaml:"pending"`
+	Dead      int    `json:"dead" yaml:"dead"`
+	Delivered int    `json:"delivered" yaml:"delivered"`
+	// OldestPendingAt is when the oldest pending entry was enqueued
+	OldestPendingAt time.Time `json:"oldest_pending_at,omitempty" yaml:"oldest_pending_at,omitempty"`
+	LastDeliveredAt time.Time `json:"last_delivered_at,omitempty" yaml:"last_delivered_at,omitempty"`
+	LastError       string    `json:"last_error,omitempty" yaml:"last_error,omitempty"`
 }
@@ -25,13 +25,23 @@ type RequestConfig struct {
 	MaxRetries     int              `json:"max_retries" yaml:"max_retries"`
 	Delay          utils.RetryDelay `json:"-" yaml:"-"`
 	TaskName       string           `json:"task_name" yaml:"task_name"`
+
+	// timeoutSet and maxRetriesSet record WithTimeout and WithMaxRetries calls
+	timeoutSet    bool
+	maxRetriesSet bool
 }
 
+const (
+	// DefaultTimeout and DefaultMaxRetries are set by DefaultRequestConfig.
+	DefaultTimeout    = 20 * time.Second
+	DefaultMaxRetries = 3
+)
+
 func DefaultRequestConfig() RequestConfig {
 	return RequestConfig{
 		ClientRef:  NET_DEFAULT_CLIENT_REF,
-		Timeout:    20 * time.Second,
-		MaxRetries: 3,
+		Timeout:    DefaultTimeout,
+		MaxRetries: DefaultMaxRetries,
 		Delay:      utils.ExponentialBackoff{},
 	}
 }
@@ -53,14 +63,28 @@ func (c *RequestConfig) WithResponseObject(object interface{}) *RequestConfig {
 
 func (c *RequestConfig) WithTimeout(duration time.Duration) *RequestConfig {
 	c.Timeout = duration
+	c.timeoutSet = true
 	return c
 }
 
 func (c *RequestConfig) WithMaxRetries(count int) *RequestConfig {
 	c.MaxRetries = count
+	c.maxRetriesSet = true
 	return c
 }
 
+// TimeoutSet reports whether Timeout was chosen by the caller: set through
+// WithTimeout, or to a value other than zero and DefaultTimeout. Unset
+// timeouts give way to the defaults of a client declared in config.
+func (c *RequestConfig) TimeoutSet() bool {
+	return c.timeoutSet || (c.Timeout != 0 && c.Timeout != DefaultTimeout)
+}
+
+// MaxRetriesSet is TimeoutSet for MaxRetries and DefaultMaxRetries.
+func (c *RequestConfig) MaxRetriesSet() bool {
+	return c.maxRetriesSet || (c.MaxRetries != 0 && c.MaxRetries != DefaultMaxRetries)
+}
+
 func (c *RequestConfig) WithDelay(delay utils.RetryDelay) *RequestConfig {
 	c.Delay = delay
 	return c
@@ -77,3 +101,17 @@ func (c *RequestConfig) BuildRequest(ctx context.Context) (any, error) {
 	}
 	return c.ReqConfig.NewRequest(ctx)
 }
+
+// RequestDescription is a client-neutral view of a request. Method and URL use
+// the client's own vocabulary, e.g. "GET https://..." for HTTP or
+// "get s3://bucket/key" for S3.
+type RequestDescription struct {
+	ClientType NetClientType     `json:"client_type" yaml:"client_type"`
+	Method     string            `json:"method" yaml:"method"`
+	URL        string            `json:"url" yaml:"url"`
+	Headers    map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body       []byte            `json:"body,omitempty" yaml:"body,omitempty"`
+	// DestinationPath is the local file the response body is written to
+	// instead of Response.Body, if any.
+	DestinationPath string `json:"destination_path,omitempty" yaml:"destination_path,omitempty"`
+}
@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/joy-dx/gonetic/utils"
+)
+
+// requestConfigJSON is the wire form of RequestConfig. ReqConfig is stored
+// with the client type it was registered under so it can be decoded again.
+type requestConfigJSON struct {
+	ClientRef  string                 `json:"client_ref"`
+	ClientType NetClientType          `json:"client_type,omitempty"`
+	ReqConfig  json.RawMessage        `json:"req_config,omitempty"`
+	Timeout    time.Duration          `json:"timeout"`
+	MaxRetries int                    `json:"max_retries"`
+	Delay      *utils.DelayDescriptor `json:"delay,omitempty"`
+	TaskName   string                 `json:"task_name,omitempty"`
+}
+
+// MarshalJSON encodes the request with its ReqConfig keyed by ReqConfig.Ref()
+// and Delay as a utils.DelayDescriptor. ResponseObject is not encoded.
+func (c RequestConfig) MarshalJSON() ([]byte, error) {
+	wire := requestConfigJSON{
+		ClientRef:  c.ClientRef,
+		Timeout:    c.Timeout,
+		MaxRetries: c.MaxRetries,
+		TaskName:   c.TaskName,
+	}
+	if c.ReqConfig != nil {
+		reqConfig, err := json.Marshal(c.ReqConfig)
+		if err != nil {
+			return nil, fmt.Errorf("encode req config: %w", err)
+		}
+		wire.ClientType = c.ReqConfig.Ref()
+		wire.ReqConfig = reqConfig
+	}
+	if c.Delay != nil {
+		desc, err := utils.DescribeDelay(c.Delay)
+		if err != nil {
+			return nil, err
+		}
+		wire.Delay = &desc
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON decodes ReqConfig with the type registered for client_type
+// through RegisterReqConfig. Fields missing from the input keep their current
+// values, so decoding over DefaultRequestConfig fills in the defaults.
+func (c *RequestConfig) UnmarshalJSON(data []byte) error {
+	wire := requestConfigJSON{
+		ClientRef:  c.ClientRef,
+		Timeout:    c.Timeout,
+		MaxRetries: c.MaxRetries,
+		TaskName:   c.TaskName,
+	}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	c.ClientRef = wire.ClientRef
+	c.Timeout = wire.Timeout
+	c.MaxRetries = wire.MaxRetries
+	c.TaskName = wire.TaskName
+
+	if len(wire.ReqConfig) > 0 && !bytes.Equal(wire.ReqConfig, []byte("null")) {
+		if wire.ClientType == "" {
+			return errors.New("req_config requires client_type")
+		}
+		reqConfig, err := DecodeReqConfig(wire.ClientType, wire.ReqConfig)
+		if err != nil {
+			return err
+		}
+		c.ReqConfig = reqConfig
+	}
+	if wire.Delay != nil {
+		delay, err := wire.Delay.Delay()
+		if err != nil {
+			return err
+		}
+		c.Delay = delay
+	}
+	return nil
+}
+
+// ReadRequestsJSONL decodes one RequestConfig per line over
+// DefaultRequestConfig. Blank lines and lines starting with # are skipped;
+// fn receives the 1-based line number. Returning an error from fn stops reading.
+func ReadRequestsJSONL(r io.Reader, fn func(line int, cfg RequestConfig) error) error {
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		raw, readErr := reader.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return fmt.Errorf("read line %d: %w", line, readErr)
+		}
+		raw = bytes.TrimSpace(raw)
+		if len(raw) > 0 && raw[0] != '#' {
+			cfg := DefaultRequestConfig()
+			if err := json.Unmarshal(raw, &cfg); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			if err := fn(line, cfg); err != nil {
+				return err
+			}
+		}
+		if readErr != nil {
+			return nil
+		}
+	}
+}
+
+// WriteRequestJSONL appends cfg to w as a single JSON line.
+func WriteRequestJSONL(w io.Writer, cfg RequestConfig) error {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,128 @@
+package dto
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joy-dx/gonetic/utils"
+)
+
+const testReqConfigType NetClientType = "test.req"
+
+type testReqConfig struct {
+	URL string `json:"url"`
+}
+
+func (c *testReqConfig) Ref() NetClientType                          { return testReqConfigType }
+func (c *testReqConfig) NewRequest(ctx context.Context) (any, error) { return c, nil }
+
+func init() {
+	RegisterReqConfig(testReqConfigType, func() ReqConfigInterface { return &testReqConfig{} })
+}
+
+func TestRequestConfig_JSON_Golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		in      string
+		want    RequestConfig
+		wantErr string
+	}{
+		{
+			name: "full",
+			in:   `{"client_ref":"api","client_type":"test.req","req_config":{"url":"https://x"},"timeout":5000000000,"max_retries":1,"delay":{"type":"constant","period":2},"task_name":"sync"}`,
+			want: RequestConfig{ClientRef: "api", ReqConfig: &testReqConfig{URL: "https://x"}, Timeout: 5 * time.Second, MaxRetries: 1, Delay: utils.ConstantDelay{Period: 2}, TaskName: "sync"},
+		},
+		{
+			name: "defaults kept",
+			in:   `{"client_type":"test.req","req_config":{"url":"https://x"}}`,
+			want: RequestConfig{ClientRef: NET_DEFAULT_CLIENT_REF, ReqConfig: &testReqConfig{URL: "https://x"}, Timeout: 20 * time.Second, MaxRetries: 3, Delay: utils.ExponentialBackoff{}},
+		},
+		{name: "missing client type", in: `{"req_config":{"url":"https://x"}}`, wantErr: "requires client_type"},
+		{name: "unregistered client type", in: `{"client_type":"nope","req_config":{}}`, wantErr: "no req config registered"},
+		{name: "unknown delay", in: `{"delay":{"type":"linear"}}`, wantErr: `unknown delay type "linear"`},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := DefaultRequestConfig()
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err=%v want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			assertRequestConfig(t, got, tt.want)
+
+			encoded, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var again RequestConfig
+			if err := json.Unmarshal(encoded, &again); err != nil {
+				t.Fatalf("Unmarshal round trip: %v", err)
+			}
+			assertRequestConfig(t, again, tt.want)
+		})
+	}
+}
+
+func TestRequestConfig_MarshalUndescribableDelay_Golden(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultRequestConfig()
+	cfg.WithDelay(struct{ utils.RetryDelay }{})
+	if _, err := json.Marshal(cfg); err == nil {
+		t.Fatalf("expected error for undescribable delay")
+	}
+}
+
+func TestReadRequestsJSONL_Golden(t *testing.T) {
+	t.Parallel()
+
+	var buf strings.Builder
+	first := DefaultRequestConfig()
+	first.WithReqConfig(&testReqConfig{URL: "https://a"}).WithTaskName("a")
+	if err := WriteRequestJSONL(&buf, first); err != nil {
+		t.Fatalf("WriteRequestJSONL: %v", err)
+	}
+	buf.WriteString("\n# comment\n")
+	buf.WriteString(`{"client_type":"test.req","req_config":{"url":"https://b"},"task_name":"b"}`) // no trailing newline
+
+	var lines []int
+	var tasks []string
+	err := ReadRequestsJSONL(strings.NewReader(buf.String()), func(line int, cfg RequestConfig) error {
+		lines = append(lines, line)
+		tasks = append(tasks, cfg.TaskName+"="+cfg.ReqConfig.(*testReqConfig).URL)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadRequestsJSONL: %v", err)
+	}
+	if strings.Join(tasks, ",") != "a=https://a,b=https://b" || len(lines) != 2 || lines[1] != 4 {
+		t.Fatalf("lines=%v tasks=%v", lines, tasks)
+	}
+
+	if err := ReadRequestsJSONL(strings.NewReader("{bad\n"), func(int, RequestConfig) error { return nil }); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("err=%v want line 1 decode error", err)
+	}
+}
+
+func assertRequestConfig(t *testing.T, got, want RequestConfig) {
+	t.Helper()
+	gotReq, _ := got.ReqConfig.(*testReqConfig)
+	wantReq, _ := want.ReqConfig.(*testReqConfig)
+	if got.ClientRef != want.ClientRef || got.Timeout != want.Timeout || got.MaxRetries != want.MaxRetries ||
+		got.Delay != want.Delay || got.TaskName != want.TaskName || *gotReq != *wantReq {
+		t.Fatalf("got %+v (%+v) want %+v (%+v)", got, gotReq, want, wantReq)
+	}
+}
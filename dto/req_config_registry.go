@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+var reqConfigTypes = struct {
+	sync.RWMutex
+	m map[NetClientType]func() ReqConfigInterface
+}{m: map[NetClientType]func() ReqConfigInterface{}}
+
+// RegisterReqConfig records how to create an empty ReqConfig for a client
+// type so serialised requests can be decoded without importing the client
+// package. It panics if the type is already registered.
+func RegisterReqConfig(clientType NetClientType, newReqConfig func() ReqConfigInterface) {
+	reqConfigTypes.Lock()
+	defer reqConfigTypes.Unlock()
+	if _, dup := reqConfigTypes.m[clientType]; dup {
+		panic(fmt.Sprintf("dto: req config %s registered twice", clientType))
+	}
+	reqConfigTypes.m[clientType] = newReqConfig
+}
+
+// NewReqConfig returns an empty ReqConfig for a registered client type.
+func NewReqConfig(clientType NetClientType) (ReqConfigInterface, error) {
+	reqConfigTypes.RLock()
+	newReqConfig, ok := reqConfigTypes.m[clientType]
+	reqConfigTypes.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no req config registered for client type %s", clientType)
+	}
+	return newReqConfig(), nil
+}
+
+// DecodeReqConfig decodes JSON into the ReqConfig registered for clientType.
+func DecodeReqConfig(clientType NetClientType, data []byte) (ReqConfigInterface, error) {
+	reqConfig, err := NewReqConfig(clientType)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, reqConfig); err != nil {
+		return nil, fmt.Errorf("decode %s req config: %w", clientType, err)
+	}
+	return reqConfig, nil
+}
@@ -9,6 +9,7 @@ require (
 	github.com/joy-dx/lockablemap v1.0.1
 	github.com/joy-dx/relay v1.1.0
 	golang.org/x/oauth2 v0.34.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,127 @@
+package gonetictest
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joy-dx/gonetic"
+	"github.com/joy-dx/gonetic/client/httpclient"
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+	relayDTO "github.com/joy-dx/relay/dto"
+)
+
+// NoDelay is a retry delay that does not wait, keeping retry tests fast.
+type NoDelay struct{}
+
+func (NoDelay) Wait(taskName string, attempt int) {}
+
+type nopRelay struct{}
+
+func (nopRelay) Debug(relayDTO.RelayEventInterface) {}
+func (nopRelay) Info(relayDTO.RelayEventInterface)  {}
+func (nopRelay) Warn(relayDTO.RelayEventInterface)  {}
+func (nopRelay) Error(relayDTO.RelayEventInterface) {}
+func (nopRelay) Fatal(relayDTO.RelayEventInterface) {}
+func (nopRelay) Meta(relayDTO.RelayEventInterface)  {}
+
+var fixtureSeq atomic.Int64
+
+// Fixture is a NetSvc with an HTTP client registered under ClientRef that
+// talks to Server. Each fixture owns its NetSvc, which is shut down when the
+// test ends.
+type Fixture struct {
+	Server    *Server
+	Svc       *gonetic.NetSvc
+	Client    *httpclient.HTTPClient
+	ClientRef string
+	t         testing.TB
+}
+
+type fixtureOptions struct {
+	clientCfg httpclient.HTTPClientConfig
+	netCfg    config.NetSvcConfig
+}
+
+// FixtureOption customises NewFixture.
+type FixtureOption func(*fixtureOptions)
+
+// WithHTTPClientConfig sets the client config, e.g. to install an
+// AuthProvider or middleware.
+func WithHTTPClientConfig(cfg httpclient.HTTPClientConfig) FixtureOption {
+	return func(o *fixtureOptions) { o.clientCfg = cfg }
+}
+
+// WithNetSvcConfig sets the NetSvcConfig used by the fixture's client.
+func WithNetSvcConfig(cfg config.NetSvcConfig) FixtureOption {
+	return func(o *fixtureOptions) { o.netCfg = cfg }
+}
+
+// NewFixture starts a Server and registers a client for it with NetSvc.
+func NewFixture(t testing.TB, opts ...FixtureOption) *Fixture {
+	t.Helper()
+
+	o := fixtureOptions{
+		clientCfg: httpclient.DefaultHTTPClientConfig(),
+		netCfg:    config.DefaultNetSvcConfig(),
+	}
+	o.netCfg.RequestTimeout = 5 * time.Second
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.netCfg.Relay() == nil {
+		o.netCfg.WithRelay(nopRelay{})
+	}
+
+	svc, err := gonetic.New(&o.netCfg)
+	if err != nil {
+		t.Fatalf("gonetictest: %v", err)
+	}
+	t.Cleanup(func() { _ = svc.Close() })
+
+	f := &Fixture{
+		Server:    NewServer(t),
+		Svc:       svc,
+		ClientRef: fmt.Sprintf("gonetictest-%d", fixtureSeq.Add(1)),
+		t:         t,
+	}
+	f.Client = httpclient.NewHTTPClient(f.ClientRef, &o.netCfg, &o.clientCfg)
+	f.Svc.RegisterClient(f.ClientRef, f.Client)
+	return f
+}
+
+// URL returns the absolute server URL for p.
+func (f *Fixture) URL(p string) string {
+	return f.Server.URL + p
+}
+
+// Request builds a RequestConfig for the fixture client with a no-wait retry
+// delay. Adjust it with the usual With* methods.
+func (f *Fixture) Request(method, p string) *dto.RequestConfig {
+	httpCfg := httpclient.DefaultHTTPRequestConfig()
+	httpCfg.WithMethod(method).WithURL(f.URL(p))
+
+	cfg := dto.DefaultRequestConfig()
+	cfg.WithClientRef(f.ClientRef).
+		WithReqConfig(&httpCfg).
+		WithDelay(NoDelay{}).
+		WithTimeout(5 * time.Second).
+		WithTaskName(method + " " + p)
+	return &cfg
+}
+
+// Get is shorthand for Request(http.MethodGet, p).
+func (f *Fixture) Get(p string) *dto.RequestConfig {
+	return f.Request(http.MethodGet, p)
+}
+
+// DownloadConfig builds a DownloadFileConfig for p into a temporary folder.
+func (f *Fixture) DownloadConfig(p string) *dto.DownloadFileConfig {
+	return &dto.DownloadFileConfig{
+		URL:               f.URL(p),
+		DestinationFolder: f.t.TempDir(),
+	}
+}
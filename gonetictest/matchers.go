@@ -0,0 +1,79 @@
+package gonetictest
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// Matcher selects requests for a route or an assertion.
+type Matcher func(req *RecordedRequest) bool
+
+// Method matches the HTTP method.
+func Method(method string) Matcher {
+	return func(req *RecordedRequest) bool { return strings.EqualFold(req.Method, method) }
+}
+
+// Path matches the URL path exactly.
+func Path(p string) Matcher {
+	return func(req *RecordedRequest) bool { return req.Path == p }
+}
+
+// PathGlob matches the URL path with path.Match syntax, e.g. "/users/*".
+func PathGlob(pattern string) Matcher {
+	return func(req *RecordedRequest) bool {
+		ok, _ := path.Match(pattern, req.Path)
+		return ok
+	}
+}
+
+// Query matches a query parameter value.
+func Query(key, value string) Matcher {
+	return func(req *RecordedRequest) bool {
+		values, ok := req.Query[key]
+		return ok && len(values) > 0 && values[0] == value
+	}
+}
+
+// Header matches a request header value.
+func Header(key, value string) Matcher {
+	return func(req *RecordedRequest) bool { return req.Header.Get(key) == value }
+}
+
+// BodyContains matches when the raw body contains s.
+func BodyContains(s string) Matcher {
+	return func(req *RecordedRequest) bool { return bytes.Contains(req.Body, []byte(s)) }
+}
+
+// BodyJSON matches when the body decodes to the same JSON value as v.
+func BodyJSON(v any) Matcher {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		panic("gonetictest: encode json matcher: " + err.Error())
+	}
+	var want any
+	_ = json.Unmarshal(raw, &want)
+	return func(req *RecordedRequest) bool {
+		var got any
+		if err := json.Unmarshal(req.Body, &got); err != nil {
+			return false
+		}
+		return reflect.DeepEqual(got, want)
+	}
+}
+
+// Match adapts an arbitrary predicate.
+func Match(fn func(req *RecordedRequest) bool) Matcher {
+	return fn
+}
+
+func matchAll(matchers []Matcher, req *RecordedRequest) bool {
+	for _, m := range matchers {
+		if !m(req) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package gonetictest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Response is one scripted answer.
+type Response struct {
+	Status  int
+	Headers map[string]string
+	Body    []byte
+	// Delay is added to the route latency before answering.
+	Delay time.Duration
+	// Reset closes the connection instead of answering.
+	Reset bool
+	// Times repeats this response before moving to the next; zero means once.
+	// The last response of a route repeats indefinitely.
+	Times int
+}
+
+// Status answers with an empty body.
+func Status(status int) Response {
+	return Response{Status: status}
+}
+
+// Text answers with a plain text body.
+func Text(status int, body string) Response {
+	return Response{
+		Status:  status,
+		Headers: map[string]string{"Content-Type": "text/plain; charset=utf-8"},
+		Body:    []byte(body),
+	}
+}
+
+// JSON answers with v encoded as JSON. It panics if v cannot be encoded.
+func JSON(status int, v any) Response {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic("gonetictest: encode json response: " + err.Error())
+	}
+	return Response{
+		Status:  status,
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    body,
+	}
+}
+
+// Bytes answers with a binary body, e.g. for downloads.
+func Bytes(status int, body []byte) Response {
+	return Response{
+		Status:  status,
+		Headers: map[string]string{"Content-Type": "application/octet-stream"},
+		Body:    body,
+	}
+}
+
+// ResetConnection drops the connection without a response.
+func ResetConnection() Response {
+	return Response{Reset: true}
+}
+
+// Route is a scripted endpoint created by Server.On.
+type Route struct {
+	matchers []Matcher
+
+	mu       sync.Mutex
+	steps    []Response
+	latency  time.Duration
+	flap     *flap
+	hits     int
+	served   int
+	requests []RecordedRequest
+}
+
+type flap struct {
+	failures  int
+	successes int
+	status    int
+}
+
+// Respond appends responses to the route's sequence.
+func (r *Route) Respond(responses ...Response) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, responses...)
+	return r
+}
+
+// Latency delays every answer from this route.
+func (r *Route) Latency(d time.Duration) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latency = d
+	return r
+}
+
+// Flap makes the route alternate between failures requests answered with
+// status and successes requests answered from the response sequence.
+func (r *Route) Flap(failures, successes, status int) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flap = &flap{failures: failures, successes: successes, status: status}
+	return r
+}
+
+// Hits returns how many requests the route answered.
+func (r *Route) Hits() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.hits
+}
+
+// Requests returns the requests the route answered.
+func (r *Route) Requests() []RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedRequest(nil), r.requests...)
+}
+
+func (r *Route) next(req RecordedRequest) (Response, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hit := r.hits
+	r.hits++
+	r.requests = append(r.requests, req)
+
+	if f := r.flap; f != nil && f.failures+f.successes > 0 {
+		if hit%(f.failures+f.successes) < f.failures {
+			return Status(f.status), r.latency
+		}
+	}
+
+	if len(r.steps) == 0 {
+		return Status(http.StatusOK), r.latency
+	}
+	idx := r.served
+	r.served++
+	for _, step := range r.steps {
+		idx -= max(step.Times, 1)
+		if idx < 0 {
+			return step, r.latency
+		}
+	}
+	return r.steps[len(r.steps)-1], r.latency
+}
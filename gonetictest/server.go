@@ -0,0 +1,193 @@
+// Package gonetictest provides a scripted HTTP mock server and a NetSvc
+// fixture wired to it, for testing retry, auth and download paths without
+// hand-written httptest handlers.
+package gonetictest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// RecordedRequest is a request received by the Server.
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	Time   time.Time
+}
+
+// Server is a scripted mock server. Routes are matched in registration order
+// and the first match answers; unmatched requests get 404 and are recorded.
+type Server struct {
+	URL string
+
+	srv       *httptest.Server
+	mu        sync.Mutex
+	routes    []*Route
+	requests  []RecordedRequest
+	unmatched []RecordedRequest
+}
+
+// NewServer starts a Server that is closed when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// On registers a route answering requests that satisfy every matcher.
+func (s *Server) On(matchers ...Matcher) *Route {
+	r := &Route{matchers: matchers}
+	s.mu.Lock()
+	s.routes = append(s.routes, r)
+	s.mu.Unlock()
+	return r
+}
+
+// Reset removes all routes and recorded requests.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes, s.requests, s.unmatched = nil, nil, nil
+}
+
+// Requests returns every request received, in arrival order.
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// RequestsMatching returns received requests satisfying every matcher.
+func (s *Server) RequestsMatching(matchers ...Matcher) []RecordedRequest {
+	var out []RecordedRequest
+	for _, req := range s.Requests() {
+		if matchAll(matchers, &req) {
+			out = append(out, req)
+		}
+	}
+	return out
+}
+
+// Unmatched returns requests no route answered.
+func (s *Server) Unmatched() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.unmatched...)
+}
+
+// AssertRequests fails the test unless exactly want received requests satisfy
+// the matchers.
+func (s *Server) AssertRequests(t testing.TB, want int, matchers ...Matcher) {
+	t.Helper()
+	if got := len(s.RequestsMatching(matchers...)); got != want {
+		t.Fatalf("gonetictest: got %d matching requests, want %d", got, want)
+	}
+}
+
+// AssertNoUnmatched fails the test if any request fell through every route.
+func (s *Server) AssertNoUnmatched(t testing.TB) {
+	t.Helper()
+	if unmatched := s.Unmatched(); len(unmatched) > 0 {
+		t.Fatalf("gonetictest: %d unmatched requests, first: %s %s", len(unmatched), unmatched[0].Method, unmatched[0].Path)
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rec := RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+		Time:   time.Now(),
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, rec)
+	var route *Route
+	for _, candidate := range s.routes {
+		if matchAll(candidate.matchers, &rec) {
+			route = candidate
+			break
+		}
+	}
+	if route == nil {
+		s.unmatched = append(s.unmatched, rec)
+	}
+	s.mu.Unlock()
+
+	if route == nil {
+		http.Error(w, fmt.Sprintf("gonetictest: no route for %s %s", r.Method, r.URL.Path), http.StatusNotFound)
+		return
+	}
+
+	resp, latency := route.next(rec)
+	if !sleep(r.Context(), latency+resp.Delay) {
+		return
+	}
+	if resp.Reset {
+		resetConnection(w)
+		return
+	}
+	for k, v := range resp.Headers {
+		w.Header().Set(k, v)
+	}
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(resp.Body)
+}
+
+// sleep waits for d, returning false if the client went away first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// resetConnection closes the underlying TCP connection with SO_LINGER 0 so
+// the client sees a reset rather than a clean EOF.
+func resetConnection(w http.ResponseWriter) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		panic("gonetictest: response writer does not support hijacking")
+	}
+	conn, _, err := hj.Hijack()
+	if err != nil {
+		return
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetLinger(0)
+	}
+	_ = conn.Close()
+}
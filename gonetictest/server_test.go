@@ -0,0 +1,238 @@
+package gonetictest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joy-dx/gonetic/client/httpclient"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+func get(t *testing.T, srv *Server, method, p, body string, header map[string]string) (int, string, error) {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+p, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	out, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(out), nil
+}
+
+func TestServer_Matchers_Golden(t *testing.T) {
+	srv := NewServer(t)
+	srv.On(Method(http.MethodPost), Path("/users"), BodyJSON(map[string]any{"name": "ada"})).Respond(Text(201, "created"))
+	srv.On(Method(http.MethodGet), PathGlob("/users/*"), Query("expand", "true")).Respond(Text(200, "expanded"))
+	srv.On(Method(http.MethodGet), PathGlob("/users/*"), Header("X-Tenant", "acme")).Respond(Text(200, "tenant"))
+	srv.On(BodyContains("ping")).Respond(Text(200, "pong"))
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		header     map[string]string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "json body", method: http.MethodPost, path: "/users", body: `{ "name" : "ada" }`, wantStatus: 201, wantBody: "created"},
+		{name: "query", method: http.MethodGet, path: "/users/1?expand=true", wantStatus: 200, wantBody: "expanded"},
+		{name: "header", method: http.MethodGet, path: "/users/1", header: map[string]string{"X-Tenant": "acme"}, wantStatus: 200, wantBody: "tenant"},
+		{name: "body contains", method: http.MethodPut, path: "/anything", body: "ping!", wantStatus: 200, wantBody: "pong"},
+		{name: "unmatched", method: http.MethodPost, path: "/users", body: `{"name":"bob"}`, wantStatus: 404},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, body, err := get(t, srv, tc.method, tc.path, tc.body, tc.header)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			if status != tc.wantStatus {
+				t.Fatalf("status=%d want %d", status, tc.wantStatus)
+			}
+			if tc.wantBody != "" && body != tc.wantBody {
+				t.Fatalf("body=%q want %q", body, tc.wantBody)
+			}
+		})
+	}
+
+	if got := len(srv.Unmatched()); got != 1 {
+		t.Fatalf("unmatched=%d want 1", got)
+	}
+	srv.AssertRequests(t, 2, PathGlob("/users/*"))
+}
+
+func TestRoute_Sequence_Golden(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(r *Route)
+		want  []int
+	}{
+		{
+			name:  "default ok",
+			setup: func(r *Route) {},
+			want:  []int{200, 200},
+		},
+		{
+			name:  "last step repeats",
+			setup: func(r *Route) { r.Respond(Status(500), Status(201)) },
+			want:  []int{500, 201, 201},
+		},
+		{
+			name: "times",
+			setup: func(r *Route) {
+				r.Respond(Response{Status: 503, Times: 2}, Status(200), Status(204))
+			},
+			want: []int{503, 503, 200, 204, 204},
+		},
+		{
+			name:  "flap",
+			setup: func(r *Route) { r.Flap(2, 1, 502).Respond(Status(200)) },
+			want:  []int{502, 502, 200, 502, 502, 200},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := NewServer(t)
+			route := srv.On(Path("/x"))
+			tc.setup(route)
+			for i, want := range tc.want {
+				status, _, err := get(t, srv, http.MethodGet, "/x", "", nil)
+				if err != nil {
+					t.Fatalf("request %d: %v", i, err)
+				}
+				if status != want {
+					t.Fatalf("request %d status=%d want %d", i, status, want)
+				}
+			}
+			if route.Hits() != len(tc.want) {
+				t.Fatalf("hits=%d want %d", route.Hits(), len(tc.want))
+			}
+		})
+	}
+}
+
+func TestServer_LatencyAndReset_Golden(t *testing.T) {
+	srv := NewServer(t)
+	srv.On(Path("/slow")).Latency(50 * time.Millisecond).Respond(Status(200))
+	srv.On(Path("/reset")).Respond(ResetConnection())
+
+	start := time.Now()
+	if _, _, err := get(t, srv, http.MethodGet, "/slow", "", nil); err != nil {
+		t.Fatalf("slow: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("latency not applied: %v", elapsed)
+	}
+
+	if _, _, err := get(t, srv, http.MethodGet, "/reset", "", nil); err == nil {
+		t.Fatalf("expected error from reset connection")
+	}
+}
+
+func TestFixture_RetryAndAuth_Golden(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     []FixtureOption
+		setup    func(f *Fixture)
+		wantErr  bool
+		wantHits int
+	}{
+		{
+			name: "retries through flapping 5xx",
+			setup: func(f *Fixture) {
+				f.Server.On(Path("/flaky")).Flap(2, 1, 503).Respond(JSON(200, map[string]bool{"ok": true}))
+			},
+			wantHits: 3,
+		},
+		{
+			name: "connection reset is not temporary",
+			setup: func(f *Fixture) {
+				f.Server.On(Path("/flaky")).Respond(ResetConnection(), Status(200))
+			},
+			wantErr:  true,
+			wantHits: 1,
+		},
+		{
+			name: "gives up after max retries",
+			setup: func(f *Fixture) {
+				f.Server.On(Path("/flaky")).Respond(Status(500))
+			},
+			wantErr:  true,
+			wantHits: 4,
+		},
+		{
+			name: "auth header sent",
+			opts: []FixtureOption{WithHTTPClientConfig(authConfig("s3cret"))},
+			setup: func(f *Fixture) {
+				f.Server.On(Path("/flaky"), Header("Authorization", "Bearer s3cret")).Respond(Status(200))
+			},
+			wantHits: 1,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFixture(t, tc.opts...)
+			tc.setup(f)
+
+			_, err := f.Svc.RequestWithRetry(context.Background(), f.Get("/flaky"))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tc.wantErr)
+			}
+			f.Server.AssertRequests(t, tc.wantHits, Path("/flaky"))
+			f.Server.AssertNoUnmatched(t)
+		})
+	}
+}
+
+func TestFixture_Download_Golden(t *testing.T) {
+	f := NewFixture(t)
+	f.Server.On(Method(http.MethodGet), Path("/files/app.tar.gz")).Respond(Bytes(200, []byte("archive")))
+
+	cfg := f.DownloadConfig("/files/app.tar.gz")
+	cfg.SkipAllowedPaths = true
+	cfg.Blocking = true
+	path, err := f.Svc.DownloadFile(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("DownloadFile: %v", err)
+	}
+	if filepath.Base(path) != "app.tar.gz" {
+		t.Fatalf("path=%s", path)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil || string(got) != "archive" {
+		t.Fatalf("content=%q err=%v", got, err)
+	}
+}
+
+func authConfig(token string) httpclient.HTTPClientConfig {
+	cfg := httpclient.DefaultHTTPClientConfig()
+	cfg.WithAuthProvider(staticAuth{token: token})
+	return cfg
+}
+
+type staticAuth struct {
+	token string
+}
+
+func (s staticAuth) Authenticate(ctx context.Context) (dto.TokenInfo, error) {
+	return dto.TokenInfo{AccessToken: s.token, Expiry: time.Now().Add(time.Hour)}, nil
+}
+
+func (s staticAuth) Refresh(ctx context.Context, old dto.TokenInfo) (dto.TokenInfo, error) {
+	return dto.TokenInfo{}, errors.New("refresh not supported")
+}
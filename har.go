@@ -0,0 +1,47 @@
+package gonetic
+
+import (
+	"fmt"
+
+	"github.com/joy-dx/gonetic/client/httpclient"
+)
+
+// harRecordable is implemented by clients that can record HAR archives.
+type harRecordable interface {
+	StartHAR(recorder *httpclient.HARRecorder)
+	StopHAR() *httpclient.HARRecorder
+}
+
+// StartHAR records the requests of client ref into recorder until StopHAR.
+// One recorder may be shared by several clients. DownloadFile transfers are
+// not recorded.
+func (s *NetSvc) StartHAR(ref string, recorder *httpclient.HARRecorder) error {
+	client, err := s.harClient(ref)
+	if err != nil {
+		return err
+	}
+	client.StartHAR(recorder)
+	return nil
+}
+
+// StopHAR stops recording client ref and returns its recorder, nil if it was
+// not recording.
+func (s *NetSvc) StopHAR(ref string) (*httpclient.HARRecorder, error) {
+	client, err := s.harClient(ref)
+	if err != nil {
+		return nil, err
+	}
+	return client.StopHAR(), nil
+}
+
+func (s *NetSvc) harClient(ref string) (harRecordable, error) {
+	netClient, isOK := s.Client(ref)
+	if !isOK {
+		return nil, fmt.Errorf("client not found: %s", ref)
+	}
+	recordable, isOK := netClient.(harRecordable)
+	if !isOK {
+		return nil, fmt.Errorf("client %s does not support HAR recording", ref)
+	}
+	return recordable, nil
+}
@@ -0,0 +1,53 @@
+package gonetic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joy-dx/gonetic/client/httpclient"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+func TestNetSvc_HAR_Golden(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	s := newTestSvc(t)
+	if err := s.Hydrate(context.Background()); err != nil {
+		t.Fatalf("Hydrate: %v", err)
+	}
+	s.RegisterClient("plain", &fakeNetClient{ref: "plain", typ: "fake"})
+
+	recorder := httpclient.NewHARRecorder(httpclient.DefaultHARConfig())
+	if err := s.StartHAR("missing", recorder); err == nil {
+		t.Fatalf("expected error for unknown client")
+	}
+	if err := s.StartHAR("plain", recorder); err == nil {
+		t.Fatalf("expected error for client without HAR support")
+	}
+	if err := s.StartHAR(dto.NET_DEFAULT_CLIENT_REF, recorder); err != nil {
+		t.Fatalf("StartHAR: %v", err)
+	}
+
+	if _, err := s.Get(context.Background(), srv.URL, false); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	stopped, err := s.StopHAR(dto.NET_DEFAULT_CLIENT_REF)
+	if err != nil || stopped != recorder {
+		t.Fatalf("StopHAR: %p %v", stopped, err)
+	}
+	if _, err := s.Get(context.Background(), srv.URL, false); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	entries := recorder.Entries()
+	if len(entries) != 1 || entries[0].ClientRef != dto.NET_DEFAULT_CLIENT_REF || entries[0].Response.Content.Text != "ok" {
+		t.Fatalf("entries=%+v", entries)
+	}
+}
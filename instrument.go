@@ -0,0 +1,223 @@
+package gonetic
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/relays"
+	"github.com/joy-dx/gonetic/telemetry"
+)
+
+// maxLoggedBody caps bodies added to relay events when LogBodies is set.
+const maxLoggedBody = 4 << 10
+
+// requestObserver reports one RequestOnce or RequestWithRetry call as relay
+// events, spans and metrics.
+type requestObserver struct {
+	svc     *NetSvc
+	tracer  telemetry.Tracer
+	meter   telemetry.Meter
+	info    relays.RequestInfo
+	started time.Time
+	// spanAttrs go on every span of the call; metricAttrs are the low
+	// cardinality subset used on measurements.
+	spanAttrs   []telemetry.Attribute
+	metricAttrs []telemetry.Attribute
+}
+
+func (s *NetSvc) observeRequest(cfg *dto.RequestConfig) *requestObserver {
+	provider := s.cfg.Instrumentation()
+	o := &requestObserver{svc: s, tracer: provider.Tracer(), meter: provider.Meter()}
+	if cfg == nil {
+		return o
+	}
+
+	o.info = relays.RequestInfo{TaskName: cfg.TaskName, ClientRef: cfg.ClientRef}
+	o.metricAttrs = append(o.metricAttrs, telemetry.String(telemetry.AttrClientRef, cfg.ClientRef))
+	if cfg.ReqConfig != nil {
+		o.metricAttrs = append(o.metricAttrs, telemetry.String(telemetry.AttrClientType, string(cfg.ReqConfig.Ref())))
+	}
+	if describer, ok := cfg.ReqConfig.(dto.RequestDescriber); ok {
+		if desc, err := describer.Describe(); err == nil {
+			redactor := s.cfg.Redactor
+			o.info.Method = desc.Method
+			o.info.URL = redactor.URL(desc.URL)
+			o.info.Headers = redactor.HeaderMap(desc.Headers)
+			o.info.Size = len(desc.Body)
+			if s.cfg.LogBodies {
+				o.info.Body = truncateBody(redactor.Body(desc.Body))
+			}
+			o.metricAttrs = append(o.metricAttrs, telemetry.String(telemetry.AttrMethod, desc.Method))
+			if target, err := url.Parse(desc.URL); err == nil && target.Host != "" {
+				o.metricAttrs = append(o.metricAttrs, telemetry.String(telemetry.AttrHost, target.Host))
+			}
+		}
+	}
+	o.spanAttrs = append(o.spanAttrs, o.metricAttrs...)
+	o.spanAttrs = append(o.spanAttrs, telemetry.Int(telemetry.AttrRequestSize, o.info.Size))
+	if cfg.TaskName != "" {
+		o.spanAttrs = append(o.spanAttrs, telemetry.String(telemetry.AttrTaskName, cfg.TaskName))
+	}
+	return o
+}
+
+// begin publishes the start event and opens the request span.
+func (o *requestObserver) begin(ctx context.Context, maxAttempts int) (context.Context, telemetry.Span) {
+	o.started = time.Now()
+	o.svc.relay.Debug(relays.RlyNetRequestStart{Request: o.info, MaxAttempts: maxAttempts})
+	return o.tracer.Start(ctx, telemetry.SpanRequest, o.spanAttrs...)
+}
+
+// finish closes the request span opened by begin.
+func (o *requestObserver) finish(ctx context.Context, span telemetry.Span, resp dto.Response, err error, attempt int, attrs ...telemetry.Attribute) {
+	if err != nil {
+		o.svc.relay.Warn(relays.RlyNetRequestFailed{
+			Request:    o.info,
+			Attempt:    attempt,
+			StatusCode: resp.StatusCode,
+			Duration:   time.Since(o.started),
+			Error:      o.svc.cfg.Redactor.Error(err),
+		})
+	}
+	o.end(ctx, span, telemetry.MetricRequestDuration, o.started, resp, err, attrs...)
+}
+
+// retry publishes that attempt is scheduled after resp or err.
+func (o *requestObserver) retry(attempt int, resp dto.Response, err error) {
+	o.svc.relay.Info(relays.RlyNetRequestRetry{
+		Request:    o.info,
+		Attempt:    attempt,
+		StatusCode: resp.StatusCode,
+		Reason:     o.svc.cfg.Redactor.Error(err),
+	})
+}
+
+// end closes span with the outcome of resp and err and records its duration
+// in metric.
+func (o *requestObserver) end(ctx context.Context, span telemetry.Span, metric string, started time.Time, resp dto.Response, err error, attrs ...telemetry.Attribute) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+		span.RecordError(err)
+	}
+	resultAttrs := []telemetry.Attribute{telemetry.String(telemetry.AttrOutcome, outcome)}
+	if resp.StatusCode != 0 {
+		resultAttrs = append(resultAttrs, telemetry.Int(telemetry.AttrStatusCode, resp.StatusCode))
+	}
+	span.SetAttributes(append(attrs, resultAttrs...)...)
+	span.SetAttributes(telemetry.Int(telemetry.AttrResponseSize, len(resp.Body)))
+	span.End()
+
+	metricAttrs := append(append([]telemetry.Attribute{}, o.metricAttrs...), resultAttrs...)
+	o.meter.Record(ctx, metric, time.Since(started).Seconds(), metricAttrs...)
+	if err != nil && metric == telemetry.MetricRequestDuration {
+		o.meter.Add(ctx, telemetry.MetricRequestErrors, 1, metricAttrs...)
+	}
+}
+
+// attempt runs one observed requestOnce as a child span.
+func (s *NetSvc) attempt(ctx context.Context, cfg *dto.RequestConfig, o *requestObserver, attempt int) (dto.Response, error) {
+	if attempt > 0 {
+		o.meter.Add(ctx, telemetry.MetricRequestRetries, 1, o.metricAttrs...)
+	}
+	s.relay.Debug(relays.RlyNetRequestAttempt{Request: o.info, Attempt: attempt})
+
+	started := time.Now()
+	ctx, span := o.tracer.Start(ctx, telemetry.SpanAttempt, append([]telemetry.Attribute{telemetry.Int(telemetry.AttrAttempt, attempt)}, o.spanAttrs...)...)
+	resp, err := s.requestOnce(ctx, cfg)
+	o.end(ctx, span, telemetry.MetricAttemptDuration, started, resp, err, o.recordTiming(ctx, resp.Timing)...)
+
+	if resp.StatusCode != 0 {
+		event := relays.RlyNetRequestResponse{
+			Request:    o.info,
+			Attempt:    attempt,
+			StatusCode: resp.StatusCode,
+			Duration:   time.Since(started),
+			Headers:    flattenHeader(s.cfg.Redactor.Header(resp.Headers)),
+			Size:       len(resp.Body),
+			Timing:     resp.Timing,
+		}
+		if s.cfg.LogBodies {
+			event.Body = truncateBody(s.cfg.Redactor.Body(resp.Body))
+		}
+		s.relay.Debug(event)
+	}
+	return resp, err
+}
+
+// recordTiming records the connection phases in timing and returns them as
+// span attributes.
+func (o *requestObserver) recordTiming(ctx context.Context, timing *dto.ResponseTiming) []telemetry.Attribute {
+	if timing == nil {
+		return nil
+	}
+	phases := []struct {
+		metric   string
+		duration time.Duration
+	}{
+		{telemetry.MetricDNSDuration, timing.DNSLookup},
+		{telemetry.MetricConnectDuration, timing.TCPConnect},
+		{telemetry.MetricTLSDuration, timing.TLSHandshake},
+		{telemetry.MetricTimeToFirstByte, timing.TimeToFirstByte},
+	}
+	for _, phase := range phases {
+		if phase.duration > 0 {
+			o.meter.Record(ctx, phase.metric, phase.duration.Seconds(), o.metricAttrs...)
+		}
+	}
+	return []telemetry.Attribute{
+		telemetry.Bool(telemetry.AttrConnReused, timing.ConnReused),
+		telemetry.String(telemetry.AttrPeerAddress, timing.RemoteAddr),
+		telemetry.String(telemetry.AttrProtocol, timing.Protocol),
+	}
+}
+
+func flattenHeader(h http.Header) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		out[k] = strings.Join(v, ", ")
+	}
+	return out
+}
+
+func truncateBody(body []byte) string {
+	if len(body) > maxLoggedBody {
+		return string(body[:maxLoggedBody]) + "...(truncated)"
+	}
+	return string(body)
+}
+
+// instrumentDownload records a download span and, on success, the bytes
+// written and throughput.
+func (s *NetSvc) instrumentDownload(ctx context.Context, cfg *dto.DownloadFileConfig) (context.Context, func(size int64, err error)) {
+	provider := s.cfg.Instrumentation()
+	attrs := []telemetry.Attribute{telemetry.String(telemetry.AttrClientRef, cfg.ClientRef)}
+	if target, err := url.Parse(cfg.URL); err == nil && target.Host != "" {
+		attrs = append(attrs, telemetry.String(telemetry.AttrHost, target.Host))
+	}
+	started := time.Now()
+	ctx, span := provider.Tracer().Start(ctx, telemetry.SpanDownload, attrs...)
+	return ctx, func(size int64, err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetAttributes(telemetry.String(telemetry.AttrOutcome, "error"))
+			span.End()
+			return
+		}
+		span.SetAttributes(telemetry.String(telemetry.AttrOutcome, "success"), telemetry.Int64(telemetry.AttrResponseSize, size))
+		span.End()
+
+		meter := provider.Meter()
+		meter.Add(ctx, telemetry.MetricDownloadBytes, float64(size), attrs...)
+		if elapsed := time.Since(started).Seconds(); elapsed > 0 {
+			meter.Record(ctx, telemetry.MetricDownloadThroughput, float64(size)/elapsed, attrs...)
+		}
+	}
+}
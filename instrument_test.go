@@ -0,0 +1,312 @@
+package gonetic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/joy-dx/gonetic/client/httpclient"
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/relays"
+	"github.com/joy-dx/gonetic/telemetry"
+	"github.com/joy-dx/gonetic/utils"
+	relayDTO "github.com/joy-dx/relay/dto"
+)
+
+func TestNetSvc_Instrumentation_Golden(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+	var traceParent atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent.Store(r.Header.Get(telemetry.TraceParentHeader))
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	recorder := telemetry.NewRecorder()
+	cfg := config.DefaultNetSvcConfig()
+	cfg.WithRelay(&fakeRelay{}).WithInstrumentation(recorder)
+	s, err := New(&cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := s.Hydrate(context.Background()); err != nil {
+		t.Fatalf("Hydrate: %v", err)
+	}
+
+	httpCfg := httpclient.DefaultHTTPRequestConfig()
+	httpCfg.WithURL(srv.URL + "/thing")
+	req := dto.DefaultRequestConfig()
+	req.WithReqConfig(&httpCfg).WithMaxRetries(2).WithDelay(utils.ConstantDelay{}).WithTaskName("fetch thing")
+	if _, err := s.RequestWithRetry(context.Background(), &req); err != nil {
+		t.Fatalf("RequestWithRetry: %v", err)
+	}
+
+	spans := recorder.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("spans=%d want 3", len(spans))
+	}
+	root := spans[2]
+	if root.Name != telemetry.SpanRequest || root.Parent.IsValid() {
+		t.Fatalf("root span=%+v", root)
+	}
+	for i, attempt := range spans[:2] {
+		if attempt.Name != telemetry.SpanAttempt || attempt.Parent != root.Context || attempt.Attributes[telemetry.AttrAttempt] != int64(i) {
+			t.Fatalf("attempt span %d=%+v", i, attempt)
+		}
+	}
+	wantAttrs := map[string]any{
+		telemetry.AttrClientRef:    dto.DefaultRequestConfig().ClientRef,
+		telemetry.AttrClientType:   string(httpCfg.Ref()),
+		telemetry.AttrTaskName:     "fetch thing",
+		telemetry.AttrMethod:       http.MethodGet,
+		telemetry.AttrHost:         srv.Listener.Addr().String(),
+		telemetry.AttrStatusCode:   int64(http.StatusOK),
+		telemetry.AttrResponseSize: int64(5),
+		telemetry.AttrRetries:      int64(1),
+		telemetry.AttrOutcome:      "success",
+	}
+	for key, want := range wantAttrs {
+		if got := root.Attributes[key]; got != want {
+			t.Fatalf("root %s=%v want %v", key, got, want)
+		}
+	}
+	if got := spans[0].Attributes[telemetry.AttrStatusCode]; got != int64(http.StatusBadGateway) {
+		t.Fatalf("first attempt status=%v", got)
+	}
+
+	if got := traceParent.Load().(string); got != spans[1].Context.TraceParent() {
+		t.Fatalf("traceparent=%q want %q", got, spans[1].Context.TraceParent())
+	}
+	if got := recorder.Sum(telemetry.MetricRequestRetries); got != 1 {
+		t.Fatalf("retries=%v want 1", got)
+	}
+	if got := len(recorder.Measurements(telemetry.MetricAttemptDuration)); got != 2 {
+		t.Fatalf("attempt durations=%d want 2", got)
+	}
+	if got := len(recorder.Measurements(telemetry.MetricRequestDuration)); got != 1 {
+		t.Fatalf("request durations=%d want 1", got)
+	}
+	if got := len(recorder.Measurements(telemetry.MetricRequestErrors)); got != 0 {
+		t.Fatalf("errors=%d want 0", got)
+	}
+
+	recorder.Reset()
+	dir := t.TempDir()
+	download := dto.DownloadFileConfig{URL: srv.URL + "/file.txt", DestinationFolder: dir}
+	dest, err := s.DownloadFile(context.Background(), &download)
+	if err != nil {
+		t.Fatalf("DownloadFile: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "file.txt")); err != nil || dest != filepath.Join(dir, "file.txt") {
+		t.Fatalf("dest=%s err=%v", dest, err)
+	}
+	if spans := recorder.Spans(); len(spans) != 1 || spans[0].Name != telemetry.SpanDownload {
+		t.Fatalf("download spans=%+v", spans)
+	}
+	if got := recorder.Sum(telemetry.MetricDownloadBytes); got != 5 {
+		t.Fatalf("download bytes=%v want 5", got)
+	}
+	if got := len(recorder.Measurements(telemetry.MetricDownloadThroughput)); got != 1 {
+		t.Fatalf("throughput samples=%d want 1", got)
+	}
+}
+
+func TestNetSvc_RequestEvents_Golden(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cr3t"})
+		_, _ = w.Write([]byte(`{"access_token":"tok-123","user":"bob"}`))
+	}))
+	defer srv.Close()
+
+	relay := &fakeRelay{}
+	cfg := config.DefaultNetSvcConfig()
+	cfg.WithRelay(relay).WithLogBodies(true)
+	s, err := New(&cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := s.Hydrate(context.Background()); err != nil {
+		t.Fatalf("Hydrate: %v", err)
+	}
+
+	httpCfg := httpclient.DefaultHTTPRequestConfig()
+	httpCfg.WithMethod(http.MethodPost).
+		WithURL(srv.URL + "/login?token=abc&page=2").
+		WithHeaders(map[string]string{"Authorization": "Bearer xyz"}).
+		WithBody(map[string]interface{}{"user": "bob", "password": "hunter2"})
+	req := dto.DefaultRequestConfig()
+	req.WithReqConfig(&httpCfg).WithMaxRetries(1).WithDelay(utils.ConstantDelay{})
+	if _, err := s.RequestWithRetry(context.Background(), &req); err != nil {
+		t.Fatalf("RequestWithRetry: %v", err)
+	}
+
+	relay.mu.Lock()
+	var types []string
+	var events []relayDTO.RelayEventInterface
+	for _, e := range relay.evts {
+		if strings.HasPrefix(string(e.RelayType()), "net.request.") {
+			types = append(types, string(e.RelayType()))
+			events = append(events, e)
+		}
+	}
+	relay.mu.Unlock()
+
+	want := []string{
+		"net.request.start", "net.request.attempt", "net.request.response",
+		"net.request.retry", "net.request.attempt", "net.request.response",
+	}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Fatalf("events=%v want %v", types, want)
+	}
+
+	start := events[0].(relays.RlyNetRequestStart)
+	if start.MaxAttempts != 2 || start.Request.Method != http.MethodPost {
+		t.Fatalf("start=%+v", start)
+	}
+	if got := start.Request.URL; strings.Contains(got, "abc") || !strings.Contains(got, "page=2") {
+		t.Fatalf("url not redacted: %s", got)
+	}
+	if got := start.Request.Headers["Authorization"]; got != utils.RedactedValue {
+		t.Fatalf("authorization=%q", got)
+	}
+	if body := start.Request.Body; strings.Contains(body, "hunter2") || !strings.Contains(body, "bob") {
+		t.Fatalf("request body not redacted: %s", body)
+	}
+
+	if retry := events[3].(relays.RlyNetRequestRetry); retry.Attempt != 1 || retry.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("retry=%+v", retry)
+	}
+	resp := events[5].(relays.RlyNetRequestResponse)
+	if resp.StatusCode != http.StatusOK || resp.Attempt != 1 || resp.Headers["Set-Cookie"] != utils.RedactedValue {
+		t.Fatalf("response=%+v", resp)
+	}
+	if strings.Contains(resp.Body, "tok-123") || !strings.Contains(resp.Body, "bob") {
+		t.Fatalf("response body not redacted: %s", resp.Body)
+	}
+	for _, e := range events {
+		for _, attr := range e.ToSlog() {
+			if v := attr.Value.String(); strings.Contains(v, "xyz") || strings.Contains(v, "s3cr3t") {
+				t.Fatalf("%s leaks secret in %s=%s", e.RelayType(), attr.Key, v)
+			}
+		}
+	}
+
+	relay.mu.Lock()
+	relay.evts = nil
+	relay.mu.Unlock()
+	broken := dto.DefaultRequestConfig()
+	broken.WithClientRef("missing").WithReqConfig(&httpCfg)
+	if _, err := s.RequestOnce(context.Background(), &broken); err == nil {
+		t.Fatalf("expected error for unknown client")
+	}
+	relay.mu.Lock()
+	last := relay.evts[len(relay.evts)-1]
+	relay.mu.Unlock()
+	if failed, ok := last.(relays.RlyNetRequestFailed); !ok || failed.Error == "" || failed.Request.ClientRef != "missing" {
+		t.Fatalf("last event=%#v", last)
+	}
+
+	denied := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer denied.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	for _, tc := range []struct {
+		name string
+		url  string
+	}{
+		{name: "unauthorized", url: denied.URL + "/private?token=abc&page=2"},
+		{name: "transport error", url: closed.URL + "/private?token=abc&page=2"},
+	} {
+		failingCfg := httpclient.DefaultHTTPRequestConfig()
+		failingCfg.WithURL(tc.url)
+		failing := dto.DefaultRequestConfig()
+		failing.WithReqConfig(&failingCfg)
+		if _, err := s.RequestOnce(context.Background(), &failing); err == nil || !strings.Contains(err.Error(), "abc") {
+			t.Fatalf("%s: caller should see the full error, got %v", tc.name, err)
+		}
+		relay.mu.Lock()
+		last = relay.evts[len(relay.evts)-1]
+		relay.mu.Unlock()
+		failed, ok := last.(relays.RlyNetRequestFailed)
+		if !ok || strings.Contains(failed.Error, "abc") || !strings.Contains(failed.Error, "token="+utils.RedactedValue) {
+			t.Fatalf("%s: failed event error not redacted: %#v", tc.name, last)
+		}
+	}
+}
+
+func TestNetSvc_ResponseTiming_Golden(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	relay := &fakeRelay{}
+	recorder := telemetry.NewRecorder()
+	cfg := config.DefaultNetSvcConfig()
+	cfg.WithRelay(relay).WithInstrumentation(recorder)
+	s, err := New(&cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	clientCfg := httpclient.DefaultHTTPClientConfig()
+	clientCfg.WithTiming(true)
+	s.RegisterClient("timed", httpclient.NewHTTPClient("timed", &cfg, &clientCfg))
+
+	httpCfg := httpclient.DefaultHTTPRequestConfig()
+	httpCfg.WithURL(srv.URL)
+	req := dto.DefaultRequestConfig()
+	req.WithClientRef("timed").WithReqConfig(&httpCfg)
+	resp, err := s.RequestOnce(context.Background(), &req)
+	if err != nil {
+		t.Fatalf("RequestOnce: %v", err)
+	}
+	if resp.Timing == nil || resp.Timing.TimeToFirstByte <= 0 {
+		t.Fatalf("timing=%+v", resp.Timing)
+	}
+
+	if got := len(recorder.Measurements(telemetry.MetricTimeToFirstByte)); got != 1 {
+		t.Fatalf("ttfb samples=%d want 1", got)
+	}
+	if got := len(recorder.Measurements(telemetry.MetricConnectDuration)); got != 1 {
+		t.Fatalf("connect samples=%d want 1", got)
+	}
+	attempt := recorder.Spans()[0]
+	if attempt.Name != telemetry.SpanAttempt || attempt.Attributes[telemetry.AttrConnReused] != false || attempt.Attributes[telemetry.AttrPeerAddress] != srv.Listener.Addr().String() {
+		t.Fatalf("attempt span=%+v", attempt)
+	}
+
+	relay.mu.Lock()
+	defer relay.mu.Unlock()
+	for _, e := range relay.evts {
+		if event, ok := e.(relays.RlyNetRequestResponse); ok {
+			if event.Timing != resp.Timing {
+				t.Fatalf("event timing=%+v want %+v", event.Timing, resp.Timing)
+			}
+			return
+		}
+	}
+	t.Fatalf("no response event")
+}
@@ -0,0 +1,61 @@
+package gonetic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/outbox"
+)
+
+// ErrOutboxDisabled is returned by Enqueue when NetSvcConfig.Outbox.Path is
+// unset.
+var ErrOutboxDisabled = errors.New("outbox disabled")
+
+// Outbox returns the durable request queue started by Hydrate, or nil when
+// it is disabled.
+func (s *NetSvc) Outbox() *outbox.Outbox {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+	return s.outbox
+}
+
+// Enqueue persists cfg to the outbox for background delivery through
+// RequestWithRetry. See outbox.Outbox.Enqueue for idempotencyKey.
+func (s *NetSvc) Enqueue(cfg dto.RequestConfig, idempotencyKey string) (id string, queued bool, err error) {
+	ob := s.Outbox()
+	if ob == nil {
+		return "", false, ErrOutboxDisabled
+	}
+	return ob.Enqueue(cfg, idempotencyKey)
+}
+
+// startOutbox opens the journal and starts delivery once per NetSvc.
+func (s *NetSvc) startOutbox() error {
+	if s.cfg.Outbox.Path == "" {
+		return nil
+	}
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+	if s.outbox != nil || s.closed {
+		return nil
+	}
+	ob, err := outbox.Open(s.cfg.Outbox, s.deliverQueued)
+	if err != nil {
+		return fmt.Errorf("open outbox: %w", err)
+	}
+	s.outbox = ob
+	ob.Start()
+	return nil
+}
+
+// deliverQueued is the outbox.Sender. Requests refused or cancelled because
+// the service is shutting down stay queued rather than counting as failures.
+func (s *NetSvc) deliverQueued(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+	resp, err := s.RequestWithRetry(ctx, cfg)
+	if err != nil && (errors.Is(err, ErrNetSvcClosed) || s.stopCtx.Err() != nil) {
+		return resp, fmt.Errorf("%w: %w", outbox.ErrSenderStopped, err)
+	}
+	return resp, err
+}
@@ -0,0 +1,186 @@
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// Journal operations. A snapshot carries the full state of one entry and is
+// what compaction writes; the others update an entry already in the journal.
+const (
+	opSnapshot  = "snapshot"
+	opAttempt   = "attempt"
+	opDelivered = "delivered"
+	opDead      = "dead"
+	opRequeue   = "requeue"
+)
+
+// record is one line of the journal.
+type record struct {
+	Op             string             `json:"op"`
+	ID             string             `json:"id"`
+	IdempotencyKey string             `json:"idempotency_key,omitempty"`
+	Status         Status             `json:"status,omitempty"`
+	Request        *dto.RequestConfig `json:"request,omitempty"`
+	Attempts       int                `json:"attempts,omitempty"`
+	Error          string             `json:"error,omitempty"`
+	EnqueuedAt     time.Time          `json:"enqueued_at,omitzero"`
+	NextAttemptAt  time.Time          `json:"next_attempt_at,omitzero"`
+	At             time.Time          `json:"at"`
+}
+
+func snapshotOf(e *Entry) record {
+	rec := record{
+		Op:             opSnapshot,
+		ID:             e.ID,
+		IdempotencyKey: e.IdempotencyKey,
+		Status:         e.Status,
+		Attempts:       e.Attempts,
+		Error:          e.LastError,
+		EnqueuedAt:     e.EnqueuedAt,
+		NextAttemptAt:  e.NextAttemptAt,
+		At:             e.UpdatedAt,
+	}
+	if e.Status != StatusDelivered {
+		request := e.Request
+		rec.Request = &request
+	}
+	return rec
+}
+
+// readJournal replays the journal at path into entries in enqueue order. A
+// final line cut short by a crash is ignored.
+func readJournal(path string) ([]*Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open journal: %w", err)
+	}
+	defer f.Close()
+
+	var order []*Entry
+	byID := map[string]*Entry{}
+	reader := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		raw, readErr := reader.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return nil, fmt.Errorf("read journal: %w", readErr)
+		}
+		raw = bytes.TrimSpace(raw)
+		if len(raw) > 0 {
+			var rec record
+			if err := json.Unmarshal(raw, &rec); err != nil {
+				if readErr != nil {
+					break // torn final write
+				}
+				return nil, fmt.Errorf("journal line %d: %w", line, err)
+			}
+			if entry := apply(byID, rec); entry != nil {
+				order = append(order, entry)
+			}
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	return order, nil
+}
+
+// apply folds rec into byID and returns the entry when rec creates one.
+func apply(byID map[string]*Entry, rec record) *Entry {
+	entry, exists := byID[rec.ID]
+	switch rec.Op {
+	case opSnapshot:
+		e := &Entry{
+			ID:             rec.ID,
+			IdempotencyKey: rec.IdempotencyKey,
+			Status:         rec.Status,
+			Attempts:       rec.Attempts,
+			LastError:      rec.Error,
+			EnqueuedAt:     rec.EnqueuedAt,
+			NextAttemptAt:  rec.NextAttemptAt,
+			UpdatedAt:      rec.At,
+		}
+		if rec.Request != nil {
+			e.Request = *rec.Request
+		}
+		if exists {
+			*entry = *e
+			return nil
+		}
+		byID[rec.ID] = e
+		return e
+	case opAttempt:
+		if exists {
+			entry.Attempts = rec.Attempts
+			entry.LastError = rec.Error
+			entry.NextAttemptAt = rec.NextAttemptAt
+			entry.UpdatedAt = rec.At
+		}
+	case opDelivered:
+		if exists {
+			entry.Status = StatusDelivered
+			entry.Request = dto.RequestConfig{}
+			entry.UpdatedAt = rec.At
+		}
+	case opDead:
+		if exists {
+			entry.Status = StatusDead
+			entry.Attempts = rec.Attempts
+			entry.LastError = rec.Error
+			entry.UpdatedAt = rec.At
+		}
+	case opRequeue:
+		if exists {
+			entry.Status = StatusPending
+			entry.Attempts = 0
+			entry.NextAttemptAt = rec.At
+			entry.UpdatedAt = rec.At
+		}
+	}
+	return nil
+}
+
+// writeJournal atomically replaces the journal with one snapshot per entry.
+func writeJournal(path string, entries []*Entry) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("compact journal: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, e := range entries {
+		if err := enc.Encode(snapshotOf(e)); err != nil {
+			tmp.Close()
+			return fmt.Errorf("compact journal: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("compact journal: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("compact journal: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("compact journal: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("compact journal: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,459 @@
+// Package outbox is a durable queue of outbound requests. Entries are written
+// to an append-only journal before Enqueue returns and are delivered by a
+// single background worker, in enqueue order, at least once.
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// compactEvery is the number of journal appends after which the journal is
+// rewritten without delivered entries outside the dedupe window.
+const compactEvery = 500
+
+var (
+	// ErrClosed is returned by Enqueue after Close.
+	ErrClosed = errors.New("outbox closed")
+	// ErrSenderStopped is returned, wrapped, by a Sender that can no longer
+	// deliver. The entry is left untouched and the worker stops.
+	ErrSenderStopped = errors.New("outbox sender stopped")
+)
+
+// Sender delivers one queued request, typically NetSvc.RequestWithRetry so
+// the request's own retry policy applies to each delivery attempt.
+type Sender func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusDelivered Status = "delivered"
+	StatusDead      Status = "dead"
+)
+
+// Entry is a queued request and its delivery state.
+type Entry struct {
+	ID             string            `json:"id"`
+	IdempotencyKey string            `json:"idempotency_key,omitempty"`
+	Request        dto.RequestConfig `json:"request"`
+	Status         Status            `json:"status"`
+	// Attempts counts failed deliveries
+	Attempts      int       `json:"attempts"`
+	LastError     string    `json:"last_error,omitempty"`
+	EnqueuedAt    time.Time `json:"enqueued_at"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type Outbox struct {
+	cfg  config.OutboxConfig
+	send Sender
+
+	mu              sync.Mutex
+	file            *os.File
+	entries         []*Entry
+	byID            map[string]*Entry
+	byKey           map[string]*Entry
+	appends         int
+	closed          bool
+	lastDeliveredAt time.Time
+	lastError       string
+
+	wake    chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started bool
+	done    chan struct{}
+}
+
+// Open loads the journal at cfg.Path, compacts it and returns an Outbox ready
+// for Start. Zero config values fall back to config.DefaultOutboxConfig.
+func Open(cfg config.OutboxConfig, send Sender) (*Outbox, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("outbox path required")
+	}
+	if send == nil {
+		return nil, errors.New("outbox sender required")
+	}
+	defaults := config.DefaultOutboxConfig()
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaults.MaxAttempts
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = defaults.RetryInterval
+	}
+	if cfg.MaxRetryInterval < cfg.RetryInterval {
+		cfg.MaxRetryInterval = max(defaults.MaxRetryInterval, cfg.RetryInterval)
+	}
+	if cfg.DedupeWindow <= 0 {
+		cfg.DedupeWindow = defaults.DedupeWindow
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("create outbox dir: %w", err)
+	}
+	entries, err := readJournal(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	o := &Outbox{
+		cfg:    cfg,
+		send:   send,
+		byID:   map[string]*Entry{},
+		byKey:  map[string]*Entry{},
+		wake:   make(chan struct{}, 1),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	for _, e := range entries {
+		o.index(e)
+	}
+	if err := o.compact(time.Now()); err != nil {
+		cancel()
+		return nil, err
+	}
+	return o, nil
+}
+
+// Start runs the delivery worker until Close.
+func (o *Outbox) Start() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.started || o.closed {
+		return
+	}
+	o.started = true
+	go o.run()
+}
+
+// Close stops the worker, cancelling a delivery in progress, and closes the
+// journal. Undelivered entries are kept for the next Open.
+func (o *Outbox) Close() error {
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return nil
+	}
+	o.closed = true
+	started := o.started
+	o.mu.Unlock()
+
+	o.cancel()
+	if started {
+		<-o.done
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.file.Close()
+}
+
+// Enqueue persists cfg and schedules its delivery. When idempotencyKey is set
+// and already known (pending, dead, or delivered within the dedupe window)
+// nothing is queued and the existing entry's ID is returned with queued false.
+func (o *Outbox) Enqueue(cfg dto.RequestConfig, idempotencyKey string) (id string, queued bool, err error) {
+	if cfg.ReqConfig == nil {
+		return "", false, dto.ErrNilReqConfig
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return "", false, ErrClosed
+	}
+	if existing, ok := o.byKey[idempotencyKey]; ok && idempotencyKey != "" {
+		return existing.ID, false, nil
+	}
+
+	now := time.Now().UTC()
+	entry := &Entry{
+		ID:             newID(),
+		IdempotencyKey: idempotencyKey,
+		Request:        cfg,
+		Status:         StatusPending,
+		EnqueuedAt:     now,
+		NextAttemptAt:  now,
+		UpdatedAt:      now,
+	}
+	if err := o.append(snapshotOf(entry)); err != nil {
+		return "", false, err
+	}
+	o.index(entry)
+	o.notify()
+	return entry.ID, true, nil
+}
+
+// Entries returns copies of the entries with the given status in enqueue
+// order, or all entries when status is empty.
+func (o *Outbox) Entries(status Status) []Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var out []Entry
+	for _, e := range o.entries {
+		if status == "" || e.Status == status {
+			out = append(out, *e)
+		}
+	}
+	return out
+}
+
+// DeadLetters returns the entries that exhausted their attempts or were
+// rejected permanently.
+func (o *Outbox) DeadLetters() []Entry {
+	return o.Entries(StatusDead)
+}
+
+// Requeue moves a dead-lettered entry back to pending with its attempts reset.
+func (o *Outbox) Requeue(id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entry, ok := o.byID[id]
+	if !ok || entry.Status != StatusDead {
+		return fmt.Errorf("dead letter not found: %s", id)
+	}
+	now := time.Now().UTC()
+	if err := o.append(record{Op: opRequeue, ID: id, At: now}); err != nil {
+		return err
+	}
+	entry.Status = StatusPending
+	entry.Attempts = 0
+	entry.NextAttemptAt = now
+	entry.UpdatedAt = now
+	o.notify()
+	return nil
+}
+
+// State reports queue depth for NetSvc.State.
+func (o *Outbox) State() dto.OutboxState {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	state := dto.OutboxState{
+		Path:            o.cfg.Path,
+		LastDeliveredAt: o.lastDeliveredAt,
+		LastError:       o.lastError,
+	}
+	for _, e := range o.entries {
+		switch e.Status {
+		case StatusPending:
+			state.Pending++
+			if state.OldestPendingAt.IsZero() || e.EnqueuedAt.Before(state.OldestPendingAt) {
+				state.OldestPendingAt = e.EnqueuedAt
+			}
+		case StatusDead:
+			state.Dead++
+		case StatusDelivered:
+			state.Delivered++
+		}
+	}
+	return state
+}
+
+func (o *Outbox) run() {
+	defer close(o.done)
+	for {
+		entry, wait := o.next()
+		if entry != nil {
+			if !o.deliver(entry) {
+				return
+			}
+			continue
+		}
+
+		var timer *time.Timer
+		var due <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			due = timer.C
+		}
+		select {
+		case <-o.ctx.Done():
+		case <-o.wake:
+		case <-due:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if o.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// next returns a copy of the first due pending entry, or how long until one
+// is due. A zero wait means nothing is pending.
+func (o *Outbox) next() (*Entry, time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := time.Now()
+	var soonest time.Time
+	for _, e := range o.entries {
+		if e.Status != StatusPending {
+			continue
+		}
+		if !e.NextAttemptAt.After(now) {
+			entry := *e
+			return &entry, 0
+		}
+		if soonest.IsZero() || e.NextAttemptAt.Before(soonest) {
+			soonest = e.NextAttemptAt
+		}
+	}
+	if soonest.IsZero() {
+		return nil, 0
+	}
+	return nil, soonest.Sub(now)
+}
+
+// deliver sends one entry and records the outcome. It returns false when the
+// worker should stop.
+func (o *Outbox) deliver(entry *Entry) bool {
+	request := entry.Request
+	resp, err := o.send(o.ctx, &request)
+	if resp.Stream != nil {
+		_ = resp.Stream.Close()
+	}
+	if errors.Is(err, ErrSenderStopped) || o.ctx.Err() != nil {
+		return false
+	}
+	if err == nil && resp.StatusCode >= http.StatusBadRequest {
+		err = fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	current, ok := o.byID[entry.ID]
+	if !ok || current.Status != StatusPending {
+		return true
+	}
+	now := time.Now().UTC()
+
+	var rec record
+	switch {
+	case err == nil:
+		rec = record{Op: opDelivered, ID: entry.ID, At: now}
+	case permanent(resp.StatusCode) || current.Attempts+1 >= o.cfg.MaxAttempts:
+		rec = record{Op: opDead, ID: entry.ID, Attempts: current.Attempts + 1, Error: err.Error(), At: now}
+	default:
+		rec = record{
+			Op:            opAttempt,
+			ID:            entry.ID,
+			Attempts:      current.Attempts + 1,
+			Error:         err.Error(),
+			NextAttemptAt: now.Add(o.backoff(current.Attempts + 1)),
+			At:            now,
+		}
+	}
+	if appendErr := o.append(rec); appendErr != nil {
+		// The outcome is lost; the entry stays pending and is sent again.
+		o.lastError = appendErr.Error()
+		return true
+	}
+	apply(o.byID, rec)
+	if err == nil {
+		o.lastDeliveredAt = now
+	} else {
+		o.lastError = err.Error()
+	}
+	if o.appends >= compactEvery {
+		if err := o.compact(now); err != nil {
+			o.lastError = err.Error()
+		}
+	}
+	return true
+}
+
+// permanent reports client errors that a retry will not fix.
+func permanent(status int) bool {
+	return status >= 400 && status < 500 &&
+		status != http.StatusRequestTimeout && status != http.StatusTooManyRequests
+}
+
+func (o *Outbox) backoff(attempts int) time.Duration {
+	wait := o.cfg.RetryInterval
+	for i := 1; i < attempts && wait < o.cfg.MaxRetryInterval; i++ {
+		wait *= 2
+	}
+	return min(wait, o.cfg.MaxRetryInterval)
+}
+
+// append writes rec to the journal and syncs it. Callers hold o.mu.
+func (o *Outbox) append(rec record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode outbox entry: %w", err)
+	}
+	if _, err := o.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write journal: %w", err)
+	}
+	if err := o.file.Sync(); err != nil {
+		return fmt.Errorf("sync journal: %w", err)
+	}
+	o.appends++
+	return nil
+}
+
+// compact drops delivered entries older than the dedupe window and rewrites
+// the journal. Callers hold o.mu, except during Open.
+func (o *Outbox) compact(now time.Time) error {
+	var kept []*Entry
+	for _, e := range o.entries {
+		if e.Status == StatusDelivered && now.Sub(e.UpdatedAt) > o.cfg.DedupeWindow {
+			delete(o.byID, e.ID)
+			if e.IdempotencyKey != "" {
+				delete(o.byKey, e.IdempotencyKey)
+			}
+			continue
+		}
+		kept = append(kept, e)
+	}
+	o.entries = kept
+
+	if err := writeJournal(o.cfg.Path, o.entries); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(o.cfg.Path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open journal: %w", err)
+	}
+	if o.file != nil {
+		_ = o.file.Close()
+	}
+	o.file = file
+	o.appends = 0
+	return nil
+}
+
+func (o *Outbox) index(e *Entry) {
+	o.entries = append(o.entries, e)
+	o.byID[e.ID] = e
+	if e.IdempotencyKey != "" {
+		o.byKey[e.IdempotencyKey] = e
+	}
+}
+
+func (o *Outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+func newID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return fmt.Sprintf("%d-%s", time.Now().UnixNano(), hex.EncodeToString(b[:]))
+}
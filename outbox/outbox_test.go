@@ -0,0 +1,287 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
+)
+
+const testReqType dto.NetClientType = "test.outbox"
+
+type testReq struct {
+	Name string `json:"name"`
+}
+
+func (r *testReq) Ref() dto.NetClientType                      { return testReqType }
+func (r *testReq) NewRequest(ctx context.Context) (any, error) { return r, nil }
+
+func init() {
+	dto.RegisterReqConfig(testReqType, func() dto.ReqConfigInterface { return &testReq{} })
+}
+
+func testRequest(name string) dto.RequestConfig {
+	cfg := dto.DefaultRequestConfig()
+	cfg.WithReqConfig(&testReq{Name: name}).WithDelay(utils.ConstantDelay{})
+	return cfg
+}
+
+// recorder is a Sender answering from a per-name status script.
+type recorder struct {
+	mu     sync.Mutex
+	sent   []string
+	status func(name string, n int) (int, error)
+}
+
+func (r *recorder) send(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+	name := cfg.ReqConfig.(*testReq).Name
+	r.mu.Lock()
+	r.sent = append(r.sent, name)
+	n := 0
+	for _, s := range r.sent {
+		if s == name {
+			n++
+		}
+	}
+	r.mu.Unlock()
+	status, err := r.status(name, n)
+	return dto.Response{StatusCode: status}, err
+}
+
+func (r *recorder) sentNames() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.sent...)
+}
+
+func testConfig(t *testing.T) config.OutboxConfig {
+	cfg := config.DefaultOutboxConfig()
+	cfg.WithPath(filepath.Join(t.TempDir(), "outbox", "journal.jsonl")).
+		WithMaxAttempts(3).
+		WithRetryInterval(time.Millisecond, 5*time.Millisecond)
+	return cfg
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+func TestOutbox_Delivery_Golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		status       func(name string, n int) (int, error)
+		wantStatus   Status
+		wantAttempts int
+		wantSends    int
+	}{
+		{
+			name:       "delivered first time",
+			status:     func(string, int) (int, error) { return 200, nil },
+			wantStatus: StatusDelivered,
+			wantSends:  1,
+		},
+		{
+			name: "delivered after transient failures",
+			status: func(_ string, n int) (int, error) {
+				if n < 3 {
+					return 0, errors.New("offline")
+				}
+				return 202, nil
+			},
+			wantStatus:   StatusDelivered,
+			wantAttempts: 2,
+			wantSends:    3,
+		},
+		{
+			name:         "dead lettered after max attempts",
+			status:       func(string, int) (int, error) { return 503, nil },
+			wantStatus:   StatusDead,
+			wantAttempts: 3,
+			wantSends:    3,
+		},
+		{
+			name:         "client error is permanent",
+			status:       func(string, int) (int, error) { return 422, nil },
+			wantStatus:   StatusDead,
+			wantAttempts: 1,
+			wantSends:    1,
+		},
+		{
+			name: "rate limit is retried",
+			status: func(_ string, n int) (int, error) {
+				if n == 1 {
+					return 429, nil
+				}
+				return 200, nil
+			},
+			wantStatus:   StatusDelivered,
+			wantAttempts: 1,
+			wantSends:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rec := &recorder{status: tt.status}
+			ob, err := Open(testConfig(t), rec.send)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer ob.Close()
+			ob.Start()
+
+			id, queued, err := ob.Enqueue(testRequest("hook"), "")
+			if err != nil || !queued {
+				t.Fatalf("Enqueue: queued=%v err=%v", queued, err)
+			}
+			waitFor(t, func() bool { return ob.Entries(StatusPending) == nil })
+
+			entries := ob.Entries("")
+			if len(entries) != 1 || entries[0].ID != id || entries[0].Status != tt.wantStatus || entries[0].Attempts != tt.wantAttempts {
+				t.Fatalf("entries=%+v", entries)
+			}
+			if got := len(rec.sentNames()); got != tt.wantSends {
+				t.Fatalf("sends=%d want %d", got, tt.wantSends)
+			}
+		})
+	}
+}
+
+func TestOutbox_IdempotencyAndPersistence_Golden(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t)
+	failing := &recorder{status: func(string, int) (int, error) { return 500, nil }}
+	ob, err := Open(cfg, failing.send)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	// Not started: entries are only persisted.
+	firstID, _, err := ob.Enqueue(testRequest("a"), "key-a")
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if _, _, err := ob.Enqueue(testRequest("b"), ""); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	dupID, queued, err := ob.Enqueue(testRequest("a-again"), "key-a")
+	if err != nil || queued || dupID != firstID {
+		t.Fatalf("duplicate: id=%s queued=%v err=%v", dupID, queued, err)
+	}
+	if err := ob.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, _, err := ob.Enqueue(testRequest("c"), ""); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Enqueue after Close err=%v", err)
+	}
+
+	// Simulate a crash mid-write.
+	f, err := os.OpenFile(cfg.Path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"op":"attempt","id":"`)
+	_ = f.Close()
+
+	ok := &recorder{status: func(string, int) (int, error) { return 200, nil }}
+	reopened, err := Open(cfg, ok.send)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+	if state := reopened.State(); state.Pending != 2 || state.OldestPendingAt.IsZero() {
+		t.Fatalf("state after reopen=%+v", state)
+	}
+	reopened.Start()
+	waitFor(t, func() bool { return reopened.State().Delivered == 2 })
+
+	if got := ok.sentNames(); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Fatalf("delivery order=%v", got)
+	}
+	if _, queued, _ := reopened.Enqueue(testRequest("a"), "key-a"); queued {
+		t.Fatalf("delivered key not deduplicated")
+	}
+}
+
+func TestOutbox_Requeue_Golden(t *testing.T) {
+	t.Parallel()
+
+	var healthy sync.Map
+	rec := &recorder{status: func(string, int) (int, error) {
+		if _, ok := healthy.Load("up"); ok {
+			return 200, nil
+		}
+		return 404, nil
+	}}
+	ob, err := Open(testConfig(t), rec.send)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer ob.Close()
+	ob.Start()
+
+	id, _, _ := ob.Enqueue(testRequest("hook"), "")
+	waitFor(t, func() bool { return len(ob.DeadLetters()) == 1 })
+	if dead := ob.DeadLetters()[0]; dead.ID != id || dead.LastError != "status 404" {
+		t.Fatalf("dead letter=%+v", dead)
+	}
+
+	healthy.Store("up", true)
+	if err := ob.Requeue(id); err != nil {
+		t.Fatalf("Requeue: %v", err)
+	}
+	waitFor(t, func() bool { return ob.State().Delivered == 1 })
+	if err := ob.Requeue(id); err == nil {
+		t.Fatalf("expected error requeueing a delivered entry")
+	}
+}
+
+func TestOutbox_SenderStopped_Golden(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t)
+	stopped := func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+		return dto.Response{}, ErrSenderStopped
+	}
+	ob, err := Open(cfg, stopped)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	ob.Start()
+	if _, _, err := ob.Enqueue(testRequest("hook"), ""); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	waitFor(t, func() bool {
+		select {
+		case <-ob.done:
+			return true
+		default:
+			return false
+		}
+	})
+	if entries := ob.Entries(StatusPending); len(entries) != 1 || entries[0].Attempts != 0 {
+		t.Fatalf("entry changed by stopped sender: %+v", entries)
+	}
+	if err := ob.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
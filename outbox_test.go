@@ -0,0 +1,72 @@
+package gonetic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joy-dx/gonetic/client/httpclient"
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
+)
+
+func TestNetSvc_Outbox_Golden(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	if _, _, err := newTestSvc(t).Enqueue(dto.DefaultRequestConfig(), ""); !errors.Is(err, ErrOutboxDisabled) {
+		t.Fatalf("err=%v want ErrOutboxDisabled", err)
+	}
+
+	cfg := config.DefaultNetSvcConfig()
+	cfg.WithRelay(&fakeRelay{}).WithOutbox(filepath.Join(t.TempDir(), "outbox.jsonl"))
+	cfg.Outbox.WithRetryInterval(time.Millisecond, time.Millisecond)
+	s, err := New(&cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := s.Hydrate(context.Background()); err != nil {
+		t.Fatalf("Hydrate: %v", err)
+	}
+
+	httpCfg := httpclient.DefaultHTTPRequestConfig()
+	httpCfg.WithMethod(http.MethodPost).WithURL(srv.URL + "/hook")
+	req := dto.DefaultRequestConfig()
+	req.WithReqConfig(&httpCfg).WithMaxRetries(0).WithDelay(utils.ConstantDelay{})
+	if _, queued, err := s.Enqueue(req, "evt-1"); err != nil || !queued {
+		t.Fatalf("Enqueue: queued=%v err=%v", queued, err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.State().Outbox.Delivered != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("not delivered: %+v", s.State().Outbox)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	if state := s.State().Outbox; state.Pending != 0 || state.LastError == "" || hits.Load() != 2 {
+		t.Fatalf("state=%+v hits=%d", state, hits.Load())
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, _, err := s.Enqueue(req, "evt-2"); err == nil {
+		t.Fatalf("expected Enqueue after Close to fail")
+	}
+}
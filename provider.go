@@ -1,6 +1,8 @@
 package gonetic
 
 import (
+	"context"
+	"errors"
 	"sync"
 
 	"github.com/joy-dx/gonetic/config"
@@ -14,16 +16,38 @@ var (
 	serviceOnce sync.Once
 )
 
+// New returns an independent NetSvc for cfg. Call Shutdown when done with it.
+func New(cfg *config.NetSvcConfig) (*NetSvc, error) {
+	if cfg == nil {
+		return nil, errors.New("no net config")
+	}
+	if cfg.Relay() == nil {
+		return nil, errors.New("no relay implementation")
+	}
+	stopCtx, stop := context.WithCancel(context.Background())
+	s := &NetSvc{
+		cfg:            cfg,
+		relay:          cfg.Relay(),
+		listenersByURL: make(map[string][]chan dto.TransferNotification),
+		transferState:  *lockablemap.NewLockableMap[string, dto.TransferNotification](),
+		clients:        make(map[string]dto.NetClientInterface),
+		stopCtx:        stopCtx,
+		stop:           stop,
+	}
+	s.relay.Debug(relays.RlyNetLog{Msg: "Net service started"})
+	return s, nil
+}
+
+// ProvideNetSvc returns a process-wide NetSvc built from the cfg of the first
+// call; later calls return the same instance whatever cfg they pass.
+//
+// Deprecated: use New, which returns independent instances.
 func ProvideNetSvc(cfg *config.NetSvcConfig) *NetSvc {
 	serviceOnce.Do(func() {
-		service = &NetSvc{
-			cfg:            cfg,
-			relay:          cfg.Relay(),
-			listenersByURL: make(map[string][]chan dto.TransferNotification),
-			transferState:  *lockablemap.NewLockableMap[string, dto.TransferNotification](),
-			clients:        make(map[string]dto.NetClientInterface),
+		var err error
+		if service, err = New(cfg); err != nil {
+			panic("gonetic: " + err.Error())
 		}
-		cfg.Relay().Debug(relays.RlyNetLog{Msg: "Net service started"})
 	})
 	return service
 }
@@ -0,0 +1,87 @@
+package gonetic
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// RegisterClient adds client under ref, replacing any client already there.
+func (s *NetSvc) RegisterClient(ref string, client dto.NetClientInterface) {
+	s.wireClient(client)
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	s.clients[ref] = client
+}
+
+// UnregisterClient removes the client under ref and reports whether one was
+// registered. Requests already dispatched to it run to completion.
+func (s *NetSvc) UnregisterClient(ref string) bool {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	_, ok := s.clients[ref]
+	delete(s.clients, ref)
+	return ok
+}
+
+// ReplaceClient atomically swaps the client under an existing ref, e.g. to
+// rotate credentials, and returns the previous client so the caller can
+// release it. Requests in flight keep using the previous client; new requests
+// see only the replacement.
+func (s *NetSvc) ReplaceClient(ref string, client dto.NetClientInterface) (dto.NetClientInterface, error) {
+	s.wireClient(client)
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	previous, ok := s.clients[ref]
+	if !ok {
+		return nil, fmt.Errorf("client not found: %s", ref)
+	}
+	s.clients[ref] = client
+	return previous, nil
+}
+
+// Client returns the client registered under ref.
+func (s *NetSvc) Client(ref string) (dto.NetClientInterface, bool) {
+	s.clientsMu.RLock()
+	defer s.clientsMu.RUnlock()
+	client, ok := s.clients[ref]
+	return client, ok
+}
+
+// Clients describes the registered clients, sorted by ref. Ref is always the
+// registration ref.
+func (s *NetSvc) Clients() []dto.NetClient {
+	s.clientsMu.RLock()
+	defer s.clientsMu.RUnlock()
+	out := make([]dto.NetClient, 0, len(s.clients))
+	for ref, client := range s.clients {
+		desc := dto.NetClient{ClientType: client.Type()}
+		if descriptor, ok := client.(dto.NetClientDescriptor); ok {
+			desc = descriptor.Descriptor()
+		}
+		desc.Ref = ref
+		out = append(out, desc)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Ref < out[j].Ref })
+	return out
+}
+
+// snapshotClients returns the registered clients for iteration without the lock.
+func (s *NetSvc) snapshotClients() []dto.NetClientInterface {
+	s.clientsMu.RLock()
+	defer s.clientsMu.RUnlock()
+	out := make([]dto.NetClientInterface, 0, len(s.clients))
+	for _, client := range s.clients {
+		out = append(out, client)
+	}
+	return out
+}
+
+func (s *NetSvc) wireClient(client dto.NetClientInterface) {
+	if publisher, ok := client.(dto.TransferPublisher); ok {
+		publisher.SetTransferPublisher(s.publishTransferUpdate)
+	}
+}
+
+var _ dto.NetRegistry = (*NetSvc)(nil)
@@ -0,0 +1,198 @@
+package gonetic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/joy-dx/gonetic/client/httpclient"
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+func staticClient(ref string, status int) *fakeNetClient {
+	return &fakeNetClient{ref: ref, typ: httpclient.NetClientHTTPRef, fn: func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+		return dto.Response{StatusCode: status}, nil
+	}}
+}
+
+func TestNetSvc_Registry_Golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		run        func(s *NetSvc) error
+		wantErr    bool
+		wantStatus int
+		wantRefs   []string
+	}{
+		{
+			name:       "register",
+			run:        func(s *NetSvc) error { s.RegisterClient("api", staticClient("api", 200)); return nil },
+			wantStatus: 200,
+			wantRefs:   []string{"api"},
+		},
+		{
+			name: "replace existing",
+			run: func(s *NetSvc) error {
+				s.RegisterClient("api", staticClient("api", 200))
+				previous, err := s.ReplaceClient("api", staticClient("api", 201))
+				if err == nil && previous.Ref() != "api" {
+					return fmt.Errorf("previous client not returned")
+				}
+				return err
+			},
+			wantStatus: 201,
+			wantRefs:   []string{"api"},
+		},
+		{
+			name: "replace missing",
+			run: func(s *NetSvc) error {
+				_, err := s.ReplaceClient("api", staticClient("api", 201))
+				return err
+			},
+			wantErr: true,
+		},
+		{
+			name: "unregister",
+			run: func(s *NetSvc) error {
+				s.RegisterClient("api", staticClient("api", 200))
+				s.RegisterClient("other", staticClient("other", 200))
+				if !s.UnregisterClient("api") || s.UnregisterClient("api") {
+					return fmt.Errorf("unexpected unregister result")
+				}
+				return nil
+			},
+			wantStatus: -1,
+			wantRefs:   []string{"other"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newTestSvc(t)
+			if err := tt.run(s); (err != nil) != tt.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tt.wantErr)
+			}
+
+			var refs []string
+			for _, c := range s.Clients() {
+				refs = append(refs, c.Ref)
+			}
+			if fmt.Sprint(refs) != fmt.Sprint(tt.wantRefs) {
+				t.Fatalf("refs=%v want %v", refs, tt.wantRefs)
+			}
+
+			if tt.wantStatus == 0 {
+				return
+			}
+			resp, err := s.RequestOnce(context.Background(), testRequest("api"))
+			if tt.wantStatus < 0 {
+				if err == nil {
+					t.Fatalf("expected client not found")
+				}
+				return
+			}
+			if err != nil || resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status=%d err=%v want %d", resp.StatusCode, err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNetSvc_ClientsAndState_Golden(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSvc(t)
+	netCfg := config.DefaultNetSvcConfig()
+	httpCfg := httpclient.DefaultHTTPClientConfig()
+	s.RegisterClient("b-http", httpclient.NewHTTPClient("internal-name", &netCfg, &httpCfg))
+	s.RegisterClient("a-fake", staticClient("a-fake", 200))
+
+	want := []dto.NetClient{
+		{Ref: "a-fake", ClientType: httpclient.NetClientHTTPRef},
+		{
+			Name:        "HTTP Client",
+			Ref:         "b-http",
+			ClientType:  httpclient.NetClientHTTPRef,
+			Description: "Perform HTTP requests to given URLs including auth support",
+		},
+	}
+	got := s.State().Clients
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("clients=%+v want %+v", got, want)
+	}
+}
+
+func TestNetSvc_State_ConnectionPools_Golden(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+
+	s := newTestSvc(t)
+	netCfg := config.DefaultNetSvcConfig()
+	httpCfg := httpclient.DefaultHTTPClientConfig()
+	client := httpclient.NewHTTPClient("api", &netCfg, &httpCfg)
+	s.RegisterClient("api", client)
+	s.RegisterClient("fake", staticClient("fake", 200))
+	if pools := s.State().ConnectionPools; pools != nil {
+		t.Fatalf("pools before any request=%v", pools)
+	}
+
+	reqCfg := httpclient.DefaultHTTPRequestConfig()
+	reqCfg.WithURL(srv.URL)
+	if _, err := s.RequestOnce(context.Background(), &dto.RequestConfig{ClientRef: "api", ReqConfig: &reqCfg}); err != nil {
+		t.Fatalf("RequestOnce: %v", err)
+	}
+	want := map[string][]dto.HostPoolStats{"api": {{Host: srv.Listener.Addr().String(), Idle: 1}}}
+	if got := s.State().ConnectionPools; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("pools=%v want %v", got, want)
+	}
+	client.CloseIdleConnections()
+}
+
+func TestNetSvc_RegistryConcurrentAccess_Golden(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSvc(t)
+	s.RegisterClient("api", staticClient("api", 200))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if _, err := s.RequestOnce(context.Background(), testRequest("api")); err != nil {
+					t.Errorf("RequestOnce: %v", err)
+					return
+				}
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_, _ = s.ReplaceClient("api", staticClient("api", 200+i))
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			ref := fmt.Sprintf("tmp-%d", i)
+			for j := 0; j < 50; j++ {
+				s.RegisterClient(ref, staticClient(ref, 200))
+				_ = s.Clients()
+				s.UnregisterClient(ref)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
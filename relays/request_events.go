@@ -0,0 +1,232 @@
+package relays
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/joy-dx/gonetic/dto"
+	relayDTO "github.com/joy-dx/relay/dto"
+)
+
+const (
+	RELAY_NET_REQUEST_START    relayDTO.EventRef = "net.request.start"
+	RELAY_NET_REQUEST_ATTEMPT  relayDTO.EventRef = "net.request.attempt"
+	RELAY_NET_REQUEST_RETRY    relayDTO.EventRef = "net.request.retry"
+	RELAY_NET_REQUEST_RESPONSE relayDTO.EventRef = "net.request.response"
+	RELAY_NET_REQUEST_FAILED   relayDTO.EventRef = "net.request.failed"
+)
+
+// RequestInfo identifies the request an event belongs to. URL, Headers and
+// Body are redacted by NetSvc before the event is published; Body is only set
+// when body logging is enabled.
+type RequestInfo struct {
+	TaskName  string            `json:"task_name,omitempty" yaml:"task_name,omitempty"`
+	ClientRef string            `json:"client_ref" yaml:"client_ref"`
+	Method    string            `json:"method,omitempty" yaml:"method,omitempty"`
+	URL       string            `json:"url,omitempty" yaml:"url,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body      string            `json:"body,omitempty" yaml:"body,omitempty"`
+	// Size request body length in bytes
+	Size int `json:"size,omitempty" yaml:"size,omitempty"`
+}
+
+func (r RequestInfo) toSlog(eventType relayDTO.EventRef) []slog.Attr {
+	fields := []slog.Attr{
+		slog.String("type", string(eventType)),
+		slog.String("client", r.ClientRef),
+	}
+	if r.TaskName != "" {
+		fields = append(fields, slog.String("task", r.TaskName))
+	}
+	if r.Method != "" {
+		fields = append(fields, slog.String("method", r.Method))
+	}
+	if r.URL != "" {
+		fields = append(fields, slog.String("url", r.URL))
+	}
+	if len(r.Headers) > 0 {
+		fields = append(fields, slog.Any("headers", r.Headers))
+	}
+	if r.Size > 0 {
+		fields = append(fields, slog.Int("size", r.Size))
+	}
+	if r.Body != "" {
+		fields = append(fields, slog.String("body", r.Body))
+	}
+	return fields
+}
+
+func (r RequestInfo) String() string {
+	if r.Method == "" && r.URL == "" {
+		return r.ClientRef
+	}
+	return r.Method + " " + r.URL
+}
+
+// RlyNetRequestStart is published when RequestOnce or RequestWithRetry is
+// called.
+type RlyNetRequestStart struct {
+	Request RequestInfo `json:"request" yaml:"request"`
+	// MaxAttempts is 1 for RequestOnce
+	MaxAttempts int `json:"max_attempts" yaml:"max_attempts"`
+}
+
+func (e RlyNetRequestStart) ToSlog() []slog.Attr {
+	return append(e.Request.toSlog(e.RelayType()), slog.Int("max_attempts", e.MaxAttempts))
+}
+
+func (e RlyNetRequestStart) Message() string {
+	return "request " + e.Request.String()
+}
+
+func (e RlyNetRequestStart) RelayChannel() relayDTO.EventChannel {
+	return RELAY_NET_CHANNEL
+}
+
+func (e RlyNetRequestStart) RelayType() relayDTO.EventRef {
+	return RELAY_NET_REQUEST_START
+}
+
+// RlyNetRequestAttempt is published before each attempt. Attempt counts from 0.
+type RlyNetRequestAttempt struct {
+	Request RequestInfo `json:"request" yaml:"request"`
+	Attempt int         `json:"attempt" yaml:"attempt"`
+}
+
+func (e RlyNetRequestAttempt) ToSlog() []slog.Attr {
+	return append(e.Request.toSlog(e.RelayType()), slog.Int("attempt", e.Attempt))
+}
+
+func (e RlyNetRequestAttempt) Message() string {
+	return fmt.Sprintf("attempt %d: %s", e.Attempt, e.Request)
+}
+
+func (e RlyNetRequestAttempt) RelayChannel() relayDTO.EventChannel {
+	return RELAY_NET_CHANNEL
+}
+
+func (e RlyNetRequestAttempt) RelayType() relayDTO.EventRef {
+	return RELAY_NET_REQUEST_ATTEMPT
+}
+
+// RlyNetRequestRetry is published when an attempt failed and another one is
+// scheduled. Attempt is the upcoming attempt.
+type RlyNetRequestRetry struct {
+	Request    RequestInfo `json:"request" yaml:"request"`
+	Attempt    int         `json:"attempt" yaml:"attempt"`
+	StatusCode int         `json:"status_code,omitempty" yaml:"status_code,omitempty"`
+	Reason     string      `json:"reason" yaml:"reason"`
+}
+
+func (e RlyNetRequestRetry) ToSlog() []slog.Attr {
+	fields := append(e.Request.toSlog(e.RelayType()),
+		slog.Int("attempt", e.Attempt),
+		slog.String("reason", e.Reason),
+	)
+	if e.StatusCode != 0 {
+		fields = append(fields, slog.Int("status", e.StatusCode))
+	}
+	return fields
+}
+
+func (e RlyNetRequestRetry) Message() string {
+	return fmt.Sprintf("retrying %s: %s", e.Request, e.Reason)
+}
+
+func (e RlyNetRequestRetry) RelayChannel() relayDTO.EventChannel {
+	return RELAY_NET_CHANNEL
+}
+
+func (e RlyNetRequestRetry) RelayType() relayDTO.EventRef {
+	return RELAY_NET_REQUEST_RETRY
+}
+
+// RlyNetRequestResponse is published for every attempt that received a
+// response, including ones that are retried.
+type RlyNetRequestResponse struct {
+	Request    RequestInfo       `json:"request" yaml:"request"`
+	Attempt    int               `json:"attempt" yaml:"attempt"`
+	StatusCode int               `json:"status_code" yaml:"status_code"`
+	Duration   time.Duration     `json:"duration" yaml:"duration"`
+	Headers    map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body       string            `json:"body,omitempty" yaml:"body,omitempty"`
+	// Size response body length in bytes
+	Size int `json:"size,omitempty" yaml:"size,omitempty"`
+	// Timing is set when the client collects connection timing
+	Timing *dto.ResponseTiming `json:"timing,omitempty" yaml:"timing,omitempty"`
+}
+
+func (e RlyNetRequestResponse) ToSlog() []slog.Attr {
+	fields := append(e.Request.toSlog(e.RelayType()),
+		slog.Int("attempt", e.Attempt),
+		slog.Int("status", e.StatusCode),
+		slog.Duration("duration", e.Duration),
+		slog.Int("response_size", e.Size),
+	)
+	if len(e.Headers) > 0 {
+		fields = append(fields, slog.Any("response_headers", e.Headers))
+	}
+	if e.Body != "" {
+		fields = append(fields, slog.String("response_body", e.Body))
+	}
+	if t := e.Timing; t != nil {
+		fields = append(fields, slog.Group("timing",
+			slog.Duration("dns", t.DNSLookup),
+			slog.Duration("connect", t.TCPConnect),
+			slog.Duration("tls", t.TLSHandshake),
+			slog.Duration("ttfb", t.TimeToFirstByte),
+			slog.Duration("total", t.Total),
+			slog.Bool("reused", t.ConnReused),
+			slog.String("remote_addr", t.RemoteAddr),
+			slog.String("protocol", t.Protocol),
+		))
+	}
+	return fields
+}
+
+func (e RlyNetRequestResponse) Message() string {
+	return fmt.Sprintf("%s: %d in %s", e.Request, e.StatusCode, e.Duration)
+}
+
+func (e RlyNetRequestResponse) RelayChannel() relayDTO.EventChannel {
+	return RELAY_NET_CHANNEL
+}
+
+func (e RlyNetRequestResponse) RelayType() relayDTO.EventRef {
+	return RELAY_NET_REQUEST_RESPONSE
+}
+
+// RlyNetRequestFailed is published once when a call returns an error.
+// Duration covers every attempt.
+type RlyNetRequestFailed struct {
+	Request    RequestInfo   `json:"request" yaml:"request"`
+	Attempt    int           `json:"attempt" yaml:"attempt"`
+	StatusCode int           `json:"status_code,omitempty" yaml:"status_code,omitempty"`
+	Duration   time.Duration `json:"duration" yaml:"duration"`
+	Error      string        `json:"error" yaml:"error"`
+}
+
+func (e RlyNetRequestFailed) ToSlog() []slog.Attr {
+	fields := append(e.Request.toSlog(e.RelayType()),
+		slog.Int("attempt", e.Attempt),
+		slog.Duration("duration", e.Duration),
+		slog.String("error", e.Error),
+	)
+	if e.StatusCode != 0 {
+		fields = append(fields, slog.Int("status", e.StatusCode))
+	}
+	return fields
+}
+
+func (e RlyNetRequestFailed) Message() string {
+	return fmt.Sprintf("%s failed: %s", e.Request, e.Error)
+}
+
+func (e RlyNetRequestFailed) RelayChannel() relayDTO.EventChannel {
+	return RELAY_NET_CHANNEL
+}
+
+func (e RlyNetRequestFailed) RelayType() relayDTO.EventRef {
+	return RELAY_NET_REQUEST_FAILED
+}
@@ -0,0 +1,50 @@
+package gonetic
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/joy-dx/gonetic/dto"
+)
+
+// ReplayResult is the outcome of one request replayed from a JSONL file.
+type ReplayResult struct {
+	Line     int
+	TaskName string
+	Response dto.Response
+	Err      error
+}
+
+// ReplayRequests sends each RequestConfig of a JSONL stream, as written by
+// dto.WriteRequestJSONL, through RequestWithRetry in file order. A failed
+// request is recorded in its result and the replay continues; a line that
+// cannot be decoded or a cancelled ctx stops it. Streamed response bodies are
+// closed unread.
+func (s *NetSvc) ReplayRequests(ctx context.Context, r io.Reader) ([]ReplayResult, error) {
+	var results []ReplayResult
+	err := dto.ReadRequestsJSONL(r, func(line int, cfg dto.RequestConfig) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		resp, err := s.RequestWithRetry(ctx, &cfg)
+		if resp.Stream != nil {
+			_ = resp.Stream.Close()
+			resp.Stream = nil
+		}
+		results = append(results, ReplayResult{Line: line, TaskName: cfg.TaskName, Response: resp, Err: err})
+		return nil
+	})
+	return results, err
+}
+
+// ReplayFile runs ReplayRequests over the JSONL file at path.
+func (s *NetSvc) ReplayFile(ctx context.Context, path string) ([]ReplayResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open requests file: %w", err)
+	}
+	defer f.Close()
+	return s.ReplayRequests(ctx, f)
+}
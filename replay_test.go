@@ -0,0 +1,98 @@
+package gonetic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/joy-dx/gonetic/client/httpclient"
+	"github.com/joy-dx/gonetic/client/s3client"
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/utils"
+)
+
+func TestRequestConfig_BuiltInReqConfigRoundTrip_Golden(t *testing.T) {
+	t.Parallel()
+
+	httpCfg := httpclient.DefaultHTTPRequestConfig()
+	httpCfg.WithMethod(http.MethodPost).WithURL("https://example.com/items").WithBody(map[string]interface{}{"name": "a"})
+	s3Cfg := &s3client.S3RequestConfig{Operation: s3client.S3OpPut, Bucket: "b", Key: "k", Body: []byte("data"), Tags: map[string]string{"env": "dev"}}
+
+	for _, reqConfig := range []dto.ReqConfigInterface{&httpCfg, s3Cfg} {
+		cfg := dto.DefaultRequestConfig()
+		cfg.WithReqConfig(reqConfig).WithDelay(utils.ConstantDelay{Period: 1})
+		encoded, err := json.Marshal(cfg)
+		if err != nil {
+			t.Fatalf("Marshal %T: %v", reqConfig, err)
+		}
+		var decoded dto.RequestConfig
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("Unmarshal %T: %v", reqConfig, err)
+		}
+		if !reflect.DeepEqual(decoded.ReqConfig, reqConfig) {
+			t.Fatalf("req config=%+v want %+v", decoded.ReqConfig, reqConfig)
+		}
+	}
+}
+
+func TestNetSvc_ReplayFile_Golden(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	s := newTestSvc(t)
+	netCfg := config.DefaultNetSvcConfig()
+	clientCfg := httpclient.DefaultHTTPClientConfig()
+	s.RegisterClient("api", httpclient.NewHTTPClient("api", &netCfg, &clientCfg))
+
+	var file strings.Builder
+	for _, p := range []string{"/fail", "/ok"} {
+		httpCfg := httpclient.DefaultHTTPRequestConfig()
+		httpCfg.WithMethod(http.MethodPut).WithURL(srv.URL + p)
+		cfg := dto.DefaultRequestConfig()
+		cfg.WithClientRef("api").WithReqConfig(&httpCfg).WithMaxRetries(0).WithDelay(utils.ConstantDelay{}).WithTaskName("put " + p)
+		if err := dto.WriteRequestJSONL(&file, cfg); err != nil {
+			t.Fatalf("WriteRequestJSONL: %v", err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "requests.jsonl")
+	if err := os.WriteFile(path, []byte(file.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := s.ReplayFile(context.Background(), path)
+	if err != nil {
+		t.Fatalf("ReplayFile: %v", err)
+	}
+	if len(results) != 2 || results[0].Err == nil || results[1].Err != nil || results[1].Response.StatusCode != http.StatusCreated {
+		t.Fatalf("results=%+v", results)
+	}
+	if results[0].Line != 1 || results[1].TaskName != "put /ok" {
+		t.Fatalf("results=%+v", results)
+	}
+	if strings.Join(paths, ",") != "PUT /fail,PUT /ok" {
+		t.Fatalf("paths=%v", paths)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.ReplayFile(ctx, path); err == nil {
+		t.Fatalf("expected cancelled replay to fail")
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/joy-dx/gonetic/client/httpclient"
 	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/telemetry"
 	"github.com/joy-dx/gonetic/utils"
 )
 
@@ -47,48 +49,72 @@ func (s *NetSvc) RequestWithRetry(ctx context.Context, cfg *dto.RequestConfig) (
 	if cfg == nil {
 		return dto.Response{}, errors.New("nil RequestConfig provided")
 	}
-	if cfg.MaxRetries < 0 {
-		cfg.MaxRetries = 0
+	ctx, done, err := s.track(ctx)
+	if err != nil {
+		return dto.Response{}, err
 	}
+	o := s.observeRequest(cfg)
+	ctx, span := o.begin(ctx, s.maxRetries(cfg)+1)
+	resp, retries, err := s.requestWithRetry(ctx, cfg, o)
+	o.finish(ctx, span, resp, err, retries, telemetry.Int(telemetry.AttrRetries, retries))
+	return releaseWith(resp, done), err
+}
+
+// requestWithRetry also returns how many retries were made.
+func (s *NetSvc) requestWithRetry(ctx context.Context, cfg *dto.RequestConfig, o *requestObserver) (dto.Response, int, error) {
+	maxRetries := s.maxRetries(cfg)
 	if cfg.Delay == nil {
 		cfg.Delay = utils.ConstantDelay{Period: 1}
 	}
 	var lastErr error
-	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			cfg.Delay.Wait(cfg.TaskName, attempt)
 		}
 
-		resp, err := s.RequestOnce(ctx, cfg)
+		resp, err := s.attempt(ctx, cfg, o, attempt)
 		if err != nil {
 			lastErr = err
 			// transient network errors → retry
-			if utils.IsTemporaryErr(err) && attempt < cfg.MaxRetries {
+			if utils.IsTemporaryErr(err) && attempt < maxRetries {
+				o.retry(attempt+1, resp, err)
 				continue
 			}
-			return resp, err
+			return resp, attempt, err
 		}
 
 		if resp.StatusCode >= 500 {
 			lastErr = fmt.Errorf("server error (%d)", resp.StatusCode)
-			if attempt < cfg.MaxRetries {
+			if attempt < maxRetries {
+				o.retry(attempt+1, resp, lastErr)
 				continue
 			}
 			// exhausted retries: return response + error
-			return resp, fmt.Errorf(
+			return resp, attempt, fmt.Errorf(
 				"failed after %d attempts: %w",
-				cfg.MaxRetries+1,
+				maxRetries+1,
 				lastErr,
 			)
 		}
-		return resp, nil
+		return resp, attempt, nil
 	}
 
-	return dto.Response{}, fmt.Errorf("failed after %d attempts: %w", cfg.MaxRetries+1, lastErr)
+	return dto.Response{}, maxRetries, fmt.Errorf("failed after %d attempts: %w", maxRetries+1, lastErr)
 }
 
 func (s *NetSvc) RequestOnce(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+	ctx, done, err := s.track(ctx)
+	if err != nil {
+		return dto.Response{}, err
+	}
+	o := s.observeRequest(cfg)
+	ctx, span := o.begin(ctx, 1)
+	resp, err := s.attempt(ctx, cfg, o, 0)
+	o.finish(ctx, span, resp, err, 0)
+	return releaseWith(resp, done), err
+}
 
+func (s *NetSvc) requestOnce(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
 	if cfg.ClientRef == "" {
 		return dto.Response{}, errors.New("nil ClientRef provided")
 	}
@@ -101,7 +127,7 @@ func (s *NetSvc) RequestOnce(ctx context.Context, cfg *dto.RequestConfig) (dto.R
 		cfg.TaskName = "http_request"
 	}
 
-	netClient, isOK := s.clients[cfg.ClientRef]
+	netClient, isOK := s.Client(cfg.ClientRef)
 	if !isOK {
 		return dto.Response{}, fmt.Errorf("client not found: %s", cfg.ClientRef)
 	}
@@ -116,17 +142,25 @@ func (s *NetSvc) RequestOnce(ctx context.Context, cfg *dto.RequestConfig) (dto.R
 		)
 	}
 
-	if cfg.Timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
-		defer cancel()
+	cancel := context.CancelFunc(func() {})
+	if timeout := s.requestTimeout(cfg); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
 	}
 
 	response, err := netClient.ProcessRequest(ctx, cfg)
 	if err != nil {
+		cancel()
 		return dto.Response{}, fmt.Errorf("perform request: %w", err)
 	}
 
+	// A streamed body is still being read after we return, so the timeout
+	// context lives until the caller closes it.
+	if response.Stream != nil {
+		response.Stream = &cancelOnClose{ReadCloser: response.Stream, cancel: cancel}
+		return response, nil
+	}
+	cancel()
+
 	if cfg.ResponseObject != nil && len(response.Body) > 0 {
 		if unmarshalErr := json.Unmarshal(response.Body, cfg.ResponseObject); unmarshalErr != nil {
 			return response, fmt.Errorf("unmarshal response: %w", unmarshalErr)
@@ -135,3 +169,25 @@ func (s *NetSvc) RequestOnce(ctx context.Context, cfg *dto.RequestConfig) (dto.R
 
 	return response, nil
 }
+
+// releaseWith runs done now, or when a streamed body is closed so Shutdown
+// keeps waiting for streams still being read.
+func releaseWith(resp dto.Response, done func()) dto.Response {
+	if resp.Stream != nil {
+		resp.Stream = &cancelOnClose{ReadCloser: resp.Stream, cancel: done}
+		return resp
+	}
+	done()
+	return resp
+}
+
+// cancelOnClose releases a request context once a streamed body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
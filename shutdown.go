@@ -0,0 +1,128 @@
+package gonetic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/relays"
+)
+
+// ErrNetSvcClosed is returned for work submitted after Shutdown.
+var ErrNetSvcClosed = errors.New("net service closed")
+
+// idleCloser is implemented by clients holding pooled connections.
+type idleCloser interface {
+	CloseIdleConnections()
+}
+
+// track registers in-flight work and derives a context cancelled when
+// Shutdown gives up waiting. The returned func must be called when done; it
+// is safe to call more than once.
+func (s *NetSvc) track(ctx context.Context) (context.Context, func(), error) {
+	s.lifecycleMu.Lock()
+	if s.closed {
+		s.lifecycleMu.Unlock()
+		return ctx, nil, ErrNetSvcClosed
+	}
+	s.inflight.Add(1)
+	s.lifecycleMu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	stopAfter := context.AfterFunc(s.stopCtx, cancel)
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			stopAfter()
+			cancel()
+			s.inflight.Done()
+		})
+	}, nil
+}
+
+// shutdownGrace is how long Shutdown waits for cancelled work to return once
+// ctx has ended.
+var shutdownGrace = time.Second
+
+// Shutdown rejects new work with ErrNetSvcClosed and waits for in-flight
+// requests and downloads to finish. If ctx ends first they are cancelled and
+// ctx's error is returned; work that ignores cancellation for longer than a
+// short grace period is left to finish in the background. The outbox, idle
+// client connections and transfer listeners are closed once all work has
+// returned. Calling Shutdown again is a no-op.
+func (s *NetSvc) Shutdown(ctx context.Context) error {
+	s.lifecycleMu.Lock()
+	if s.closed {
+		s.lifecycleMu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.lifecycleMu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		s.stop()
+		return s.release()
+	case <-ctx.Done():
+	}
+
+	err := fmt.Errorf("shutdown: %w", ctx.Err())
+	s.stop()
+	select {
+	case <-drained:
+		_ = s.release()
+	case <-time.After(shutdownGrace):
+		go func() {
+			<-drained
+			_ = s.release()
+		}()
+	}
+	return err
+}
+
+// release closes what in-flight work may still use. It runs once all tracked
+// work has returned, so listeners are never closed under a sender.
+func (s *NetSvc) release() error {
+	var err error
+	// Deliveries are refused from here on; queued entries stay in the journal
+	// for the next start.
+	if ob := s.Outbox(); ob != nil {
+		if closeErr := ob.Close(); closeErr != nil {
+			err = fmt.Errorf("shutdown: %w", closeErr)
+		}
+	}
+
+	for _, client := range s.snapshotClients() {
+		if closer, ok := client.(idleCloser); ok {
+			closer.CloseIdleConnections()
+		}
+	}
+
+	s.muListeners.Lock()
+	for source, chans := range s.listenersByURL {
+		for _, ch := range chans {
+			close(ch)
+		}
+		delete(s.listenersByURL, source)
+	}
+	s.muListeners.Unlock()
+
+	s.relay.Debug(relays.RlyNetLog{Msg: "Net service stopped"})
+	return err
+}
+
+// Close is Shutdown without a deadline.
+func (s *NetSvc) Close() error {
+	return s.Shutdown(context.Background())
+}
+
+var _ dto.NetInterface = (*NetSvc)(nil)
@@ -0,0 +1,211 @@
+package gonetic
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joy-dx/gonetic/client/httpclient"
+	"github.com/joy-dx/gonetic/config"
+	"github.com/joy-dx/gonetic/dto"
+)
+
+type idleClient struct {
+	fakeNetClient
+	closed atomic.Bool
+}
+
+func (c *idleClient) CloseIdleConnections() { c.closed.Store(true) }
+
+func testRequest(ref string) *dto.RequestConfig {
+	httpCfg := httpclient.DefaultHTTPRequestConfig()
+	cfg := dto.DefaultRequestConfig()
+	cfg.WithClientRef(ref).WithReqConfig(&httpCfg).WithDelay(noWaitDelay{})
+	return &cfg
+}
+
+func TestNew_IndependentInstances_Golden(t *testing.T) {
+	t.Parallel()
+
+	cfgA := config.DefaultNetSvcConfig()
+	cfgA.WithRelay(&fakeRelay{})
+	cfgA.UserAgent = "a"
+	cfgB := config.DefaultNetSvcConfig()
+	cfgB.WithRelay(&fakeRelay{})
+	cfgB.UserAgent = "b"
+
+	a, errA := New(&cfgA)
+	b, errB := New(&cfgB)
+	if errA != nil || errB != nil {
+		t.Fatalf("New: %v %v", errA, errB)
+	}
+	if a == b || a.State().UserAgent != "a" || b.State().UserAgent != "b" {
+		t.Fatalf("instances not independent")
+	}
+
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	b.RegisterClient("x", &fakeNetClient{ref: "x", typ: httpclient.NetClientHTTPRef, fn: func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+		return dto.Response{StatusCode: 200}, nil
+	}})
+	if _, err := b.RequestOnce(context.Background(), testRequest("x")); err != nil {
+		t.Fatalf("closing one instance affected the other: %v", err)
+	}
+
+	noRelay := config.DefaultNetSvcConfig()
+	if _, err := New(&noRelay); err == nil {
+		t.Fatalf("expected error without relay")
+	}
+	if _, err := New(nil); err == nil {
+		t.Fatalf("expected error without config")
+	}
+}
+
+func TestNetSvc_Shutdown_Golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		// fn is the client behaviour; release unblocks it.
+		fn          func(release <-chan struct{}) func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error)
+		stream      bool
+		deadline    time.Duration
+		wantErr     error
+		wantReqErr  error
+		releaseWait bool
+		// releaseAfter unblocks fn only once Shutdown has returned.
+		releaseAfter bool
+	}{
+		{
+			name: "drains in-flight request",
+			fn: func(release <-chan struct{}) func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+				return func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+					<-release
+					return dto.Response{StatusCode: 200}, nil
+				}
+			},
+			releaseWait: true,
+		},
+		{
+			name: "cancels in-flight request at deadline",
+			fn: func(release <-chan struct{}) func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+				return func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+					<-ctx.Done()
+					return dto.Response{}, ctx.Err()
+				}
+			},
+			deadline:   20 * time.Millisecond,
+			wantErr:    context.DeadlineExceeded,
+			wantReqErr: context.Canceled,
+		},
+		{
+			name: "returns at deadline when work ignores cancellation",
+			fn: func(release <-chan struct{}) func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+				return func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+					<-release
+					return dto.Response{StatusCode: 200}, nil
+				}
+			},
+			deadline:     20 * time.Millisecond,
+			wantErr:      context.DeadlineExceeded,
+			releaseAfter: true,
+		},
+		{
+			name: "waits for open stream",
+			fn: func(release <-chan struct{}) func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+				return func(ctx context.Context, cfg *dto.RequestConfig) (dto.Response, error) {
+					return dto.Response{StatusCode: 200, Stream: io.NopCloser(strings.NewReader("x"))}, nil
+				}
+			},
+			stream:      true,
+			releaseWait: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newTestSvc(t)
+			release := make(chan struct{})
+			client := &idleClient{fakeNetClient: fakeNetClient{ref: "c", typ: httpclient.NetClientHTTPRef, fn: tt.fn(release)}}
+			s.RegisterClient("c", client)
+			listener, unsub := s.TransferListener("https://example.com/file")
+
+			started := make(chan struct{})
+			reqErr := make(chan error, 1)
+			var stream io.ReadCloser
+			go func() {
+				close(started)
+				resp, err := s.RequestOnce(context.Background(), testRequest("c"))
+				stream = resp.Stream
+				reqErr <- err
+			}()
+			<-started
+			if tt.stream {
+				if err := <-reqErr; err != nil {
+					t.Fatalf("RequestOnce: %v", err)
+				}
+			}
+			// Let the request register before shutting down.
+			time.Sleep(10 * time.Millisecond)
+
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+			shutdownErr := make(chan error, 1)
+			go func() { shutdownErr <- s.Shutdown(ctx) }()
+
+			if tt.releaseWait {
+				select {
+				case err := <-shutdownErr:
+					t.Fatalf("Shutdown returned before work drained: %v", err)
+				case <-time.After(20 * time.Millisecond):
+				}
+				if tt.stream {
+					_ = stream.Close()
+				} else {
+					close(release)
+				}
+			}
+
+			if err := <-shutdownErr; !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Shutdown err=%v want %v", err, tt.wantErr)
+			}
+			if tt.releaseAfter {
+				close(release)
+			}
+			if !tt.stream {
+				if err := <-reqErr; !errors.Is(err, tt.wantReqErr) {
+					t.Fatalf("request err=%v want %v", err, tt.wantReqErr)
+				}
+			}
+
+			if _, err := s.RequestOnce(context.Background(), testRequest("c")); !errors.Is(err, ErrNetSvcClosed) {
+				t.Fatalf("request after shutdown err=%v", err)
+			}
+			if _, err := s.DownloadFile(context.Background(), &dto.DownloadFileConfig{URL: "https://example.com/f"}); !errors.Is(err, ErrNetSvcClosed) {
+				t.Fatalf("download after shutdown err=%v", err)
+			}
+			if _, open := <-listener; open {
+				t.Fatalf("listener not closed")
+			}
+			unsub() // must not double close
+			if !client.closed.Load() {
+				t.Fatalf("idle connections not closed")
+			}
+			if err := s.Shutdown(context.Background()); err != nil {
+				t.Fatalf("second Shutdown: %v", err)
+			}
+		})
+	}
+}
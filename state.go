@@ -13,7 +13,7 @@ import (
 
 func (s *NetSvc) State() *dto.NetState {
 
-	return &dto.NetState{
+	state := &dto.NetState{
 		ExtraHeaders:             s.cfg.ExtraHeaders,
 		RequestTimeout:           s.cfg.RequestTimeout,
 		UserAgent:                s.cfg.UserAgent,
@@ -22,7 +22,41 @@ func (s *NetSvc) State() *dto.NetState {
 		DownloadCallbackInterval: s.cfg.DownloadCallbackInterval,
 		PreferCurlDownloads:      s.cfg.PreferCurlDownloads,
 		TransfersStatus:          s.transferState.GetAll(),
+		Clients:                  s.Clients(),
 	}
+	state.ConnectionPools = s.connectionPools()
+	if ob := s.Outbox(); ob != nil {
+		outboxState := ob.State()
+		state.Outbox = &outboxState
+	}
+	return state
+}
+
+// poolStatsProvider is implemented by clients that track their connection
+// pool, such as httpclient.HTTPClient.
+type poolStatsProvider interface {
+	PoolStats() []dto.HostPoolStats
+}
+
+// connectionPools returns the pool stats of clients holding open
+// connections, keyed by ref.
+func (s *NetSvc) connectionPools() map[string][]dto.HostPoolStats {
+	s.clientsMu.RLock()
+	defer s.clientsMu.RUnlock()
+	var pools map[string][]dto.HostPoolStats
+	for ref, client := range s.clients {
+		provider, ok := client.(poolStatsProvider)
+		if !ok {
+			continue
+		}
+		if stats := provider.PoolStats(); len(stats) > 0 {
+			if pools == nil {
+				pools = make(map[string][]dto.HostPoolStats)
+			}
+			pools[ref] = stats
+		}
+	}
+	return pools
 }
 
 func isCurlAvailable() bool {
@@ -48,7 +82,11 @@ func (s *NetSvc) Hydrate(ctx context.Context) error {
 
 	defaultClientCfg := httpclient.DefaultHTTPClientConfig()
 	defaultClient := httpclient.NewHTTPClient(dto.NET_DEFAULT_CLIENT_REF, s.cfg, &defaultClientCfg)
-	s.clients[dto.NET_DEFAULT_CLIENT_REF] = defaultClient
+	s.RegisterClient(dto.NET_DEFAULT_CLIENT_REF, defaultClient)
 
-	return nil
+	// Declared clients may replace the default client by using its ref
+	if err := s.registerDeclaredClients(); err != nil {
+		return err
+	}
+	return s.startOutbox()
 }
@@ -1,10 +1,12 @@
 package gonetic
 
 import (
+	"context"
 	"sync"
 
 	"github.com/joy-dx/gonetic/config"
 	"github.com/joy-dx/gonetic/dto"
+	"github.com/joy-dx/gonetic/outbox"
 	"github.com/joy-dx/lockablemap"
 	relayDTO "github.com/joy-dx/relay/dto"
 )
@@ -13,14 +15,18 @@ import (
 type NetSvc struct {
 	cfg            *config.NetSvcConfig
 	relay          relayDTO.RelayInterface
+	clientsMu      sync.RWMutex
 	clients        map[string]dto.NetClientInterface
 	transferState  lockablemap.LockableMap[string, dto.TransferNotification]
 	muListeners    sync.Mutex
 	listenersByURL map[string][]chan dto.TransferNotification
-}
 
-func (s *NetSvc) RegisterClient(ref string, client dto.NetClientInterface) {
-	s.clients[ref] = client
+	lifecycleMu sync.Mutex
+	closed      bool
+	inflight    sync.WaitGroup
+	stopCtx     context.Context
+	stop        context.CancelFunc
+	outbox      *outbox.Outbox
 }
 
 // TransferListener returns a channel of updates for a particular URL
@@ -35,11 +41,15 @@ func (s *NetSvc) TransferListener(sourceURL string) (<-chan dto.TransferNotifica
 		s.muListeners.Lock()
 		defer s.muListeners.Unlock()
 
+		// The channel may already be closed by TransferListenerClose or Shutdown.
 		chans := s.listenersByURL[sourceURL]
+		found := false
 		out := chans[:0]
 		for _, c := range chans {
 			if c != ch {
 				out = append(out, c)
+			} else {
+				found = true
 			}
 		}
 		if len(out) == 0 {
@@ -47,7 +57,9 @@ func (s *NetSvc) TransferListener(sourceURL string) (<-chan dto.TransferNotifica
 		} else {
 			s.listenersByURL[sourceURL] = out
 		}
-		close(ch)
+		if found {
+			close(ch)
+		}
 	}
 
 	return ch, unsub
@@ -9,7 +9,6 @@ import (
 
 	"github.com/joy-dx/gonetic/config"
 	"github.com/joy-dx/gonetic/dto"
-	"github.com/joy-dx/lockablemap"
 	relayDTO "github.com/joy-dx/relay/dto"
 )
 
@@ -82,13 +81,10 @@ func newTestSvc(t *testing.T) *NetSvc {
 	t.Helper()
 
 	cfg := config.DefaultNetSvcConfig()
-	// If your NetSvc constructor exists, use it. Here we assemble directly.
-	s := &NetSvc{
-		cfg:            &cfg,
-		relay:          &fakeRelay{},
-		clients:        map[string]dto.NetClientInterface{},
-		transferState:  *lockablemap.NewLockableMap[string, dto.TransferNotification](),
-		listenersByURL: map[string][]chan dto.TransferNotification{},
+	cfg.WithRelay(&fakeRelay{})
+	s, err := New(&cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
 	}
 	return s
 }
@@ -107,7 +103,7 @@ func TestNetSvc_RegisterClient_Golden(t *testing.T) {
 
 	s.RegisterClient("x", c)
 
-	if _, ok := s.clients["x"]; !ok {
+	if _, ok := s.Client("x"); !ok {
 		t.Fatalf("client not registered")
 	}
 }
@@ -173,3 +169,39 @@ func TestNetSvc_TransferListeners_Golden(t *testing.T) {
 		t.Fatalf("timeout waiting for ch2 COMPLETE")
 	}
 }
+
+type fakePublishingClient struct {
+	fakeNetClient
+	publish func(dto.TransferNotification)
+}
+
+func (c *fakePublishingClient) SetTransferPublisher(publish func(dto.TransferNotification)) {
+	c.publish = publish
+}
+
+func TestNetSvc_RegisterClient_WiresTransferPublisher_Golden(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSvc(t)
+	c := &fakePublishingClient{fakeNetClient: fakeNetClient{ref: "s3"}}
+	s.RegisterClient("s3", c)
+
+	if c.publish == nil {
+		t.Fatalf("expected transfer publisher to be set on register")
+	}
+
+	src := "/tmp/upload.bin"
+	ch, unsub := s.TransferListener(src)
+	defer unsub()
+
+	c.publish(dto.TransferNotification{Source: src, Destination: "s3://b/k", Status: dto.COMPLETE})
+
+	select {
+	case n := <-ch:
+		if n.Destination != "s3://b/k" || n.Status != dto.COMPLETE {
+			t.Fatalf("unexpected notification: %+v", n)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatalf("timeout waiting for client published update")
+	}
+}
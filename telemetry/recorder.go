@@ -0,0 +1,169 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// Recorder is an in-memory Provider for tests and debugging. It keeps every
+// ended span and measurement.
+type Recorder struct {
+	mu           sync.Mutex
+	spans        []SpanData
+	measurements []Measurement
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// SpanData is an ended span.
+type SpanData struct {
+	Name       string
+	Context    SpanContext
+	Parent     SpanContext
+	Attributes map[string]any
+	Err        error
+	Start      time.Time
+	End        time.Time
+}
+
+// MeasurementKind tells counters, histograms and gauges apart.
+type MeasurementKind string
+
+const (
+	KindCounter   MeasurementKind = "counter"
+	KindHistogram MeasurementKind = "histogram"
+	KindGauge     MeasurementKind = "gauge"
+)
+
+type Measurement struct {
+	Name       string
+	Kind       MeasurementKind
+	Value      float64
+	Attributes map[string]any
+}
+
+func (r *Recorder) Tracer() Tracer { return r }
+func (r *Recorder) Meter() Meter   { return r }
+
+// Spans returns the ended spans in end order.
+func (r *Recorder) Spans() []SpanData {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]SpanData(nil), r.spans...)
+}
+
+// Measurements returns the measurements recorded under name.
+func (r *Recorder) Measurements(name string) []Measurement {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Measurement
+	for _, m := range r.measurements {
+		if m.Name == name {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// Sum adds up the values recorded under name.
+func (r *Recorder) Sum(name string) float64 {
+	var total float64
+	for _, m := range r.Measurements(name) {
+		total += m.Value
+	}
+	return total
+}
+
+// Reset drops everything recorded so far.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = nil
+	r.measurements = nil
+}
+
+func (r *Recorder) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	parent := SpanContextFromContext(ctx)
+	sc := SpanContext{TraceID: parent.TraceID, Sampled: true}
+	if !parent.IsValid() {
+		_, _ = rand.Read(sc.TraceID[:])
+	}
+	_, _ = rand.Read(sc.SpanID[:])
+
+	span := &recordedSpan{recorder: r, data: SpanData{
+		Name:       name,
+		Context:    sc,
+		Parent:     parent,
+		Attributes: map[string]any{},
+		Start:      time.Now(),
+	}}
+	span.SetAttributes(attrs...)
+	return ContextWithSpanContext(ctx, sc), span
+}
+
+func (r *Recorder) Add(ctx context.Context, name string, value float64, attrs ...Attribute) {
+	r.measure(name, KindCounter, value, attrs)
+}
+
+func (r *Recorder) Record(ctx context.Context, name string, value float64, attrs ...Attribute) {
+	r.measure(name, KindHistogram, value, attrs)
+}
+
+func (r *Recorder) Set(ctx context.Context, name string, value float64, attrs ...Attribute) {
+	r.measure(name, KindGauge, value, attrs)
+}
+
+func (r *Recorder) measure(name string, kind MeasurementKind, value float64, attrs []Attribute) {
+	m := Measurement{Name: name, Kind: kind, Value: value, Attributes: make(map[string]any, len(attrs))}
+	for _, a := range attrs {
+		m.Attributes[a.Key] = a.Value
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.measurements = append(r.measurements, m)
+}
+
+type recordedSpan struct {
+	recorder *Recorder
+	mu       sync.Mutex
+	data     SpanData
+	ended    bool
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range attrs {
+		s.data.Attributes[a.Key] = a.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Err = err
+}
+
+func (s *recordedSpan) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.recorder.spans = append(s.recorder.spans, data)
+}
+
+func (s *recordedSpan) SpanContext() SpanContext {
+	return s.data.Context
+}
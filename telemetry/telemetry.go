@@ -0,0 +1,128 @@
+// Package telemetry defines the small tracing and metrics interfaces NetSvc
+// reports through. Adapt them to OpenTelemetry or any other backend without
+// gonetic depending on it; Noop is used when nothing is configured.
+package telemetry
+
+import "context"
+
+// Span names emitted by NetSvc.
+const (
+	SpanRequest  = "gonetic.request"
+	SpanAttempt  = "gonetic.request.attempt"
+	SpanDownload = "gonetic.download"
+)
+
+// Metric names emitted by NetSvc. Durations are seconds, throughput is bytes
+// per second.
+const (
+	MetricRequestDuration    = "gonetic.request.duration"
+	MetricAttemptDuration    = "gonetic.request.attempt.duration"
+	MetricRequestRetries     = "gonetic.request.retries"
+	MetricRequestErrors      = "gonetic.request.errors"
+	MetricDownloadBytes      = "gonetic.download.bytes"
+	MetricDownloadThroughput = "gonetic.download.throughput"
+	// Connection phases, recorded when the client reports dto.ResponseTiming
+	MetricDNSDuration     = "gonetic.request.dns.duration"
+	MetricConnectDuration = "gonetic.request.connect.duration"
+	MetricTLSDuration     = "gonetic.request.tls.duration"
+	MetricTimeToFirstByte = "gonetic.request.ttfb"
+	// MetricCircuitState is a gauge for clients implementing a circuit
+	// breaker: 0 closed, 1 half-open, 2 open. See RecordCircuitState.
+	MetricCircuitState = "gonetic.client.circuit.state"
+)
+
+// Attribute keys set on spans and metrics.
+const (
+	AttrClientRef    = "gonetic.client.ref"
+	AttrClientType   = "gonetic.client.type"
+	AttrTaskName     = "gonetic.task.name"
+	AttrAttempt      = "gonetic.request.attempt"
+	AttrRetries      = "gonetic.request.retries"
+	AttrMethod       = "http.request.method"
+	AttrHost         = "server.address"
+	AttrStatusCode   = "http.response.status_code"
+	AttrRequestSize  = "http.request.body.size"
+	AttrResponseSize = "http.response.body.size"
+	AttrOutcome      = "gonetic.outcome"
+	AttrCircuitState = "gonetic.circuit.state"
+	AttrConnReused   = "gonetic.connection.reused"
+	AttrPeerAddress  = "network.peer.address"
+	AttrProtocol     = "gonetic.network.protocol"
+)
+
+// Attribute is a key/value pair on a span or measurement. Value is a string,
+// int64, float64 or bool.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+func String(key string, value string) Attribute { return Attribute{Key: key, Value: value} }
+func Int(key string, value int) Attribute       { return Attribute{Key: key, Value: int64(value)} }
+func Int64(key string, value int64) Attribute   { return Attribute{Key: key, Value: value} }
+func Bool(key string, value bool) Attribute     { return Attribute{Key: key, Value: value} }
+
+// Provider supplies the tracer and meter NetSvc reports to.
+type Provider interface {
+	Tracer() Tracer
+	Meter() Meter
+}
+
+type Tracer interface {
+	// Start begins a span as a child of the span in ctx, if any, and returns
+	// ctx carrying the new span's SpanContext.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+	// SpanContext identifies the span for traceparent propagation. Return the
+	// zero value when the span is not recorded.
+	SpanContext() SpanContext
+}
+
+type Meter interface {
+	// Add increments a counter.
+	Add(ctx context.Context, name string, value float64, attrs ...Attribute)
+	// Record adds a histogram sample.
+	Record(ctx context.Context, name string, value float64, attrs ...Attribute)
+	// Set stores the current value of a gauge.
+	Set(ctx context.Context, name string, value float64, attrs ...Attribute)
+}
+
+// RecordCircuitState reports a circuit breaker transition for a client.
+// gonetic has no breaker of its own; wrappers that implement one call this.
+func RecordCircuitState(ctx context.Context, meter Meter, clientRef string, state string) {
+	value := map[string]float64{"closed": 0, "half_open": 1, "open": 2}[state]
+	meter.Set(ctx, MetricCircuitState, value, String(AttrClientRef, clientRef), String(AttrCircuitState, state))
+}
+
+// Noop discards everything. Its spans have no SpanContext, so no traceparent
+// header is sent unless ctx already carries one.
+var Noop Provider = noopProvider{}
+
+type noopProvider struct{}
+
+func (noopProvider) Tracer() Tracer { return noopTracer{} }
+func (noopProvider) Meter() Meter   { return noopMeter{} }
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+func (noopSpan) SpanContext() SpanContext   { return SpanContext{} }
+
+type noopMeter struct{}
+
+func (noopMeter) Add(context.Context, string, float64, ...Attribute)    {}
+func (noopMeter) Record(context.Context, string, float64, ...Attribute) {}
+func (noopMeter) Set(context.Context, string, float64, ...Attribute)    {}
@@ -0,0 +1,86 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseTraceParent_Golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		in      string
+		sampled bool
+		wantErr bool
+	}{
+		{name: "sampled", in: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sampled: true},
+		{name: "not sampled", in: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"},
+		{name: "future version extra fields", in: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", sampled: true},
+		{name: "version 00 extra fields", in: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", wantErr: true},
+		{name: "invalid version", in: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "zero trace id", in: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", wantErr: true},
+		{name: "short span id", in: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa-01", wantErr: true},
+		{name: "not hex", in: "00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01", wantErr: true},
+		{name: "empty", in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := ParseTraceParent(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if sc.Sampled != tt.sampled || !sc.IsValid() {
+				t.Fatalf("sc=%+v", sc)
+			}
+			if tt.in[:2] == "00" && sc.TraceParent() != tt.in {
+				t.Fatalf("round trip=%q want %q", sc.TraceParent(), tt.in)
+			}
+		})
+	}
+}
+
+func TestRecorder_Golden(t *testing.T) {
+	t.Parallel()
+
+	incoming, err := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRecorder()
+	ctx, parent := r.Tracer().Start(ContextWithSpanContext(context.Background(), incoming), "parent", String("k", "v"))
+	_, child := r.Tracer().Start(ctx, "child")
+	child.End()
+	parent.End()
+	parent.End()
+
+	spans := r.Spans()
+	if len(spans) != 2 || spans[0].Name != "child" || spans[1].Name != "parent" {
+		t.Fatalf("spans=%+v", spans)
+	}
+	if spans[1].Parent != incoming || spans[1].Context.TraceID != incoming.TraceID || spans[1].Attributes["k"] != "v" {
+		t.Fatalf("parent=%+v", spans[1])
+	}
+	if spans[0].Parent != spans[1].Context {
+		t.Fatalf("child parent=%+v want %+v", spans[0].Parent, spans[1].Context)
+	}
+
+	RecordCircuitState(ctx, r.Meter(), "api", "open")
+	r.Meter().Add(ctx, "count", 2)
+	r.Meter().Add(ctx, "count", 3)
+	if got := r.Sum("count"); got != 5 {
+		t.Fatalf("sum=%v want 5", got)
+	}
+	if m := r.Measurements(MetricCircuitState); len(m) != 1 || m[0].Kind != KindGauge || m[0].Value != 2 {
+		t.Fatalf("circuit=%+v", m)
+	}
+
+	r.Reset()
+	if len(r.Spans()) != 0 || r.Sum("count") != 0 {
+		t.Fatalf("not reset")
+	}
+}
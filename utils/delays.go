@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"math/rand/v2"
+	"sync"
 	"time"
 )
 
@@ -31,3 +34,83 @@ func (d ExponentialBackoff) Wait(task string, attempt int) {
 	jitter := time.Duration(rand.Float64() * float64(backoff) * 0.5)
 	time.Sleep((backoff + jitter) * time.Second)
 }
+
+// -----------------------------------------------------------------------------
+// Serialisable delay descriptors
+// -----------------------------------------------------------------------------
+
+// Built-in delay descriptor types.
+const (
+	DelayTypeConstant    = "constant"
+	DelayTypeExponential = "exponential"
+)
+
+// DelayDescriptor is the serialisable form of a RetryDelay.
+type DelayDescriptor struct {
+	Type string `json:"type" yaml:"type"`
+	// Period is the constant delay in seconds
+	Period int `json:"period,omitempty" yaml:"period,omitempty"`
+	// Params carries settings for delay types registered with RegisterDelayType
+	Params map[string]any `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// DescribableDelay is implemented by RetryDelay strategies that can be
+// persisted. Custom strategies also need RegisterDelayType to be rebuilt.
+type DescribableDelay interface {
+	RetryDelay
+	DelayDescriptor() DelayDescriptor
+}
+
+func (d ConstantDelay) DelayDescriptor() DelayDescriptor {
+	return DelayDescriptor{Type: DelayTypeConstant, Period: d.Period}
+}
+
+func (d ExponentialBackoff) DelayDescriptor() DelayDescriptor {
+	return DelayDescriptor{Type: DelayTypeExponential}
+}
+
+var delayTypes = struct {
+	sync.RWMutex
+	m map[string]func(DelayDescriptor) (RetryDelay, error)
+}{m: map[string]func(DelayDescriptor) (RetryDelay, error){
+	DelayTypeConstant: func(d DelayDescriptor) (RetryDelay, error) {
+		if d.Period < 0 {
+			return nil, errors.New("constant delay period must not be negative")
+		}
+		return ConstantDelay{Period: d.Period}, nil
+	},
+	DelayTypeExponential: func(DelayDescriptor) (RetryDelay, error) {
+		return ExponentialBackoff{}, nil
+	},
+}}
+
+// RegisterDelayType adds a builder for a custom DelayDescriptor type. It
+// panics if the type is already registered.
+func RegisterDelayType(delayType string, build func(DelayDescriptor) (RetryDelay, error)) {
+	delayTypes.Lock()
+	defer delayTypes.Unlock()
+	if _, dup := delayTypes.m[delayType]; dup {
+		panic(fmt.Sprintf("utils: delay type %s registered twice", delayType))
+	}
+	delayTypes.m[delayType] = build
+}
+
+// DescribeDelay returns the descriptor of a DescribableDelay.
+func DescribeDelay(delay RetryDelay) (DelayDescriptor, error) {
+	describable, ok := delay.(DescribableDelay)
+	if !ok {
+		return DelayDescriptor{}, fmt.Errorf("delay %T is not serialisable", delay)
+	}
+	return describable.DelayDescriptor(), nil
+}
+
+// Delay rebuilds the RetryDelay the descriptor describes.
+func (d DelayDescriptor) Delay() (RetryDelay, error) {
+	delayTypes.RLock()
+	build, ok := delayTypes.m[d.Type]
+	delayTypes.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown delay type %q", d.Type)
+	}
+	return build(d)
+}
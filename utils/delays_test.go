@@ -33,3 +33,51 @@ func TestExponentialBackoff_Wait_Golden(t *testing.T) {
 		t.Fatalf("elapsed=%v too long (unexpected)", elapsed)
 	}
 }
+
+type fixedDelay struct{ attempts int }
+
+func (d fixedDelay) Wait(task string, attempt int) {}
+func (d fixedDelay) DelayDescriptor() DelayDescriptor {
+	return DelayDescriptor{Type: "test-fixed", Params: map[string]any{"attempts": d.attempts}}
+}
+
+func TestDelayDescriptor_RoundTrip_Golden(t *testing.T) {
+	t.Parallel()
+	RegisterDelayType("test-fixed", func(d DelayDescriptor) (RetryDelay, error) {
+		attempts, _ := d.Params["attempts"].(int)
+		return fixedDelay{attempts: attempts}, nil
+	})
+
+	tests := []struct {
+		name    string
+		delay   RetryDelay
+		wantErr bool
+	}{
+		{name: "constant", delay: ConstantDelay{Period: 3}},
+		{name: "exponential", delay: ExponentialBackoff{}},
+		{name: "custom", delay: fixedDelay{attempts: 2}},
+		{name: "not describable", delay: struct{ RetryDelay }{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			desc, err := DescribeDelay(tt.delay)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := desc.Delay()
+			if err != nil || got != tt.delay {
+				t.Fatalf("got=%#v err=%v want %#v", got, err, tt.delay)
+			}
+		})
+	}
+
+	if _, err := (DelayDescriptor{Type: "unknown"}).Delay(); err == nil {
+		t.Fatalf("expected error for unknown delay type")
+	}
+}
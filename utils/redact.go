@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces secrets removed by a Redactor.
+const RedactedValue = "REDACTED"
+
+// Redactor masks secrets in headers, URL query parameters and JSON or form
+// bodies. Names are compared case-insensitively.
+type Redactor struct {
+	Headers     []string `json:"headers,omitempty" yaml:"headers,omitempty" mapstructure:"headers"`
+	QueryParams []string `json:"query_params,omitempty" yaml:"query_params,omitempty" mapstructure:"query_params"`
+	BodyKeys    []string `json:"body_keys,omitempty" yaml:"body_keys,omitempty" mapstructure:"body_keys"`
+}
+
+// DefaultRedactor masks credentials, cookies, signatures and common secret
+// field names.
+func DefaultRedactor() Redactor {
+	return Redactor{
+		Headers: []string{
+			"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie",
+			"X-Api-Key", "X-Amz-Security-Token", "X-Amz-Server-Side-Encryption-Customer-Key",
+		},
+		QueryParams: []string{
+			"access_token", "api_key", "apikey", "token", "signature",
+			"X-Amz-Signature", "X-Amz-Credential", "X-Amz-Security-Token",
+		},
+		BodyKeys: []string{
+			"password", "secret", "client_secret", "token", "access_token",
+			"refresh_token", "id_token", "api_key",
+		},
+	}
+}
+
+// IsZero reports whether no headers, query parameters or body keys are set.
+func (r Redactor) IsZero() bool {
+	return len(r.Headers) == 0 && len(r.QueryParams) == 0 && len(r.BodyKeys) == 0
+}
+
+// Header returns a copy of h with sensitive values replaced.
+func (r Redactor) Header(h http.Header) http.Header {
+	if h == nil {
+		return nil
+	}
+	out := h.Clone()
+	for _, name := range r.Headers {
+		if _, ok := out[http.CanonicalHeaderKey(name)]; ok {
+			out.Set(name, RedactedValue)
+		}
+	}
+	return out
+}
+
+// HeaderMap is Header for map[string]string headers.
+func (r Redactor) HeaderMap(h map[string]string) map[string]string {
+	if h == nil {
+		return nil
+	}
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if containsFold(r.Headers, k) {
+			v = RedactedValue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// URL replaces sensitive query parameter values. Unparseable input is
+// returned unchanged.
+func (r Redactor) URL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	q := u.Query()
+	changed := false
+	for k := range q {
+		if containsFold(r.QueryParams, k) {
+			q.Set(k, RedactedValue)
+			changed = true
+		}
+	}
+	if u.User != nil {
+		u.User = url.User(u.User.Username())
+		changed = true
+	}
+	if !changed {
+		return raw
+	}
+	if u.RawQuery != "" {
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
+
+// urlPattern finds absolute URLs embedded in free text, stopping at quotes
+// so the quoted URL in a *url.Error message is matched on its own.
+var urlPattern = regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://[^\s"'<>]+`)
+
+// Error returns err's message with every URL in it passed through URL, so
+// errors such as *url.Error do not leak credentials or signed parameters.
+func (r Redactor) Error(err error) string {
+	if err == nil {
+		return ""
+	}
+	return urlPattern.ReplaceAllStringFunc(err.Error(), r.URL)
+}
+
+// Body replaces sensitive fields in a JSON document, at any depth, or in a
+// form-urlencoded body. Other content is returned unchanged.
+func (r Redactor) Body(body []byte) []byte {
+	if len(body) == 0 || len(r.BodyKeys) == 0 {
+		return body
+	}
+	var doc any
+	if err := json.Unmarshal(body, &doc); err == nil {
+		if !r.redactJSON(doc) {
+			return body
+		}
+		out, err := json.Marshal(doc)
+		if err != nil {
+			return body
+		}
+		return out
+	}
+	if form, err := url.ParseQuery(string(body)); err == nil && strings.Contains(string(body), "=") {
+		changed := false
+		for k := range form {
+			if containsFold(r.BodyKeys, k) {
+				form.Set(k, RedactedValue)
+				changed = true
+			}
+		}
+		if changed {
+			return []byte(form.Encode())
+		}
+	}
+	return body
+}
+
+func (r Redactor) redactJSON(v any) bool {
+	changed := false
+	switch node := v.(type) {
+	case map[string]any:
+		for k, child := range node {
+			if containsFold(r.BodyKeys, k) {
+				node[k] = RedactedValue
+				changed = true
+				continue
+			}
+			changed = r.redactJSON(child) || changed
+		}
+	case []any:
+		for _, child := range node {
+			changed = r.redactJSON(child) || changed
+		}
+	}
+	return changed
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}